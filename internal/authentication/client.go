@@ -2,14 +2,24 @@ package authentication
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net/http"
+	"sync/atomic"
 
 	"github.com/zmb3/spotify/v2"
 	spotifyauth "github.com/zmb3/spotify/v2/auth"
 	"golang.org/x/oauth2"
 )
 
-func NewSpotifyClient(ctx context.Context, tok *oauth2.Token, authenticator *spotifyauth.Authenticator) *spotify.Client {
+// AuthExpiredMsg is sent when Spotify stops accepting the login, the token couldn't be
+// refreshed or requests are rejected as unauthorized
+type AuthExpiredMsg struct {
+	Error error
+}
+
+// NewSpotifyClient creates a client for the token, onExpired is called once the login stops working
+func NewSpotifyClient(ctx context.Context, tok *oauth2.Token, authenticator *spotifyauth.Authenticator, onExpired func(error)) *spotify.Client {
 	if tok == nil {
 		log.Println("NewSpotifyClient: nil oauth2.Token")
 		return nil
@@ -20,5 +30,63 @@ func NewSpotifyClient(ctx context.Context, tok *oauth2.Token, authenticator *spo
 		return nil
 	}
 
-	return spotify.New(authenticator.Client(ctx, tok), spotify.WithRetry(true))
+	httpClient := authenticator.Client(ctx, tok)
+	// The oauth2 transport stays outermost, spotify.Client.Token reads the token through it
+	if transport, ok := httpClient.Transport.(*oauth2.Transport); ok {
+		watcher := &expiryWatcher{onExpired: onExpired}
+		base := transport.Base
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		transport.Source = watchedSource{next: transport.Source, watcher: watcher}
+		transport.Base = watchedTransport{next: base, watcher: watcher}
+	}
+	return spotify.New(httpClient, spotify.WithRetry(true))
+}
+
+// expiryWatcher reports the first failure caused by the login, and again after a request succeeded
+type expiryWatcher struct {
+	onExpired func(error)
+	expired   atomic.Bool
+}
+
+func (w *expiryWatcher) fail(err error) {
+	if !w.expired.Swap(true) {
+		log.Printf("Authentication: Login expired: %v", err)
+		if w.onExpired != nil {
+			w.onExpired(err)
+		}
+	}
+}
+
+// watchedSource reports tokens that couldn't be refreshed
+type watchedSource struct {
+	next    oauth2.TokenSource
+	watcher *expiryWatcher
+}
+
+func (s watchedSource) Token() (*oauth2.Token, error) {
+	token, err := s.next.Token()
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		s.watcher.fail(err)
+	}
+	return token, err
+}
+
+// watchedTransport reports requests Spotify rejected as unauthorized
+type watchedTransport struct {
+	next    http.RoundTripper
+	watcher *expiryWatcher
+}
+
+func (t watchedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	switch {
+	case err == nil && resp.StatusCode == http.StatusUnauthorized:
+		t.watcher.fail(errors.New("Spotify rejected the login as unauthorized"))
+	case err == nil && resp.StatusCode < 400:
+		t.watcher.expired.Store(false)
+	}
+	return resp, err
 }
@@ -18,6 +18,8 @@ type credentials struct {
 	TokenType    string    `json:"token_type"`
 	RefreshToken string    `json:"refresh_token"`
 	Expiry       time.Time `json:"expiry"`
+	// Space separated scopes the login granted, missing from logins saved by older versions
+	Scope string `json:"scope,omitempty"`
 }
 
 // CredentialManager handles the storage and retrieval of authentication credentials and client ID
@@ -47,6 +49,9 @@ func (cm *credentialManager) saveToken(token *oauth2.Token) error {
 		RefreshToken: token.RefreshToken,
 		Expiry:       token.Expiry,
 	}
+	if scope, ok := token.Extra("scope").(string); ok {
+		creds.Scope = scope
+	}
 
 	data, err := json.MarshalIndent(creds, "", "  ")
 	if err != nil {
@@ -78,12 +83,14 @@ func (cm *credentialManager) loadToken() (*oauth2.Token, error) {
 		return nil, fmt.Errorf("failed to unmarshal credentials: %w", err)
 	}
 
-	return &oauth2.Token{
+	token := &oauth2.Token{
 		AccessToken:  creds.AccessToken,
 		TokenType:    creds.TokenType,
 		RefreshToken: creds.RefreshToken,
 		Expiry:       creds.Expiry,
-	}, nil
+	}
+	// Kept with the token like Spotify returns it, so saving the token again keeps the scopes
+	return token.WithExtra(map[string]any{"scope": creds.Scope}), nil
 }
 
 func (cm *credentialManager) saveClientID(clientID string) error {
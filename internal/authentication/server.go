@@ -9,7 +9,9 @@ import (
 	"net/http"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dietzy1/termify/internal/config"
+	"github.com/dietzy1/termify/internal/control"
 )
 
 //go:embed login_complete.html
@@ -22,13 +24,13 @@ type server struct {
 	service *service
 }
 
-func NewServer(c *config.Config, s *service) *server {
+func NewServer(c *config.Config, s *service, events *control.Broker) *server {
 
 	mux := http.NewServeMux()
 	srv := &server{
 		config: c,
 		server: &http.Server{
-			Addr:    c.GetPort(),
+			Addr:    c.GetListenAddress(),
 			Handler: mux,
 		},
 		service: s,
@@ -37,6 +39,13 @@ func NewServer(c *config.Config, s *service) *server {
 	mux.HandleFunc("/callback", srv.callbackHandler)
 	mux.HandleFunc("/health", srv.healthHandler)
 
+	if c.IsAPIEnabled() {
+		log.Println("Serving the HTTP API under /api/")
+		mux.Handle("/api/", control.NewHTTPHandler(c.GetAPIToken(), programSender{service: s}, events))
+		// Event streams stay open until the broker closes them
+		srv.server.RegisterOnShutdown(events.Close)
+	}
+
 	return srv
 }
 
@@ -44,7 +53,7 @@ func (s *server) ListenAndServe(ctx context.Context) error {
 	errChan := make(chan error, 1)
 	log.Println("Starting authentication service")
 	go func() {
-		log.Printf("Starting callback server on %s", s.config.GetPort())
+		log.Printf("Starting callback server on %s", s.config.GetListenAddress())
 		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			errChan <- fmt.Errorf("server error: %w", err)
 		}
@@ -107,7 +116,7 @@ func (s *server) callbackHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("State verification successful")
 
-	client := NewSpotifyClient(r.Context(), tok, s.service.authenticator)
+	client := NewSpotifyClient(r.Context(), tok, s.service.authenticator, s.service.authExpired)
 	s.service.program.Send(
 		LoginClientMsg{
 			Client: client,
@@ -137,6 +146,21 @@ func (s *server) callbackHandler(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// programSender forwards API commands to the TUI once it started
+type programSender struct {
+	service *service
+}
+
+func (p programSender) Send(msg tea.Msg) {
+	if p.service.program == nil {
+		if command, ok := msg.(control.CommandMsg); ok {
+			command.Reply(control.Response{Error: "Termify is still starting"})
+		}
+		return
+	}
+	p.service.program.Send(msg)
+}
+
 func (s *server) healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	fmt.Fprintln(w, "OK")
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"slices"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dietzy1/termify/internal/config"
@@ -15,6 +17,8 @@ import (
 
 type LoginUrlMsg struct {
 	Url string
+	// Why logging in again is needed, empty for a first login
+	Reason string
 }
 
 type LoginClientMsg struct {
@@ -54,6 +58,15 @@ func (s *service) SetTeaProgram(p *tea.Program) {
 	s.program = p
 }
 
+// authExpired tells the program the login stopped working. It doesn't wait for the program,
+// which may be the one making the request.
+func (s *service) authExpired(err error) {
+	if s.program == nil {
+		return
+	}
+	go s.program.Send(AuthExpiredMsg{Error: err})
+}
+
 func (s *service) StartPkceAuth(clientID string) tea.Cmd {
 	log.Println("Token is invalid or not found, generating new URL")
 	url := s.generateUrl(clientID)
@@ -82,12 +95,23 @@ func (s *service) StartStoredTokenAuth(ctx context.Context) tea.Cmd {
 
 	s.setAuthenticator(clientID)
 
+	// A login from before a feature was added lacks its scopes, Spotify would reject its requests
+	if token != nil {
+		if missing := missingScopes(token); len(missing) > 0 {
+			log.Printf("Token lacks scopes %s, logging in again", strings.Join(missing, " "))
+			url := s.generateUrl(clientID)
+			return func() tea.Msg {
+				return LoginUrlMsg{Url: url, Reason: "Termify needs new permissions since you last logged in."}
+			}
+		}
+	}
+
 	// Exit early if we have a valid token
 	if token != nil && token.Valid() {
 		log.Println("Token is valid, returning client")
 
 		return func() tea.Msg {
-			return LoginClientMsg{Client: NewSpotifyClient(ctx, token, s.authenticator)}
+			return LoginClientMsg{Client: NewSpotifyClient(ctx, token, s.authenticator, s.authExpired)}
 		}
 	}
 
@@ -99,12 +123,13 @@ func (s *service) StartStoredTokenAuth(ctx context.Context) tea.Cmd {
 		refreshedToken, err := s.authenticator.RefreshToken(ctx, token)
 		if err != nil {
 			log.Println("Failed to refresh token:", err)
+			s.authExpired(err)
 		}
 		if refreshedToken != nil {
 			log.Println("Token refreshed successfully")
 			log.Println("Refreshed token:", refreshedToken)
 			return func() tea.Msg {
-				return LoginClientMsg{Client: NewSpotifyClient(ctx, refreshedToken, s.authenticator)}
+				return LoginClientMsg{Client: NewSpotifyClient(ctx, refreshedToken, s.authenticator, s.authExpired)}
 			}
 		}
 	}
@@ -151,17 +176,41 @@ func (s *service) setAuthenticator(clientID string) {
 	s.authenticator = spotifyauth.New(
 		spotifyauth.WithClientID(clientID),
 		spotifyauth.WithRedirectURL(fmt.Sprintf("http://127.0.0.1%s/callback", s.config.Server.Port)),
-		spotifyauth.WithScopes(
-			spotifyauth.ScopeUserReadPrivate,
-			spotifyauth.ScopePlaylistReadCollaborative,
-			spotifyauth.ScopePlaylistReadPrivate,
-			spotifyauth.ScopeUserReadPlaybackState,
-			spotifyauth.ScopeUserModifyPlaybackState,
-			spotifyauth.ScopeUserLibraryRead,
-		),
+		spotifyauth.WithScopes(scopes...),
 	)
 }
 
+// scopes are the permissions Termify logs in with
+var scopes = []string{
+	spotifyauth.ScopeUserReadPrivate,
+	spotifyauth.ScopePlaylistReadCollaborative,
+	spotifyauth.ScopePlaylistReadPrivate,
+	spotifyauth.ScopeUserReadPlaybackState,
+	spotifyauth.ScopeUserModifyPlaybackState,
+	spotifyauth.ScopeUserLibraryRead,
+	spotifyauth.ScopeUserReadRecentlyPlayed,
+	spotifyauth.ScopeUserTopRead,
+	spotifyauth.ScopeUserFollowRead,
+	spotifyauth.ScopeUserFollowModify,
+	spotifyauth.ScopeUserLibraryModify,
+	spotifyauth.ScopePlaylistModifyPublic,
+	spotifyauth.ScopePlaylistModifyPrivate,
+}
+
+// missingScopes returns the scopes the token wasn't granted
+func missingScopes(token *oauth2.Token) []string {
+	granted, _ := token.Extra("scope").(string)
+	grantedScopes := strings.Fields(granted)
+
+	var missing []string
+	for _, scope := range scopes {
+		if !slices.Contains(grantedScopes, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
 func (s *service) SaveToken(token *oauth2.Token) error {
 	log.Println("Saving token")
 
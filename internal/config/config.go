@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -28,16 +29,121 @@ type Config struct {
 		ConnectClient string `yaml:"connect_client"`
 	} `yaml:"spotify"`
 
+	// Autoplay configuration
+	Autoplay struct {
+		// What to play once the current view runs out of tracks
+		Fallback string `yaml:"fallback"`
+		// Playlist ID or URI to continue from when fallback is "playlist"
+		FallbackPlaylist string `yaml:"fallback_playlist"`
+	} `yaml:"autoplay"`
+
+	// Track table columns per view, in display order
+	Columns struct {
+		Playlist []string `yaml:"playlist"`
+		Album    []string `yaml:"album"`
+		Artist   []string `yaml:"artist"`
+	} `yaml:"columns"`
+
+	// Clipboard configuration
+	Clipboard struct {
+		// How copied text reaches the clipboard: auto, system or osc52
+		Method string `yaml:"method"`
+	} `yaml:"clipboard"`
+
+	// Control socket configuration
+	Control struct {
+		// Whether to accept commands on the control socket in the config directory
+		Enabled bool `yaml:"enabled"`
+	} `yaml:"control"`
+
+	// HTTP API configuration
+	API struct {
+		// Whether to serve the JSON API on the server port
+		Enabled bool `yaml:"enabled"`
+		// Token clients send as a bearer token
+		Token string `yaml:"token"`
+	} `yaml:"api"`
+
+	// MPRIS configuration
+	MPRIS struct {
+		// Whether to appear as a media player on the D-Bus session bus
+		Enabled bool `yaml:"enabled"`
+	} `yaml:"mpris"`
+
+	// Now playing output for status bars
+	NowPlaying struct {
+		// File or named pipe the now playing line is written to, empty to turn it off
+		Output string `yaml:"output"`
+		// Go text/template the line is rendered with
+		Format string `yaml:"format"`
+		// Whether to write JSON for waybar's custom module instead of plain text
+		Waybar bool `yaml:"waybar"`
+	} `yaml:"now_playing"`
+
+	// Scrobbling configuration
+	Scrobble Scrobble `yaml:"scrobble"`
+
+	// Commands and webhooks run on events, by event name
+	Hooks map[string][]Hook `yaml:"hooks"`
+
 	// Logging configuration
 	Logging struct {
 		// Whether logging is enabled
 		Enabled bool `yaml:"enabled"`
 	} `yaml:"logging"`
 
+	// What to open when Termify starts, only taken from the command line
+	Launch struct {
+		// Spotify links or URIs given as positional arguments
+		Items []string
+		// Whether to start playing the items
+		Play bool
+		// Name of the Spotify Connect device to play on
+		Device string
+	} `yaml:"-"`
+
 	// Internal configuration
 	ConfigPath string `yaml:"-"` // Not stored in config file
 }
 
+// Scrobble is where and as whom listens are submitted
+type Scrobble struct {
+	// Whether to submit listens
+	Enabled bool `yaml:"enabled"`
+	// API the listens are submitted with, listenbrainz or lastfm
+	Service string `yaml:"service"`
+	// API root of a compatible server, the official one of the service when empty
+	URL string `yaml:"url"`
+	// ListenBrainz user token
+	Token string `yaml:"token"`
+	// Last.fm API account
+	APIKey    string `yaml:"api_key"`
+	APISecret string `yaml:"api_secret"`
+	// Last.fm session key, or the username and password to get one with
+	SessionKey string `yaml:"session_key"`
+	Username   string `yaml:"username"`
+	Password   string `yaml:"password"`
+}
+
+// Hook is a shell command or a webhook run when an event happens
+type Hook struct {
+	// Shell command, the track and playback are passed in TERMIFY_* environment variables
+	Command string `yaml:"command"`
+	// URL a JSON description of the event is POSTed to
+	URL string `yaml:"url"`
+	// How long the hook may run before it is stopped, 10s when unset
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// SocketFileName is the name of the control socket in the config directory
+const SocketFileName = "termify.sock"
+
+// DefaultNowPlayingFormat shows the artists and title while a track is loaded
+const DefaultNowPlayingFormat = "{{if .Title}}{{.Artist}} – {{.Title}}{{end}}"
+
+// defaultColumns is the track table layout used when a view has no columns configured
+var defaultColumns = []string{"number", "title", "artist", "album", "duration"}
+
 // DefaultConfig returns a config with default values
 func defaultConfig() *Config {
 	cfg := &Config{}
@@ -46,6 +152,15 @@ func defaultConfig() *Config {
 
 	cfg.Server.Port = "8080"
 	cfg.Spotify.ConnectClient = "default"
+	cfg.Autoplay.Fallback = "stop"
+	cfg.Columns.Playlist = defaultColumns
+	cfg.Columns.Album = defaultColumns
+	cfg.Columns.Artist = defaultColumns
+	cfg.Clipboard.Method = "auto"
+	cfg.Control.Enabled = true
+	cfg.MPRIS.Enabled = true
+	cfg.NowPlaying.Format = DefaultNowPlayingFormat
+	cfg.Scrobble.Service = "listenbrainz"
 	cfg.Logging.Enabled = true
 
 	return cfg
@@ -62,8 +177,10 @@ func LoadConfig() (*Config, error) {
 		clientID       = flag.String("client-id", "", "Spotify client ID")
 		connectClient  = flag.String("connect-client", "", "Spotify connect client to use")
 		loggingEnabled = flag.Bool("logging", true, "Enable or disable logging")
+		play           = flag.Bool("play", false, "Start playing the items given as arguments")
+		device         = flag.String("device", "", "Name of the Spotify Connect device to play on")
 	)
-	flag.Parse()
+	cfg.Launch.Items = parseArgs(os.Args[1:])
 
 	// Determine config file path
 	userConfigDir, err := os.UserHomeDir()
@@ -108,6 +225,60 @@ func LoadConfig() (*Config, error) {
 	if envConnectClient := os.Getenv("TERMIFY_CONNECT_CLIENT"); envConnectClient != "" {
 		cfg.Spotify.ConnectClient = envConnectClient
 	}
+	if envAutoplayFallback := os.Getenv("TERMIFY_AUTOPLAY_FALLBACK"); envAutoplayFallback != "" {
+		cfg.Autoplay.Fallback = envAutoplayFallback
+	}
+	if envClipboardMethod := os.Getenv("TERMIFY_CLIPBOARD_METHOD"); envClipboardMethod != "" {
+		cfg.Clipboard.Method = envClipboardMethod
+	}
+	if envControlEnabled := os.Getenv("TERMIFY_CONTROL_ENABLED"); envControlEnabled != "" {
+		cfg.Control.Enabled = envControlEnabled == "true" || envControlEnabled == "1"
+	}
+	if envAPIEnabled := os.Getenv("TERMIFY_API_ENABLED"); envAPIEnabled != "" {
+		cfg.API.Enabled = envAPIEnabled == "true" || envAPIEnabled == "1"
+	}
+	if envAPIToken := os.Getenv("TERMIFY_API_TOKEN"); envAPIToken != "" {
+		cfg.API.Token = envAPIToken
+	}
+	if envMPRISEnabled := os.Getenv("TERMIFY_MPRIS_ENABLED"); envMPRISEnabled != "" {
+		cfg.MPRIS.Enabled = envMPRISEnabled == "true" || envMPRISEnabled == "1"
+	}
+	if envNowPlayingOutput := os.Getenv("TERMIFY_NOW_PLAYING_OUTPUT"); envNowPlayingOutput != "" {
+		cfg.NowPlaying.Output = envNowPlayingOutput
+	}
+	if envNowPlayingFormat := os.Getenv("TERMIFY_NOW_PLAYING_FORMAT"); envNowPlayingFormat != "" {
+		cfg.NowPlaying.Format = envNowPlayingFormat
+	}
+	if envNowPlayingWaybar := os.Getenv("TERMIFY_NOW_PLAYING_WAYBAR"); envNowPlayingWaybar != "" {
+		cfg.NowPlaying.Waybar = envNowPlayingWaybar == "true" || envNowPlayingWaybar == "1"
+	}
+	if envScrobbleEnabled := os.Getenv("TERMIFY_SCROBBLE_ENABLED"); envScrobbleEnabled != "" {
+		cfg.Scrobble.Enabled = envScrobbleEnabled == "true" || envScrobbleEnabled == "1"
+	}
+	if envScrobbleService := os.Getenv("TERMIFY_SCROBBLE_SERVICE"); envScrobbleService != "" {
+		cfg.Scrobble.Service = envScrobbleService
+	}
+	if envScrobbleURL := os.Getenv("TERMIFY_SCROBBLE_URL"); envScrobbleURL != "" {
+		cfg.Scrobble.URL = envScrobbleURL
+	}
+	if envScrobbleToken := os.Getenv("TERMIFY_SCROBBLE_TOKEN"); envScrobbleToken != "" {
+		cfg.Scrobble.Token = envScrobbleToken
+	}
+	if envScrobbleAPIKey := os.Getenv("TERMIFY_SCROBBLE_API_KEY"); envScrobbleAPIKey != "" {
+		cfg.Scrobble.APIKey = envScrobbleAPIKey
+	}
+	if envScrobbleAPISecret := os.Getenv("TERMIFY_SCROBBLE_API_SECRET"); envScrobbleAPISecret != "" {
+		cfg.Scrobble.APISecret = envScrobbleAPISecret
+	}
+	if envScrobbleSessionKey := os.Getenv("TERMIFY_SCROBBLE_SESSION_KEY"); envScrobbleSessionKey != "" {
+		cfg.Scrobble.SessionKey = envScrobbleSessionKey
+	}
+	if envScrobbleUsername := os.Getenv("TERMIFY_SCROBBLE_USERNAME"); envScrobbleUsername != "" {
+		cfg.Scrobble.Username = envScrobbleUsername
+	}
+	if envScrobblePassword := os.Getenv("TERMIFY_SCROBBLE_PASSWORD"); envScrobblePassword != "" {
+		cfg.Scrobble.Password = envScrobblePassword
+	}
 	if envLoggingEnabled := os.Getenv("TERMIFY_LOGGING_ENABLED"); envLoggingEnabled != "" {
 		cfg.Logging.Enabled = envLoggingEnabled == "true" || envLoggingEnabled == "1"
 	}
@@ -125,6 +296,8 @@ func LoadConfig() (*Config, error) {
 	if *loggingEnabled {
 		cfg.Logging.Enabled = *loggingEnabled
 	}
+	cfg.Launch.Play = *play
+	cfg.Launch.Device = *device
 
 	// Ensure port has colon prefix
 	if cfg.Server.Port != "" && cfg.Server.Port[0] != ':' {
@@ -138,8 +311,43 @@ func LoadConfig() (*Config, error) {
 	log.Println("Spotify:")
 	log.Printf("  Client ID: %s", cfg.Spotify.ClientID)
 	log.Printf("  Connect client: %s", cfg.Spotify.ConnectClient)
+	log.Println("Autoplay:")
+	log.Printf("  Fallback: %s", cfg.Autoplay.Fallback)
+	log.Printf("  Fallback playlist: %s", cfg.Autoplay.FallbackPlaylist)
+	log.Println("Columns:")
+	log.Printf("  Playlist: %v", cfg.Columns.Playlist)
+	log.Printf("  Album: %v", cfg.Columns.Album)
+	log.Printf("  Artist: %v", cfg.Columns.Artist)
+	log.Println("Clipboard:")
+	log.Printf("  Method: %s", cfg.Clipboard.Method)
+	log.Println("Control:")
+	log.Printf("  Enabled: %t", cfg.Control.Enabled)
+	log.Println("API:")
+	log.Printf("  Enabled: %t", cfg.API.Enabled)
+	log.Printf("  Token set: %t", cfg.API.Token != "")
+	log.Println("MPRIS:")
+	log.Printf("  Enabled: %t", cfg.MPRIS.Enabled)
+	log.Println("Now Playing:")
+	log.Printf("  Output: %s", cfg.NowPlaying.Output)
+	log.Printf("  Format: %s", cfg.NowPlaying.Format)
+	log.Printf("  Waybar: %t", cfg.NowPlaying.Waybar)
+	log.Println("Scrobble:")
+	log.Printf("  Enabled: %t", cfg.Scrobble.Enabled)
+	log.Printf("  Service: %s", cfg.Scrobble.Service)
+	log.Printf("  URL: %s", cfg.Scrobble.URL)
+	log.Printf("  Credentials set: %t", cfg.Scrobble.Token != "" || cfg.Scrobble.APIKey != "")
+	log.Println("Hooks:")
+	for _, event := range HookEvents {
+		if hooks := cfg.Hooks[event]; len(hooks) > 0 {
+			log.Printf("  %s: %d", event, len(hooks))
+		}
+	}
 	log.Println("Logging:")
 	log.Printf("  Enabled: %t", cfg.Logging.Enabled)
+	log.Println("Launch:")
+	log.Printf("  Items: %v", cfg.Launch.Items)
+	log.Printf("  Play: %t", cfg.Launch.Play)
+	log.Printf("  Device: %s", cfg.Launch.Device)
 	log.Println("Config Path:")
 	log.Printf("  Path: %s", cfg.ConfigPath)
 	log.Println("============================")
@@ -151,6 +359,43 @@ func LoadConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// ConfigDir returns the directory of the config file at path, as LoadConfig resolves it,
+// or the default config directory when path is empty
+func ConfigDir(path string) (string, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user config directory: %w", err)
+		}
+		return filepath.Join(home, "termify"), nil
+	}
+	if filepath.Base(path) == "config.yaml" {
+		return filepath.Dir(path), nil
+	}
+	return path, nil
+}
+
+// parseArgs parses the command-line flags and returns the positional arguments. Unlike flag.Parse
+// it accepts flags after the positional arguments, as in `termify spotify:playlist:ID --play`.
+func parseArgs(args []string) []string {
+	var positional []string
+	for {
+		// The default flag set exits on invalid flags
+		flag.CommandLine.Parse(args)
+		rest := flag.Args()
+		if len(rest) == 0 {
+			return positional
+		}
+
+		// Everything after a "--" terminator is positional
+		if parsed := len(args) - len(rest); parsed > 0 && args[parsed-1] == "--" {
+			return append(positional, rest...)
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
 // loadFromFile loads configuration from a YAML file
 func (c *Config) loadFromFile(path string) error {
 	data, err := os.ReadFile(path)
@@ -197,6 +442,39 @@ func (c *Config) GetPort() string {
 	return c.Server.Port
 }
 
+// GetListenAddress returns the address the server listens on. It only listens on
+// loopback, the callback comes from the local browser and the API controls playback.
+func (c *Config) GetListenAddress() string {
+	return "127.0.0.1" + c.Server.Port
+}
+
+// GetAutoplayFallback returns the configured autoplay fallback strategy
+func (c *Config) GetAutoplayFallback() string {
+	return c.Autoplay.Fallback
+}
+
+// GetFallbackPlaylist returns the playlist used by the "playlist" autoplay fallback
+func (c *Config) GetFallbackPlaylist() string {
+	return c.Autoplay.FallbackPlaylist
+}
+
+// GetColumns returns the track table columns for a view: playlist, album or artist
+func (c *Config) GetColumns(view string) []string {
+	switch view {
+	case "album":
+		return c.Columns.Album
+	case "artist":
+		return c.Columns.Artist
+	default:
+		return c.Columns.Playlist
+	}
+}
+
+// GetClipboardMethod returns how copied text reaches the clipboard: auto, system or osc52
+func (c *Config) GetClipboardMethod() string {
+	return c.Clipboard.Method
+}
+
 // IsLoggingEnabled returns whether logging is enabled
 func (c *Config) IsLoggingEnabled() bool {
 	return c.Logging.Enabled
@@ -206,3 +484,97 @@ func (c *Config) IsLoggingEnabled() bool {
 func (c *Config) GetLogFilePath() string {
 	return c.ConfigPath + "/termify.log"
 }
+
+// GetSessionFilePath returns the path to the saved session
+func (c *Config) GetSessionFilePath() string {
+	return c.ConfigPath + "/session.json"
+}
+
+// IsControlEnabled returns whether to accept commands on the control socket
+func (c *Config) IsControlEnabled() bool {
+	return c.Control.Enabled
+}
+
+// GetControlSocketPath returns the path to the control socket
+func (c *Config) GetControlSocketPath() string {
+	return filepath.Join(c.ConfigPath, SocketFileName)
+}
+
+// IsAPIEnabled returns whether to serve the HTTP API
+func (c *Config) IsAPIEnabled() bool {
+	return c.API.Enabled
+}
+
+// GetAPIToken returns the token HTTP API clients must send
+func (c *Config) GetAPIToken() string {
+	return c.API.Token
+}
+
+// IsMPRISEnabled returns whether to appear as a media player on the D-Bus session bus
+func (c *Config) IsMPRISEnabled() bool {
+	return c.MPRIS.Enabled
+}
+
+// GetNowPlayingOutput returns the file or named pipe to write the now playing line to,
+// relative paths are in the config directory. It is empty when the output is off.
+func (c *Config) GetNowPlayingOutput() string {
+	if c.NowPlaying.Output == "" || filepath.IsAbs(c.NowPlaying.Output) {
+		return c.NowPlaying.Output
+	}
+	return filepath.Join(c.ConfigPath, c.NowPlaying.Output)
+}
+
+// GetNowPlayingFormat returns the template the now playing line is rendered with
+func (c *Config) GetNowPlayingFormat() string {
+	return c.NowPlaying.Format
+}
+
+// IsNowPlayingWaybar returns whether to write the now playing line as waybar JSON
+func (c *Config) IsNowPlayingWaybar() bool {
+	return c.NowPlaying.Waybar
+}
+
+// IsScrobbleEnabled returns whether to submit listens
+func (c *Config) IsScrobbleEnabled() bool {
+	return c.Scrobble.Enabled
+}
+
+// GetScrobble returns the scrobbling service, endpoint and credentials
+func (c *Config) GetScrobble() Scrobble {
+	return c.Scrobble
+}
+
+// GetScrobbleQueueFilePath returns the path to the listens waiting to be submitted
+func (c *Config) GetScrobbleQueueFilePath() string {
+	return c.ConfigPath + "/scrobble_queue.json"
+}
+
+// GetHooks returns the hooks by event name
+func (c *Config) GetHooks() map[string][]Hook {
+	return c.Hooks
+}
+
+// GetLaunchItems returns the Spotify links or URIs given on the command line
+func (c *Config) GetLaunchItems() []string {
+	return c.Launch.Items
+}
+
+// ShouldPlayOnLaunch returns whether to start playing the launch items
+func (c *Config) ShouldPlayOnLaunch() bool {
+	return c.Launch.Play
+}
+
+// GetLaunchDevice returns the name of the device to play on at launch
+func (c *Config) GetLaunchDevice() string {
+	return c.Launch.Device
+}
+
+// GetDaemonSessionFilePath returns the path to the queue saved by the daemon
+func (c *Config) GetDaemonSessionFilePath() string {
+	return c.ConfigPath + "/daemon_session.json"
+}
+
+// GetSearchHistoryFilePath returns the path to the saved search history
+func (c *Config) GetSearchHistoryFilePath() string {
+	return c.ConfigPath + "/search_history.json"
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -26,5 +27,97 @@ func (c *Config) validate() error {
 		return fmt.Errorf("invalid Spotify Client ID: must be 32 characters long, got %d", len(c.Spotify.ClientID))
 	}
 
+	// Validate Autoplay fallback
+	switch c.Autoplay.Fallback {
+	case "stop", "loop", "artist", "album", "liked":
+	case "playlist":
+		if c.Autoplay.FallbackPlaylist == "" {
+			return errors.New("autoplay fallback 'playlist' requires autoplay.fallback_playlist to be set")
+		}
+	default:
+		return fmt.Errorf("invalid autoplay fallback '%s': must be one of stop, loop, artist, album, liked, playlist", c.Autoplay.Fallback)
+	}
+
+	// Validate clipboard method
+	switch c.Clipboard.Method {
+	case "auto", "system", "osc52":
+	default:
+		return fmt.Errorf("invalid clipboard method '%s': must be one of auto, system, osc52", c.Clipboard.Method)
+	}
+
+	// Validate API token, the API controls playback for anyone who can reach the port
+	if c.API.Enabled && len(c.API.Token) < minAPITokenLength {
+		return fmt.Errorf("api.token must be at least %d characters when the API is enabled", minAPITokenLength)
+	}
+
+	// Validate scrobbling, the credentials needed depend on the service
+	if c.Scrobble.Enabled {
+		switch c.Scrobble.Service {
+		case "listenbrainz":
+			if c.Scrobble.Token == "" {
+				return errors.New("scrobble.token must be set to scrobble to ListenBrainz")
+			}
+		case "lastfm":
+			if c.Scrobble.APIKey == "" || c.Scrobble.APISecret == "" {
+				return errors.New("scrobble.api_key and scrobble.api_secret must be set to scrobble to Last.fm")
+			}
+			if c.Scrobble.SessionKey == "" && (c.Scrobble.Username == "" || c.Scrobble.Password == "") {
+				return errors.New("scrobble.session_key, or scrobble.username and scrobble.password, must be set to scrobble to Last.fm")
+			}
+		default:
+			return fmt.Errorf("invalid scrobble service '%s': must be one of listenbrainz, lastfm", c.Scrobble.Service)
+		}
+		if c.Scrobble.URL != "" && !strings.HasPrefix(c.Scrobble.URL, "http://") && !strings.HasPrefix(c.Scrobble.URL, "https://") {
+			return fmt.Errorf("invalid scrobble url '%s': must start with http:// or https://", c.Scrobble.URL)
+		}
+	}
+
+	// Validate hooks
+	for event, hooks := range c.Hooks {
+		if !slices.Contains(HookEvents, event) {
+			return fmt.Errorf("invalid hook event '%s': must be one of %s", event, strings.Join(HookEvents, ", "))
+		}
+		for i, hook := range hooks {
+			if (hook.Command == "") == (hook.URL == "") {
+				return fmt.Errorf("hooks.%s[%d] must have either a command or a url", event, i)
+			}
+			if hook.URL != "" && !strings.HasPrefix(hook.URL, "http://") && !strings.HasPrefix(hook.URL, "https://") {
+				return fmt.Errorf("invalid url '%s' in hooks.%s[%d]: must start with http:// or https://", hook.URL, event, i)
+			}
+			if hook.Timeout < 0 {
+				return fmt.Errorf("hooks.%s[%d].timeout must not be negative", event, i)
+			}
+		}
+	}
+
+	// Validate track table columns
+	for view, columns := range map[string][]string{
+		"playlist": c.Columns.Playlist,
+		"album":    c.Columns.Album,
+		"artist":   c.Columns.Artist,
+	} {
+		if len(columns) == 0 {
+			return fmt.Errorf("columns.%s must list at least one column", view)
+		}
+		for _, column := range columns {
+			if !slices.Contains(ValidColumns, column) {
+				return fmt.Errorf("invalid column '%s' in columns.%s: must be one of %s", column, view, strings.Join(ValidColumns, ", "))
+			}
+		}
+	}
+
 	return nil
 }
+
+const minAPITokenLength = 16
+
+// HookEvents lists the events hooks can be run on
+var HookEvents = []string{
+	"track_changed", "paused", "resumed", "device_changed", "queue_emptied", "auth_expired",
+}
+
+// ValidColumns lists the track table columns that can be configured
+var ValidColumns = []string{
+	"number", "title", "artist", "artists", "album", "duration",
+	"added", "popularity", "year", "explicit", "track", "disc",
+}
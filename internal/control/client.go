@@ -0,0 +1,246 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dietzy1/termify/internal/config"
+)
+
+// Exit codes of `termify ctl`
+const (
+	ExitOK         = 0 // The command ran
+	ExitFailed     = 1 // Termify ran the command but it failed, for example without an active device
+	ExitUsage      = 2 // The command or its value is invalid
+	ExitNotRunning = 3 // No Termify instance is listening on the socket
+)
+
+// ErrNotRunning is returned when nothing listens on the socket
+var ErrNotRunning = errors.New("termify is not running")
+
+// Send runs one request against the instance listening on the socket
+func Send(path string, request Request) (Response, error) {
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return Response{}, fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(replyTimeout + 5*time.Second))
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return Response{}, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var response Response
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(line, &response); err != nil {
+		return Response{}, fmt.Errorf("invalid response: %w", err)
+	}
+	return response, nil
+}
+
+// DaemonRunning reports whether the instance listening on the socket is the daemon
+func DaemonRunning(path string) bool {
+	response, err := Send(path, Request{Command: CommandStatus})
+	return err == nil && response.Daemon
+}
+
+// Subscription is an open stream of events from the socket
+type Subscription struct {
+	conn   net.Conn
+	events chan Event
+}
+
+// Subscribe opens a stream of events, starting with the current player and queue
+func Subscribe(path string) (*Subscription, error) {
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+	if err := json.NewEncoder(conn).Encode(Request{Command: CommandSubscribe}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	var response Response
+	line, err := reader.ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &response)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+	if !response.OK {
+		conn.Close()
+		return nil, fmt.Errorf("failed to subscribe: %s", response.Error)
+	}
+
+	sub := &Subscription{conn: conn, events: make(chan Event, subscriberBuffer)}
+	go func() {
+		defer close(sub.events)
+		decoder := json.NewDecoder(reader)
+		for {
+			var event Event
+			if err := decoder.Decode(&event); err != nil {
+				log.Printf("Control: Subscription ended: %v", err)
+				return
+			}
+			sub.events <- event
+		}
+	}()
+	return sub, nil
+}
+
+// Events returns the events, the channel closes once the connection is lost
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.conn.Close()
+}
+
+// SocketPath returns the socket given with --socket, or the one in the directory of the config given with --config
+func SocketPath(configPath, socketPath string) (string, error) {
+	if socketPath != "" {
+		return socketPath, nil
+	}
+	dir, err := config.ConfigDir(configPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, config.SocketFileName), nil
+}
+
+// RunCLI runs `termify ctl [flags] <command> [value]` and returns the exit code
+func RunCLI(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("termify ctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", "", "Path to the config file of the instance to control")
+	socketPath := flags.String("socket", "", "Path to the control socket, overrides --config")
+	asJSON := flags.Bool("json", false, "Print the response as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: termify ctl [flags] <command> [value]")
+		fmt.Fprintln(stderr, "\nCommands:")
+		for _, command := range Commands {
+			if value, ok := commandsWithValue[command]; ok {
+				fmt.Fprintf(stderr, "  %-11s %s\n", command, value)
+			} else {
+				fmt.Fprintf(stderr, "  %s\n", command)
+			}
+		}
+		fmt.Fprintln(stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+
+	// Parsing stops at the command, so values like -10 aren't taken for flags
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	if flags.NArg() == 0 || flags.NArg() > 2 {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	request := Request{Command: flags.Arg(0), Value: flags.Arg(1)}
+	if err := request.ValidatePublic(); err != nil {
+		fmt.Fprintf(stderr, "termify ctl: %v\n", err)
+		return ExitUsage
+	}
+
+	path, err := SocketPath(*configPath, *socketPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "termify ctl: %v\n", err)
+		return ExitUsage
+	}
+
+	response, err := Send(path, request)
+	if err != nil {
+		fmt.Fprintf(stderr, "termify ctl: %v\n", err)
+		if errors.Is(err, ErrNotRunning) {
+			return ExitNotRunning
+		}
+		return ExitFailed
+	}
+
+	if *asJSON {
+		json.NewEncoder(stdout).Encode(response)
+		if !response.OK {
+			return ExitFailed
+		}
+		return ExitOK
+	}
+	if !response.OK {
+		fmt.Fprintf(stderr, "termify ctl: %s\n", response.Error)
+		return ExitFailed
+	}
+
+	switch {
+	case request.Command == CommandStatus && response.Status != nil:
+		printStatus(stdout, *response.Status)
+	case request.Command == CommandQueue:
+		for i, track := range response.Queue {
+			fmt.Fprintf(stdout, "%d. %s – %s\n", i+1, strings.Join(track.Artists, ", "), track.Name)
+		}
+	case request.Command == CommandDevices:
+		for _, device := range response.Devices {
+			active := " "
+			if device.Active {
+				active = "*"
+			}
+			fmt.Fprintf(stdout, "%s %s (%s)\n", active, device.Name, device.Type)
+		}
+	}
+	return ExitOK
+}
+
+func printStatus(w io.Writer, status Status) {
+	if status.Track == "" {
+		fmt.Fprintln(w, "Stopped")
+		return
+	}
+
+	state := "Paused"
+	if status.Playing {
+		state = "Playing"
+	}
+	title := status.Track
+	if len(status.Artists) > 0 {
+		title = strings.Join(status.Artists, ", ") + " – " + status.Track
+	}
+	fmt.Fprintf(w, "%s: %s\n", state, title)
+	if status.Album != "" {
+		fmt.Fprintf(w, "Album: %s\n", status.Album)
+	}
+	fmt.Fprintf(w, "Position: %s / %s\n", FormatMs(status.ProgressMs), FormatMs(status.DurationMs))
+	fmt.Fprintf(w, "Volume: %d%%\n", status.Volume)
+	fmt.Fprintf(w, "Shuffle: %t, Repeat: %s\n", status.Shuffle, status.Repeat)
+	if status.Device != "" {
+		fmt.Fprintf(w, "Device: %s\n", status.Device)
+	}
+	fmt.Fprintf(w, "Queue: %d tracks\n", status.QueueLength)
+}
+
+// FormatMs formats a duration as m:ss
+func FormatMs(ms int) string {
+	seconds := ms / 1000
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,82 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeProgram fails pause like Spotify without an active device and runs every other command
+type fakeProgram struct{}
+
+func (fakeProgram) Send(msg tea.Msg) {
+	command, ok := msg.(CommandMsg)
+	if !ok {
+		return
+	}
+	if command.Request.Command == CommandPause {
+		command.Reply(Response{Error: "no active device"})
+		return
+	}
+	command.Reply(Response{OK: true, Status: &Status{Playing: true, Track: "Song", Artists: []string{"Artist"}}})
+}
+
+func TestRunCLIExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "termify.sock")
+	server, err := Listen(socket, NewBroker())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		server.Serve(ctx, fakeProgram{})
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string // Printed on success
+		stderr string // Part of the message printed on failure
+	}{
+		{"help", []string{"-h"}, ExitOK, "", "Usage"},
+		{"no command", []string{"--socket", socket}, ExitUsage, "", "Usage"},
+		{"too many values", []string{"--socket", socket, "volume", "10", "20"}, ExitUsage, "", "Usage"},
+		{"unknown flag", []string{"--verbose", "status"}, ExitUsage, "", "flag provided but not defined"},
+		{"unknown command", []string{"--socket", socket, "rewind"}, ExitUsage, "", "unknown command 'rewind'"},
+		{"internal command", []string{"--socket", socket, "token"}, ExitUsage, "", "unknown command 'token'"},
+		{"invalid value", []string{"--socket", socket, "volume", "101"}, ExitUsage, "", "invalid volume '101'"},
+		{"missing value", []string{"--socket", socket, "enqueue"}, ExitUsage, "", "enqueue needs a value"},
+		{"not running", []string{"--socket", filepath.Join(dir, "missing.sock"), "status"}, ExitNotRunning, "", "not running"},
+		{"ran", []string{"--socket", socket, "play-pause"}, ExitOK, "", ""},
+		{"negative value", []string{"--socket", socket, "volume", "-10"}, ExitOK, "", ""},
+		{"status", []string{"--socket", socket, "status"}, ExitOK, "Playing: Artist – Song", ""},
+		{"failed", []string{"--socket", socket, "pause"}, ExitFailed, "", "no active device"},
+		{"failed as json", []string{"--socket", socket, "--json", "pause"}, ExitFailed, `"error":"no active device"`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := RunCLI(tt.args, &stdout, &stderr); code != tt.code {
+				t.Errorf("exit code %d, want %d, stderr: %s", code, tt.code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.stdout) {
+				t.Errorf("stdout %q, want it to contain %q", stdout.String(), tt.stdout)
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr %q, want it to contain %q", stderr.String(), tt.stderr)
+			}
+		})
+	}
+}
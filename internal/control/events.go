@@ -0,0 +1,87 @@
+package control
+
+import (
+	"log"
+	"sync"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// Event types pushed to subscribers
+const (
+	EventPlayer = "player" // The playback state changed
+	EventQueue  = "queue"  // The queue changed
+	// Spotify stopped accepting the login, Termify needs to be logged in again
+	EventAuthExpired = "auth_expired"
+)
+
+// Event is a change in the running instance
+type Event struct {
+	Type   string  `json:"type"`
+	Status *Status `json:"status,omitempty"`
+	Queue  []Track `json:"queue,omitempty"`
+	// The full queued tracks, only sent to socket subscribers that mirror the queue
+	Tracks []spotify.SimpleTrack `json:"tracks,omitempty"`
+}
+
+// Events pending for a subscriber before it is considered too slow and events are dropped
+const subscriberBuffer = 16
+
+// Broker fans events out to every subscriber
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel of events, closed once unsubscribed or the broker closes
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := make(chan Event, subscriberBuffer)
+	if b.closed {
+		close(events)
+		return events, func() {}
+	}
+	b.subscribers[events] = struct{}{}
+
+	return events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[events]; ok {
+			delete(b.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// Publish sends the event to every subscriber without waiting on slow ones
+func (b *Broker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for events := range b.subscribers {
+		select {
+		case events <- event:
+		default:
+			log.Printf("Control: Dropped %s event for a slow subscriber", event.Type)
+		}
+	}
+}
+
+// Close ends every subscription
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for events := range b.subscribers {
+		delete(b.subscribers, events)
+		close(events)
+	}
+}
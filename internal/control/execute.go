@@ -0,0 +1,254 @@
+package control
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
+)
+
+// Execute runs a command against the Spotify state, for the TUI and the daemon alike. progressMs is
+// the position the caller counted since the last state fetch and next plays the next track the way
+// the caller autoplays. Commands that talk to Spotify reply once the call finished.
+func Execute(ctx context.Context, s *state.SpotifyState, msg CommandMsg, progressMs int, next func() tea.Cmd) tea.Cmd {
+	request := msg.Request
+
+	fail := func(format string, args ...any) tea.Cmd {
+		msg.Reply(Response{Error: fmt.Sprintf(format, args...)})
+		return nil
+	}
+
+	playerState := s.GetPlayerState()
+	var cmd tea.Cmd
+	switch request.Command {
+	case CommandStatus:
+		status := NewStatus(s, progressMs)
+		msg.Reply(Response{OK: true, Status: &status})
+		return nil
+
+	case CommandQueue:
+		status := NewStatus(s, progressMs)
+		msg.Reply(Response{OK: true, Status: &status, Queue: QueueTracks(s)})
+		return nil
+
+	case CommandDevices:
+		cmd = s.RefreshDevices(ctx)
+
+	case CommandDevice:
+		cmd = s.SelectDeviceByName(ctx, request.Value)
+
+	case CommandPlayPause:
+		if playerState.Playing {
+			cmd = s.PausePlayback(ctx)
+		} else {
+			cmd = s.StartPlayback(ctx)
+		}
+
+	case CommandPlay:
+		if request.Value == "" {
+			cmd = s.StartPlayback(ctx)
+			break
+		}
+		link, ok := state.ParseSpotifyLink(request.Value)
+		if !ok {
+			return fail("invalid link '%s': expected an open.spotify.com URL or a spotify: URI", request.Value)
+		}
+		cmd = s.PlayLink(ctx, link)
+
+	case CommandPause:
+		cmd = s.PausePlayback(ctx)
+
+	case CommandNext:
+		cmd = next()
+
+	case CommandPrevious:
+		cmd = s.PreviousTrack(ctx)
+
+	case CommandVolume:
+		volume, relative, err := ParseVolume(request.Value)
+		if err != nil {
+			return fail("%v", err)
+		}
+		if relative {
+			volume += int(playerState.Device.Volume)
+		}
+		cmd = s.SetVolume(ctx, volume)
+
+	case CommandSeek:
+		if playerState.Item == nil {
+			return fail("nothing is playing")
+		}
+		position, relative, err := ParseSeek(request.Value)
+		if err != nil {
+			return fail("%v", err)
+		}
+		if relative {
+			position += progressMs
+		}
+		cmd = s.Seek(ctx, min(position, int(playerState.Item.Duration)))
+
+	case CommandShuffle:
+		shuffle, err := ParseShuffle(request.Value)
+		if err != nil {
+			return fail("%v", err)
+		}
+		cmd = s.SetShuffle(ctx, shuffle)
+
+	case CommandRepeat:
+		mode, err := ParseRepeat(request.Value)
+		if err != nil {
+			return fail("%v", err)
+		}
+		cmd = s.SetRepeatMode(ctx, mode)
+
+	case CommandEnqueue:
+		links, ok := state.ParseSpotifyLinks(request.Value)
+		if !ok {
+			return fail("invalid link '%s': expected open.spotify.com URLs or spotify: URIs", request.Value)
+		}
+		cmd = s.QueueLinks(ctx, links)
+
+	default:
+		return fail("%s is not supported here", request.Command)
+	}
+
+	if cmd == nil {
+		return fail("%s had nothing to do", request.Command)
+	}
+	return replyWhenDone(s, msg, cmd)
+}
+
+// replyWhenDone runs the command, answers the request with its outcome and passes its messages on
+func replyWhenDone(s *state.SpotifyState, msg CommandMsg, cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		results := runCmd(cmd)
+
+		response := Response{OK: true}
+		for _, result := range results {
+			switch result := result.(type) {
+			case state.ErrorMsg:
+				response = Response{Error: fmt.Sprintf("%s: %s", result.Title, result.Message)}
+			case state.LinksQueuedMsg:
+				if result.Queued == 0 {
+					response = Response{Error: "no tracks could be queued"}
+				}
+			}
+		}
+		if response.OK {
+			playerState := s.GetPlayerState()
+			status := NewStatus(s, int(playerState.Progress))
+			response.Status = &status
+			if msg.Request.Command == CommandDevices {
+				response.Devices = DeviceList(s)
+			}
+		}
+		msg.Reply(response)
+
+		if len(results) == 1 {
+			return results[0]
+		}
+		batch := make(tea.BatchMsg, len(results))
+		for i, result := range results {
+			batch[i] = func() tea.Msg { return result }
+		}
+		return batch
+	}
+}
+
+// runCmd runs the command and any commands it batches, returning their messages
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, cmd := range batch {
+		msgs = append(msgs, runCmd(cmd)...)
+	}
+	return msgs
+}
+
+// NewStatus describes the current playback, with the progress the caller knows best
+func NewStatus(s *state.SpotifyState, progressMs int) Status {
+	playerState := s.GetPlayerState()
+	status := Status{
+		Playing:     playerState.Playing,
+		Volume:      int(playerState.Device.Volume),
+		Shuffle:     playerState.ShuffleState,
+		Repeat:      playerState.RepeatState,
+		Device:      playerState.Device.Name,
+		QueueLength: s.Queue.Size(),
+	}
+	if item := playerState.Item; item != nil {
+		status.Track = item.Name
+		status.Artists = artistNames(item.Artists)
+		status.Album = item.Album.Name
+		status.URI = string(item.URI)
+		status.DurationMs = int(item.Duration)
+		if len(item.Album.Images) > 0 {
+			status.ArtURL = item.Album.Images[0].URL
+		}
+		status.ProgressMs = min(progressMs, status.DurationMs)
+	}
+	return status
+}
+
+// QueueTracks lists the queue
+func QueueTracks(s *state.SpotifyState) []Track {
+	queue := s.Queue.List()
+	tracks := make([]Track, len(queue))
+	for i, track := range queue {
+		tracks[i] = Track{
+			Name:       track.Name,
+			Artists:    artistNames(track.Artists),
+			Album:      track.Album.Name,
+			URI:        string(track.URI),
+			DurationMs: int(track.Duration),
+		}
+	}
+	return tracks
+}
+
+// DeviceList lists the Spotify Connect devices
+func DeviceList(s *state.SpotifyState) []Device {
+	deviceState := s.GetDeviceState()
+	devices := make([]Device, len(deviceState))
+	for i, device := range deviceState {
+		devices[i] = Device{
+			ID:     string(device.ID),
+			Name:   device.Name,
+			Type:   device.Type,
+			Active: device.Active,
+			Volume: int(device.Volume),
+		}
+	}
+	return devices
+}
+
+// NewEvent describes the current player or queue for subscribers
+func NewEvent(s *state.SpotifyState, eventType string, progressMs int) Event {
+	event := Event{Type: eventType}
+	switch eventType {
+	case EventPlayer:
+		status := NewStatus(s, progressMs)
+		event.Status = &status
+	case EventQueue:
+		event.Queue = QueueTracks(s)
+		event.Tracks = s.Queue.List()
+	}
+	return event
+}
+
+func artistNames(artists []spotify.SimpleArtist) []string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return names
+}
@@ -0,0 +1,163 @@
+package control
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// How often an idle event stream sends a comment so proxies keep it open
+const keepaliveInterval = 30 * time.Second
+
+// httpHandler serves the JSON API, every command goes through the program like socket commands
+type httpHandler struct {
+	token   string
+	program Sender
+	events  *Broker
+}
+
+// NewHTTPHandler serves the API under /api/. Requests need the token in an
+// "Authorization: Bearer" header. The event stream also takes it as a token query
+// parameter, since EventSource clients can't set headers.
+func NewHTTPHandler(token string, program Sender, events *Broker) http.Handler {
+	h := &httpHandler{token: token, program: program, events: events}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/status", h.authorize(h.query(CommandStatus), false))
+	mux.Handle("GET /api/queue", h.authorize(h.query(CommandQueue), false))
+	mux.Handle("GET /api/devices", h.authorize(h.query(CommandDevices), false))
+	mux.Handle("GET /api/events", h.authorize(http.HandlerFunc(h.eventStream), true))
+	mux.Handle("POST /api/{command}", h.authorize(http.HandlerFunc(h.command), false))
+
+	return mux
+}
+
+// authorize checks the bearer token, and the token query parameter when queryToken is set
+func (h *httpHandler) authorize(next http.Handler, queryToken bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var token string
+		if queryToken {
+			token = r.URL.Query().Get("token")
+		}
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			token = bearer
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			log.Printf("Control: Rejected unauthorized API request %s %s", r.Method, r.URL.Path)
+			writeJSON(w, http.StatusUnauthorized, Response{Error: "missing or invalid token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// query answers a GET request with the response to a command that only reads state
+func (h *httpHandler) query(command string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.run(w, r, Request{Command: command})
+	}
+}
+
+// command runs the command named in the path. The value comes from a JSON body
+// such as {"value": "+10"}, or from a value query parameter.
+func (h *httpHandler) command(w http.ResponseWriter, r *http.Request) {
+	request := Request{Command: r.PathValue("command"), Value: r.URL.Query().Get("value")}
+	if r.ContentLength != 0 {
+		var body struct {
+			Value string `json:"value"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, Response{Error: fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+		request.Value = body.Value
+	}
+	h.run(w, r, request)
+}
+
+func (h *httpHandler) run(w http.ResponseWriter, r *http.Request, request Request) {
+	if err := request.ValidatePublic(); err != nil {
+		writeJSON(w, http.StatusBadRequest, Response{Error: err.Error()})
+		return
+	}
+
+	response := Dispatch(r.Context(), h.program, request)
+	if !response.OK {
+		writeJSON(w, http.StatusBadGateway, response)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// eventStream pushes player and queue changes as server-sent events, starting with the current state
+func (h *httpHandler) eventStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, Response{Error: "streaming is not supported"})
+		return
+	}
+
+	events, unsubscribe := h.events.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	log.Println("Control: Event stream opened")
+
+	if response := Dispatch(r.Context(), h.program, Request{Command: CommandStatus}); response.OK {
+		writeEvent(w, Event{Type: EventPlayer, Status: response.Status})
+	}
+	if response := Dispatch(r.Context(), h.program, Request{Command: CommandQueue}); response.OK {
+		writeEvent(w, Event{Type: EventQueue, Queue: response.Queue})
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Println("Control: Event stream closed by client")
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			event.Tracks = nil
+			if err := writeEvent(w, event); err != nil {
+				log.Printf("Control: Failed to write event: %v", err)
+				return
+			}
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Control: Failed to write API response: %v", err)
+	}
+}
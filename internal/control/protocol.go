@@ -0,0 +1,241 @@
+package control
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
+	"golang.org/x/oauth2"
+)
+
+// Commands understood by a running Termify
+const (
+	CommandPlayPause = "play-pause"
+	CommandPlay      = "play"
+	CommandPause     = "pause"
+	CommandNext      = "next"
+	CommandPrevious  = "previous"
+	CommandVolume    = "volume"
+	CommandSeek      = "seek"
+	CommandShuffle   = "shuffle"
+	CommandRepeat    = "repeat"
+	CommandEnqueue   = "enqueue"
+	CommandDevice    = "device"
+	CommandStatus    = "status"
+	CommandQueue     = "queue"
+	CommandDevices   = "devices"
+)
+
+// Commands attached TUIs use to share the daemon's queue, not meant for scripts
+const (
+	// Keep the connection open and stream events, starting with the current player and queue
+	CommandSubscribe = "subscribe"
+	// Replace the queue with the request's tracks
+	CommandSetQueue = "set-queue"
+	// Tracks to continue with once the queue runs out, usually the view a track was played from
+	CommandSetContext = "set-context"
+	// The daemon's access token, attached TUIs use the daemon's login instead of their own
+	CommandToken = "token"
+	// Switch the autoplay fallback to the one named by the value, as cycled in a TUI
+	CommandSetFallback = "set-fallback"
+)
+
+// commandsWithValue lists the commands that take a value and what it looks like
+var commandsWithValue = map[string]string{
+	CommandPlay:    "optional Spotify link or URI to play",
+	CommandVolume:  "0-100, +N or -N",
+	CommandSeek:    "seconds, m:ss, +N or -N",
+	CommandShuffle: "on or off",
+	CommandRepeat:  "off, context or track",
+	CommandEnqueue: "Spotify link or URI",
+	CommandDevice:  "device name",
+}
+
+// Commands lists every command in the order they are documented
+var Commands = []string{
+	CommandPlayPause, CommandPlay, CommandPause, CommandNext, CommandPrevious,
+	CommandVolume, CommandSeek, CommandShuffle, CommandRepeat, CommandEnqueue, CommandDevice, CommandStatus, CommandQueue, CommandDevices,
+}
+
+// Request is one command sent to the socket, encoded as a single line of JSON
+type Request struct {
+	Command string `json:"command"`
+	Value   string `json:"value,omitempty"`
+	// Full tracks for set-queue and set-context
+	Tracks []spotify.SimpleTrack `json:"tracks,omitempty"`
+}
+
+// Validate checks the command exists and has a well-formed value
+func (r Request) Validate() error {
+	switch r.Command {
+	case CommandVolume:
+		_, _, err := ParseVolume(r.Value)
+		return err
+	case CommandSeek:
+		_, _, err := ParseSeek(r.Value)
+		return err
+	case CommandShuffle:
+		_, err := ParseShuffle(r.Value)
+		return err
+	case CommandRepeat:
+		_, err := ParseRepeat(r.Value)
+		return err
+	case CommandEnqueue, CommandDevice:
+		if strings.TrimSpace(r.Value) == "" {
+			return fmt.Errorf("%s needs a value: %s", r.Command, commandsWithValue[r.Command])
+		}
+		return nil
+	case CommandPlay:
+		if r.Value == "" {
+			return nil
+		}
+		if _, ok := state.ParseSpotifyLink(r.Value); !ok {
+			return fmt.Errorf("invalid link '%s': expected an open.spotify.com URL or a spotify: URI", r.Value)
+		}
+		return nil
+	case CommandSetFallback:
+		_, err := state.ParseAutoplayFallback(r.Value)
+		return err
+	case CommandPlayPause, CommandPause, CommandNext, CommandPrevious,
+		CommandStatus, CommandQueue, CommandDevices,
+		CommandSubscribe, CommandSetQueue, CommandSetContext, CommandToken:
+		return nil
+	}
+	return fmt.Errorf("unknown command '%s': must be one of %s", r.Command, strings.Join(Commands, ", "))
+}
+
+// ValidatePublic is Validate for scripts and HTTP clients, which can't use the attach commands
+func (r Request) ValidatePublic() error {
+	switch r.Command {
+	case CommandSubscribe, CommandSetQueue, CommandSetContext, CommandToken, CommandSetFallback:
+		return fmt.Errorf("unknown command '%s': must be one of %s", r.Command, strings.Join(Commands, ", "))
+	}
+	return r.Validate()
+}
+
+// Response answers a Request. Status is set on success, the queue and devices when asked for.
+type Response struct {
+	OK      bool     `json:"ok"`
+	Error   string   `json:"error,omitempty"`
+	Status  *Status  `json:"status,omitempty"`
+	Queue   []Track  `json:"queue,omitempty"`
+	Devices []Device `json:"devices,omitempty"`
+	// Set when the daemon answered rather than a TUI
+	Daemon bool `json:"daemon,omitempty"`
+	// Access token for the token command, without the refresh token
+	Token *oauth2.Token `json:"token,omitempty"`
+}
+
+// Status describes the playback after a command ran
+type Status struct {
+	Playing     bool     `json:"playing"`
+	Track       string   `json:"track,omitempty"`
+	Artists     []string `json:"artists,omitempty"`
+	Album       string   `json:"album,omitempty"`
+	URI         string   `json:"uri,omitempty"`
+	ArtURL      string   `json:"art_url,omitempty"`
+	ProgressMs  int      `json:"progress_ms"`
+	DurationMs  int      `json:"duration_ms"`
+	Volume      int      `json:"volume"`
+	Shuffle     bool     `json:"shuffle"`
+	Repeat      string   `json:"repeat,omitempty"`
+	Device      string   `json:"device,omitempty"`
+	QueueLength int      `json:"queue_length"`
+}
+
+// Advanced returns the status with the progress moved on by the time that passed while playing
+func (s Status) Advanced(elapsed time.Duration) Status {
+	if s.Playing {
+		s.ProgressMs = min(s.ProgressMs+int(elapsed.Milliseconds()), s.DurationMs)
+	}
+	return s
+}
+
+// Track is a queued track
+type Track struct {
+	Name       string   `json:"name"`
+	Artists    []string `json:"artists"`
+	Album      string   `json:"album,omitempty"`
+	URI        string   `json:"uri"`
+	DurationMs int      `json:"duration_ms"`
+}
+
+// Device is a Spotify Connect device
+type Device struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Active bool   `json:"active"`
+	Volume int    `json:"volume"`
+}
+
+// ParseVolume reads an absolute volume, or a change when it starts with + or -
+func ParseVolume(value string) (volume int, relative bool, err error) {
+	relative = strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-")
+	volume, err = strconv.Atoi(value)
+	if err != nil || (!relative && (volume < 0 || volume > 100)) {
+		return 0, false, fmt.Errorf("invalid volume '%s': must be %s", value, commandsWithValue[CommandVolume])
+	}
+	return volume, relative, nil
+}
+
+// ParseSeek reads a position in milliseconds from seconds or m:ss, or a change when it starts with + or -.
+// The seconds may have up to three decimals.
+func ParseSeek(value string) (positionMs int, relative bool, err error) {
+	invalid := fmt.Errorf("invalid position '%s': must be %s", value, commandsWithValue[CommandSeek])
+
+	sign := 1
+	switch {
+	case strings.HasPrefix(value, "+"):
+		relative = true
+	case strings.HasPrefix(value, "-"):
+		relative = true
+		sign = -1
+	}
+	value = strings.TrimLeft(value, "+-")
+
+	minutes, seconds, hasMinutes := strings.Cut(value, ":")
+	if !hasMinutes {
+		minutes, seconds = "0", value
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 {
+		return 0, false, invalid
+	}
+	seconds, fraction, hasFraction := strings.Cut(seconds, ".")
+	s, err := strconv.Atoi(seconds)
+	if err != nil || s < 0 || (hasMinutes && s > 59) {
+		return 0, false, invalid
+	}
+	ms := 0
+	if hasFraction {
+		if fraction == "" || len(fraction) > 3 || strings.Trim(fraction, "0123456789") != "" {
+			return 0, false, invalid
+		}
+		ms, _ = strconv.Atoi(fraction + strings.Repeat("0", 3-len(fraction)))
+	}
+	return sign * ((m*60+s)*1000 + ms), relative, nil
+}
+
+// ParseShuffle reads on or off
+func ParseShuffle(value string) (bool, error) {
+	switch value {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid shuffle '%s': must be %s", value, commandsWithValue[CommandShuffle])
+}
+
+// ParseRepeat reads a repeat mode as Spotify names it
+func ParseRepeat(value string) (string, error) {
+	switch value {
+	case "off", "context", "track":
+		return value, nil
+	}
+	return "", fmt.Errorf("invalid repeat mode '%s': must be %s", value, commandsWithValue[CommandRepeat])
+}
@@ -0,0 +1,78 @@
+package control
+
+import "testing"
+
+func TestParseSeek(t *testing.T) {
+	tests := []struct {
+		value    string
+		position int
+		relative bool
+		valid    bool
+	}{
+		{"30", 30000, false, true},
+		{"0", 0, false, true},
+		{"1:30", 90000, false, true},
+		{"1:05", 65000, false, true},
+		{"90", 90000, false, true},
+		{"15.5", 15500, false, true},
+		{"15.500", 15500, false, true},
+		{"15.05", 15050, false, true},
+		{"1:05.250", 65250, false, true},
+		{"+10", 10000, true, true},
+		{"-5", -5000, true, true},
+		{"+0.5", 500, true, true},
+		{"-1:00", -60000, true, true},
+		{"", 0, false, false},
+		{"soon", 0, false, false},
+		{"1:60", 0, false, false},
+		{"1:-2", 0, false, false},
+		{"1:2:3", 0, false, false},
+		{"15.", 0, false, false},
+		{".5", 0, false, false},
+		{"15.5000", 0, false, false},
+		{"15.5s", 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			position, relative, err := ParseSeek(tt.value)
+			if (err == nil) != tt.valid {
+				t.Fatalf("ParseSeek(%q) error = %v, want valid %t", tt.value, err, tt.valid)
+			}
+			if position != tt.position || relative != tt.relative {
+				t.Errorf("ParseSeek(%q) = %d, %t, want %d, %t", tt.value, position, relative, tt.position, tt.relative)
+			}
+		})
+	}
+}
+
+func TestParseVolume(t *testing.T) {
+	tests := []struct {
+		value    string
+		volume   int
+		relative bool
+		valid    bool
+	}{
+		{"50", 50, false, true},
+		{"0", 0, false, true},
+		{"100", 100, false, true},
+		{"+10", 10, true, true},
+		{"-10", -10, true, true},
+		{"+200", 200, true, true}, // Clamped once added to the current volume
+		{"101", 0, false, false},
+		{"", 0, false, false},
+		{"-", 0, false, false},
+		{"loud", 0, false, false},
+		{"50%", 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			volume, relative, err := ParseVolume(tt.value)
+			if (err == nil) != tt.valid {
+				t.Fatalf("ParseVolume(%q) error = %v, want valid %t", tt.value, err, tt.valid)
+			}
+			if volume != tt.volume || relative != tt.relative {
+				t.Errorf("ParseVolume(%q) = %d, %t, want %d, %t", tt.value, volume, relative, tt.volume, tt.relative)
+			}
+		})
+	}
+}
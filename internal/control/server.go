@@ -0,0 +1,198 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// How long a command may take before the client is told it timed out
+const replyTimeout = 15 * time.Second
+
+// CommandMsg carries a socket request into the Bubble Tea program. The program must call Reply
+// exactly once, commands that talk to Spotify reply once the call finished.
+type CommandMsg struct {
+	Request Request
+	Reply   func(Response)
+}
+
+// Sender is the part of tea.Program that commands are sent through
+type Sender interface {
+	Send(msg tea.Msg)
+}
+
+// Server accepts commands on a Unix domain socket and forwards them to the program
+type Server struct {
+	// Daemon marks every response as coming from the daemon, which TUIs attach to
+	Daemon bool
+
+	path     string
+	listener net.Listener
+	events   *Broker
+	wg       sync.WaitGroup
+}
+
+// Listen creates the socket at path, subscribers are sent the events published to the broker.
+// A socket left behind by an instance that crashed is replaced, while one that still accepts
+// connections means another instance is running.
+func Listen(path string, events *Broker) (*Server, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("another Termify instance is listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	// Anyone who can write to the socket can control playback
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+
+	log.Printf("Control: Listening on %s", path)
+	return &Server{path: path, listener: listener, events: events}, nil
+}
+
+// Serve accepts connections until the context is cancelled, then removes the socket
+func (s *Server) Serve(ctx context.Context, program Sender) {
+	go func() {
+		<-ctx.Done()
+		s.listener.Close()
+	}()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+				log.Printf("Control: Failed to accept connection: %v", err)
+				continue
+			}
+			break
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(ctx, conn, program)
+		}()
+	}
+
+	s.wg.Wait()
+	os.Remove(s.path)
+	log.Println("Control: Socket closed")
+}
+
+// handle answers every request on the connection, one JSON line each
+func (s *Server) handle(ctx context.Context, conn net.Conn, program Sender) {
+	defer conn.Close()
+
+	// An idle client would otherwise keep the server from shutting down
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	// Decoded as a stream rather than scanned by line, set-queue carries whole tracks
+	// and has no length limit
+	decoder := json.NewDecoder(conn)
+	encoder := json.NewEncoder(conn)
+	for {
+		var request Request
+		var response Response
+		err := decoder.Decode(&request)
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			// The rest of the stream can't be told apart from the broken request
+			encoder.Encode(Response{Error: fmt.Sprintf("invalid request: %v", err), Daemon: s.Daemon})
+			return
+		case err != nil && !errors.As(err, &typeErr):
+			// The client closed the connection
+			return
+		}
+
+		if err != nil {
+			response = Response{Error: fmt.Sprintf("invalid request: %v", err)}
+		} else if err := request.Validate(); err != nil {
+			response = Response{Error: err.Error()}
+		} else if request.Command == CommandSubscribe {
+			s.stream(ctx, encoder, program)
+			return
+		} else {
+			response = Dispatch(ctx, program, request)
+		}
+		response.Daemon = s.Daemon
+
+		if err := encoder.Encode(response); err != nil {
+			log.Printf("Control: Failed to write response: %v", err)
+			return
+		}
+	}
+}
+
+// stream writes every published event to the connection until either side closes it.
+// The program answers the subscribe command by publishing the current player and queue.
+func (s *Server) stream(ctx context.Context, encoder *json.Encoder, program Sender) {
+	events, unsubscribe := s.events.Subscribe()
+	defer unsubscribe()
+
+	response := Dispatch(ctx, program, Request{Command: CommandSubscribe})
+	response.Daemon = s.Daemon
+	if err := encoder.Encode(response); err != nil || !response.OK {
+		return
+	}
+	log.Println("Control: Subscriber attached")
+
+	for event := range events {
+		if err := encoder.Encode(event); err != nil {
+			log.Printf("Control: Subscriber detached: %v", err)
+			return
+		}
+	}
+}
+
+// Dispatch sends the request into the program and waits for its reply
+func Dispatch(ctx context.Context, program Sender, request Request) Response {
+	log.Printf("Control: Received %s %s", request.Command, request.Value)
+
+	// Buffered so a late reply never blocks the program
+	replies := make(chan Response, 1)
+	program.Send(CommandMsg{
+		Request: request,
+		Reply: func(response Response) {
+			select {
+			case replies <- response:
+			default:
+			}
+		},
+	})
+
+	select {
+	case response := <-replies:
+		return response
+	case <-time.After(replyTimeout):
+		return Response{Error: fmt.Sprintf("%s timed out", request.Command)}
+	case <-ctx.Done():
+		return Response{Error: "Termify is shutting down"}
+	}
+}
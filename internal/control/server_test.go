@@ -0,0 +1,136 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmb3/spotify/v2"
+)
+
+// queueProgram takes set-queue like the daemon, keeping the tracks it was sent
+type queueProgram struct {
+	queued chan []spotify.SimpleTrack
+}
+
+func (p queueProgram) Send(msg tea.Msg) {
+	if command, ok := msg.(CommandMsg); ok {
+		p.queued <- command.Request.Tracks
+		command.Reply(Response{OK: true})
+	}
+}
+
+// startTestServer serves the program on a socket in a temporary directory
+func startTestServer(t *testing.T, program Sender) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "termify.sock")
+	server, err := Listen(socket, NewBroker())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		server.Serve(ctx, program)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return socket
+}
+
+func TestSetQueueWithManyTracks(t *testing.T) {
+	program := queueProgram{queued: make(chan []spotify.SimpleTrack, 1)}
+	socket := startTestServer(t, program)
+
+	tracks := make([]spotify.SimpleTrack, 500)
+	for i := range tracks {
+		tracks[i] = spotify.SimpleTrack{
+			ID:      spotify.ID(fmt.Sprintf("%022d", i)),
+			Name:    fmt.Sprintf("Track %d", i),
+			URI:     spotify.URI(fmt.Sprintf("spotify:track:%022d", i)),
+			Artists: []spotify.SimpleArtist{{Name: "Artist", ID: "0OdUWJ0sBjDrqHygGUXeCF"}},
+			Album:   spotify.SimpleAlbum{Name: "Album", ID: "6akEvsycLGftJxYudPjmqK"},
+		}
+	}
+	request := Request{Command: CommandSetQueue, Tracks: tracks}
+	if data, _ := json.Marshal(request); len(data) <= bufio.MaxScanTokenSize {
+		t.Fatalf("the request is only %d bytes, make it longer than a scanned line", len(data))
+	}
+
+	response, err := Send(socket, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.OK {
+		t.Fatalf("set-queue failed: %s", response.Error)
+	}
+	if queued := <-program.queued; len(queued) != len(tracks) || queued[len(queued)-1].ID != tracks[len(tracks)-1].ID {
+		t.Errorf("queued %d tracks, want %d", len(queued), len(tracks))
+	}
+}
+
+func TestInvalidRequests(t *testing.T) {
+	socket := startTestServer(t, fakeProgram{})
+
+	tests := []struct {
+		name  string
+		lines string
+		want  []string // Part of the error of every reply, empty when it succeeded
+		// Whether the connection still answers after the replies
+		open bool
+	}{
+		{"wrong type then a valid request", `{"command": 5}` + "\n" + `{"command": "status"}` + "\n", []string{"invalid request", ""}, true},
+		{"unknown command", `{"command": "rewind"}` + "\n", []string{"unknown command 'rewind'"}, true},
+		{"broken json", `{"command" "status"}` + "\n", []string{"invalid request"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := net.Dial("unix", socket)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			if _, err := conn.Write([]byte(tt.lines)); err != nil {
+				t.Fatal(err)
+			}
+
+			reader := bufio.NewReader(conn)
+			for _, want := range tt.want {
+				line, err := reader.ReadBytes('\n')
+				if err != nil {
+					t.Fatalf("no reply: %v", err)
+				}
+				var response Response
+				if err := json.Unmarshal(line, &response); err != nil {
+					t.Fatal(err)
+				}
+				if want == "" && !response.OK {
+					t.Errorf("request failed: %s", response.Error)
+				}
+				if want != "" && !strings.Contains(response.Error, want) {
+					t.Errorf("error %q, want it to contain %q", response.Error, want)
+				}
+			}
+
+			if tt.open {
+				conn.Write([]byte(`{"command": "status"}` + "\n"))
+			}
+			_, err = reader.ReadBytes('\n')
+			if tt.open && err != nil {
+				t.Errorf("the connection closed: %v", err)
+			}
+			if !tt.open && err == nil {
+				t.Error("the connection is still open after a broken request")
+			}
+		})
+	}
+}
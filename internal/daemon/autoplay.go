@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
+)
+
+// next plays the next queued track, then the track after the current one in the context an
+// attached TUI played from, and then whatever the autoplay fallback finds
+func (m *model) next() tea.Cmd {
+	// The counted progress is past the end until the new track's state arrives
+	m.progress = 0
+
+	if track, err := m.spotifyState.Queue.Dequeue(); err == nil {
+		log.Printf("Daemon: Playing next track from queue: %s", track.ID)
+		return tea.Batch(state.UpdateQueue(), m.spotifyState.PlayTrack(m.ctx, track.ID))
+	}
+
+	if track := m.nextInContext(); track != "" {
+		log.Printf("Daemon: Playing next track from context: %s", track)
+		return m.spotifyState.PlayTrack(m.ctx, track)
+	}
+
+	fallback := m.spotifyState.GetAutoplayFallback()
+	log.Printf("Daemon: Queue and context ran out, using autoplay fallback: %s", fallback)
+	switch fallback {
+	case state.FallbackStop:
+		return m.spotifyState.FetchPlaybackState(m.ctx)
+
+	case state.FallbackLoopContext:
+		if len(m.context) > 0 {
+			return m.spotifyState.PlayTrack(m.ctx, m.context[0].ID)
+		}
+		return m.spotifyState.FetchPlaybackState(m.ctx)
+	}
+	return m.spotifyState.LoadAutoplayFallback(m.ctx)
+}
+
+// nextInContext returns the context track following the current one
+func (m *model) nextInContext() spotify.ID {
+	for i, track := range m.context {
+		if track.ID == m.trackID && i+1 < len(m.context) {
+			return m.context[i+1].ID
+		}
+	}
+	return ""
+}
+
+func (m model) snapshotSession() state.Session {
+	return state.Session{
+		Queue:   m.spotifyState.Queue.List(),
+		SavedAt: time.Now(),
+	}
+}
+
+// saveSession writes the queue synchronously, used when stopping
+func (m model) saveSession() {
+	if err := state.SaveSession(m.sessionPath, m.snapshotSession()); err != nil {
+		log.Printf("Daemon: Failed to save the queue: %v", err)
+		return
+	}
+	log.Println("Daemon: Queue saved")
+}
+
+// saveSessionAsync snapshots the queue on the update loop and writes it in the background
+func (m model) saveSessionAsync() tea.Cmd {
+	path, session := m.sessionPath, m.snapshotSession()
+	return func() tea.Msg {
+		if err := state.SaveSession(path, session); err != nil {
+			log.Printf("Daemon: Failed to save the queue: %v", err)
+		}
+		return nil
+	}
+}
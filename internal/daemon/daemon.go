@@ -0,0 +1,330 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dietzy1/termify/internal/authentication"
+	"github.com/dietzy1/termify/internal/config"
+	"github.com/dietzy1/termify/internal/control"
+	"github.com/dietzy1/termify/internal/services"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
+	"golang.org/x/oauth2"
+)
+
+// How often the player state is fetched, to notice changes made in other Spotify clients
+const pollInterval = 5 * time.Second
+
+type authenticator interface {
+	StartStoredTokenAuth(ctx context.Context) tea.Cmd
+	SetTeaProgram(p *tea.Program)
+}
+
+type tokenStorer interface {
+	SaveToken(token *oauth2.Token) error
+}
+
+type tickMsg time.Time
+
+type pollMsg time.Time
+
+// model owns playback, the queue and autoplay without a UI. It runs in a Bubble Tea program
+// without a renderer so it shares the state commands and message flow of the TUI.
+type model struct {
+	ctx           context.Context
+	authenticator authenticator
+	events        *control.Broker
+	config        *config.Config
+	sessionPath   string
+
+	spotifyState *state.SpotifyState // Nil until logged in
+
+	// Position in the current track counted between polls, like the TUI's audio player
+	progress int
+	trackID  spotify.ID
+	playing  bool
+
+	// Tracks an attached TUI played from, continued with once the queue runs out
+	context []spotify.SimpleTrack
+
+	err error // Why the daemon stopped, if it failed
+}
+
+// Run runs the daemon until the context is cancelled or it is interrupted
+func Run(ctx context.Context, c *config.Config, authenticator authenticator, tokenStorer tokenStorer, events *control.Broker) error {
+	if !c.IsControlEnabled() {
+		return errors.New("the daemon is controlled through its socket, set control.enabled to true")
+	}
+	server, err := control.Listen(c.GetControlSocketPath(), events)
+	if err != nil {
+		return err
+	}
+	server.Daemon = true
+
+	m := model{
+		ctx:           ctx,
+		authenticator: authenticator,
+		events:        events,
+		config:        c,
+		sessionPath:   c.GetDaemonSessionFilePath(),
+	}
+
+	p := tea.NewProgram(m, tea.WithoutRenderer(), tea.WithInput(nil))
+	authenticator.SetTeaProgram(p)
+
+	go func() {
+		<-ctx.Done()
+		log.Println("Daemon: Context cancelled, stopping")
+		p.Quit()
+	}()
+
+	serveCtx, stopServing := context.WithCancel(ctx)
+	var serving sync.WaitGroup
+	defer func() {
+		stopServing()
+		serving.Wait()
+	}()
+	serving.Go(func() { server.Serve(serveCtx, p) })
+
+	services.Start(serveCtx, c, events, p, &serving)
+
+	fmt.Printf("Termify daemon listening on %s\n", c.GetControlSocketPath())
+	final, err := p.Run()
+	if err != nil && !errors.Is(err, tea.ErrInterrupted) {
+		return fmt.Errorf("error running daemon: %w", err)
+	}
+
+	m = final.(model)
+	if m.spotifyState != nil {
+		if token := m.spotifyState.GetOathToken(); token != nil {
+			if err := tokenStorer.SaveToken(token); err != nil {
+				log.Printf("Daemon: Failed to save token: %v", err)
+			}
+		}
+		m.saveSession()
+	}
+	return m.err
+}
+
+func (m model) Init() tea.Cmd {
+	cmd := m.authenticator.StartStoredTokenAuth(m.ctx)
+	if cmd == nil {
+		return func() tea.Msg {
+			return authentication.LoginErrorMsg{Error: errors.New("not logged in to Spotify, run termify once to log in")}
+		}
+	}
+	return cmd
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case authentication.LoginUrlMsg:
+		// The callback on the server port completes the login
+		log.Printf("Daemon: Waiting for login at %s", msg.Url)
+		if msg.Reason != "" {
+			fmt.Println(msg.Reason)
+		}
+		fmt.Printf("Open this URL to log in to Spotify:\n%s\n", msg.Url)
+		return m, nil
+
+	case authentication.LoginErrorMsg:
+		log.Printf("Daemon: Login failed: %v", msg.Error)
+		m.err = msg.Error
+		return m, tea.Quit
+
+	case authentication.LoginClientMsg:
+		return m.start(msg.Client)
+
+	case authentication.AuthExpiredMsg:
+		fmt.Printf("The Spotify login expired: %v\n", msg.Error)
+		m.events.Publish(control.Event{Type: control.EventAuthExpired})
+		return m, nil
+
+	case tea.InterruptMsg:
+		return m, tea.Quit
+	}
+
+	// Everything below needs a logged in client
+	if m.spotifyState == nil {
+		if command, ok := msg.(control.CommandMsg); ok {
+			command.Reply(control.Response{Error: "the daemon is not logged in to Spotify yet"})
+		}
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case control.CommandMsg:
+		return m, m.handleCommand(msg)
+
+	case tickMsg:
+		playerState := m.spotifyState.GetPlayerState()
+		if m.playing && playerState.Item != nil {
+			m.progress++
+			if m.progress > int(playerState.Item.Duration/1000) {
+				log.Println("Daemon: Track ended")
+				cmd := m.next()
+				return m, tea.Batch(cmd, tick())
+			}
+		}
+		return m, tick()
+
+	case pollMsg:
+		return m, tea.Batch(m.spotifyState.FetchPlaybackState(m.ctx), poll())
+
+	case state.PlayerStateUpdatedMsg:
+		return m.handlePlayerState()
+
+	case state.QueueUpdatedMsg:
+		m.publish(control.EventQueue)
+		return m, m.saveSessionAsync()
+
+	case state.LinksQueuedMsg:
+		return m, state.UpdateQueue()
+
+	case state.AutoplayFallbackLoadedMsg:
+		if msg.Count == 0 {
+			log.Println("Daemon: The autoplay fallback found no tracks, stopping")
+			return m, m.spotifyState.FetchPlaybackState(m.ctx)
+		}
+		cmd := m.next()
+		return m, cmd
+
+	case state.ErrorMsg:
+		log.Printf("Daemon: %s: %s", msg.Title, msg.Message)
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m model) View() string {
+	return ""
+}
+
+// start restores the queue and begins following playback once logged in
+func (m model) start(client *spotify.Client) (tea.Model, tea.Cmd) {
+	log.Println("Daemon: Logged in")
+	fmt.Println("Logged in to Spotify")
+
+	m.spotifyState = state.NewSpotifyState(client)
+	if fallback, err := state.ParseAutoplayFallback(m.config.GetAutoplayFallback()); err != nil {
+		log.Printf("Daemon: %v, autoplay will stop once the queue runs out", err)
+	} else {
+		m.spotifyState.SetAutoplayFallback(fallback)
+	}
+	m.spotifyState.SetFallbackPlaylist(m.config.GetFallbackPlaylist())
+
+	session, err := state.LoadSession(m.sessionPath)
+	if err != nil {
+		log.Printf("Daemon: Failed to load the saved queue: %v", err)
+	} else if session != nil {
+		m.spotifyState.Queue.EnqueueAll(session.Queue)
+		log.Printf("Daemon: Restored %d queued tracks", len(session.Queue))
+	}
+
+	return m, tea.Batch(
+		m.spotifyState.FetchPlaybackState(m.ctx),
+		m.spotifyState.RefreshDevices(m.ctx),
+		state.UpdateQueue(),
+		tick(),
+		poll(),
+	)
+}
+
+// handlePlayerState resyncs the counted progress and notices tracks that ended between ticks
+func (m model) handlePlayerState() (tea.Model, tea.Cmd) {
+	playerState := m.spotifyState.GetPlayerState()
+
+	// Spotify stops at the start of a track played on its own once it ends, which a poll can
+	// see before the counted progress passes the end
+	ended := m.playing && !playerState.Playing && playerState.Progress == 0 &&
+		playerState.Item != nil && playerState.Item.ID == m.trackID &&
+		m.progress*1000 >= int(playerState.Item.Duration)-2*int(pollInterval/time.Millisecond)
+
+	m.playing = playerState.Playing
+	m.progress = int(playerState.Progress / 1000)
+	if playerState.Item != nil {
+		m.trackID = playerState.Item.ID
+	}
+	m.publish(control.EventPlayer)
+
+	if ended {
+		log.Println("Daemon: Track ended while polling")
+		cmd := m.next()
+		return m, cmd
+	}
+	return m, nil
+}
+
+// handleCommand runs a socket or API command, attached TUIs also share their queue and view
+func (m *model) handleCommand(msg control.CommandMsg) tea.Cmd {
+	switch msg.Request.Command {
+	case control.CommandSubscribe:
+		m.publish(control.EventPlayer)
+		m.publish(control.EventQueue)
+		msg.Reply(control.Response{OK: true})
+		return nil
+
+	case control.CommandSetQueue:
+		m.spotifyState.Queue.Replace(msg.Request.Tracks)
+		log.Printf("Daemon: Queue replaced by an attached TUI, %d tracks", len(msg.Request.Tracks))
+		msg.Reply(control.Response{OK: true})
+		return state.UpdateQueue()
+
+	case control.CommandSetContext:
+		m.context = msg.Request.Tracks
+		log.Printf("Daemon: Autoplay context set to %d tracks", len(m.context))
+		msg.Reply(control.Response{OK: true})
+		return nil
+
+	case control.CommandSetFallback:
+		fallback, err := state.ParseAutoplayFallback(msg.Request.Value)
+		if err != nil {
+			msg.Reply(control.Response{Error: err.Error()})
+			return nil
+		}
+		m.spotifyState.SetAutoplayFallback(fallback)
+		log.Printf("Daemon: Autoplay fallback set to %s by an attached TUI", fallback)
+		msg.Reply(control.Response{OK: true})
+		return nil
+
+	case control.CommandToken:
+		token := m.spotifyState.GetOathToken()
+		if token == nil {
+			msg.Reply(control.Response{Error: "the daemon has no usable Spotify login"})
+			return nil
+		}
+		// Only the daemon refreshes the login, a refresh elsewhere could invalidate its refresh token
+		msg.Reply(control.Response{OK: true, Token: &oauth2.Token{
+			AccessToken: token.AccessToken,
+			TokenType:   token.TokenType,
+			Expiry:      token.Expiry,
+		}})
+		return nil
+	}
+
+	return control.Execute(m.ctx, m.spotifyState, msg, m.progress*1000, func() tea.Cmd {
+		return m.next()
+	})
+}
+
+func (m model) publish(eventType string) {
+	m.events.Publish(control.NewEvent(m.spotifyState, eventType, m.progress*1000))
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+func poll() tea.Cmd {
+	return tea.Tick(pollInterval, func(t time.Time) tea.Msg {
+		return pollMsg(t)
+	})
+}
@@ -0,0 +1,240 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dietzy1/termify/internal/config"
+	"github.com/dietzy1/termify/internal/control"
+)
+
+// Events hooks run on, as named in the config
+const (
+	TrackChanged  = "track_changed"
+	Paused        = "paused"
+	Resumed       = "resumed"
+	DeviceChanged = "device_changed"
+	QueueEmptied  = "queue_emptied"
+	AuthExpired   = "auth_expired"
+)
+
+// How long a hook may run when its timeout isn't set
+const defaultTimeout = 10 * time.Second
+
+// Longest command output kept for the log when a command fails
+const maxLoggedOutput = 512
+
+// Runner runs the configured hooks for the changes published to the broker
+type Runner struct {
+	hooks  map[string][]config.Hook
+	events *control.Broker
+	client *http.Client
+	wg     sync.WaitGroup
+
+	// The last player and queue seen, to tell what changed. Events are compared
+	// once there is something to compare against, so starting doesn't run hooks.
+	status      *control.Status
+	queueLength int
+	queueKnown  bool
+}
+
+// Payload is the JSON body POSTed to webhooks
+type Payload struct {
+	Event     string          `json:"event"`
+	Status    *control.Status `json:"status,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+func NewRunner(hooks map[string][]config.Hook, events *control.Broker) *Runner {
+	return &Runner{hooks: hooks, events: events, client: &http.Client{}}
+}
+
+// Serve runs hooks until the context is cancelled, which also stops the hooks still running
+func (r *Runner) Serve(ctx context.Context) {
+	events, unsubscribe := r.events.Subscribe()
+	defer unsubscribe()
+	defer r.wg.Wait()
+
+	log.Println("Hooks: Running")
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Hooks: Stopped")
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			for _, name := range r.changes(event) {
+				r.run(ctx, name)
+			}
+		}
+	}
+}
+
+// changes returns the hook events an event stands for
+func (r *Runner) changes(event control.Event) []string {
+	var names []string
+	switch event.Type {
+	case control.EventPlayer:
+		if event.Status == nil {
+			return nil
+		}
+		previous, status := r.status, *event.Status
+		r.status = &status
+		if previous == nil {
+			return nil
+		}
+
+		if status.URI != "" && status.URI != previous.URI {
+			names = append(names, TrackChanged)
+		}
+		if status.URI != "" && status.Playing != previous.Playing {
+			if status.Playing {
+				names = append(names, Resumed)
+			} else {
+				names = append(names, Paused)
+			}
+		}
+		if status.Device != "" && status.Device != previous.Device {
+			names = append(names, DeviceChanged)
+		}
+
+	case control.EventQueue:
+		emptied := r.queueKnown && r.queueLength > 0 && len(event.Queue) == 0
+		r.queueLength, r.queueKnown = len(event.Queue), true
+		if emptied {
+			names = append(names, QueueEmptied)
+		}
+
+	case control.EventAuthExpired:
+		names = append(names, AuthExpired)
+	}
+	return names
+}
+
+// run starts every hook for the event in the background
+func (r *Runner) run(ctx context.Context, name string) {
+	hooks := r.hooks[name]
+	if len(hooks) == 0 {
+		return
+	}
+	log.Printf("Hooks: %s, running %d hooks", name, len(hooks))
+
+	payload := Payload{Event: name, Timestamp: time.Now()}
+	if r.status != nil {
+		status := *r.status
+		payload.Status = &status
+	}
+
+	for _, hook := range hooks {
+		timeout := hook.Timeout
+		if timeout == 0 {
+			timeout = defaultTimeout
+		}
+		r.wg.Go(func() {
+			hookCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			var err error
+			if hook.Command != "" {
+				err = runCommand(hookCtx, hook.Command, payload)
+			} else {
+				err = r.post(hookCtx, hook.URL, payload)
+			}
+			if err != nil {
+				log.Printf("Hooks: %s hook failed: %v", name, err)
+			}
+		})
+	}
+}
+
+// runCommand runs the command in the shell with the event in its environment
+func runCommand(ctx context.Context, command string, payload Payload) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(), environment(payload)...)
+	// Don't wait on processes the command left running with its output
+	cmd.WaitDelay = time.Second
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("'%s' timed out", command)
+	}
+	if err != nil {
+		text := strings.TrimSpace(string(output))
+		if len(text) > maxLoggedOutput {
+			text = text[:maxLoggedOutput] + "…"
+		}
+		return fmt.Errorf("'%s': %w: %s", command, err, text)
+	}
+	return nil
+}
+
+// environment describes the event as TERMIFY_* variables
+func environment(payload Payload) []string {
+	env := []string{"TERMIFY_EVENT=" + payload.Event}
+	status := payload.Status
+	if status == nil {
+		return env
+	}
+	state := "stopped"
+	if status.URI != "" {
+		state = "paused"
+		if status.Playing {
+			state = "playing"
+		}
+	}
+	return append(env,
+		"TERMIFY_STATE="+state,
+		"TERMIFY_TITLE="+status.Track,
+		"TERMIFY_ARTIST="+strings.Join(status.Artists, ", "),
+		"TERMIFY_ALBUM="+status.Album,
+		"TERMIFY_URI="+status.URI,
+		"TERMIFY_ART_URL="+status.ArtURL,
+		"TERMIFY_DURATION_MS="+strconv.Itoa(status.DurationMs),
+		"TERMIFY_PROGRESS_MS="+strconv.Itoa(status.ProgressMs),
+		"TERMIFY_VOLUME="+strconv.Itoa(status.Volume),
+		"TERMIFY_DEVICE="+status.Device,
+		"TERMIFY_QUEUE_LENGTH="+strconv.Itoa(status.QueueLength),
+	)
+}
+
+// post sends the event as JSON to the webhook
+func (r *Runner) post(ctx context.Context, url string, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Termify")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s: %s", url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package mpris
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/dietzy1/termify/internal/control"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/godbus/dbus/v5"
+)
+
+// Track ID for when nothing is playing
+const noTrack = dbus.ObjectPath("/org/mpris/MediaPlayer2/TrackList/NoTrack")
+
+// MPRIS loop statuses and the Spotify repeat modes they stand for
+var loopStatuses = map[string]string{
+	"off":     "None",
+	"track":   "Track",
+	"context": "Playlist",
+}
+
+// root implements org.mpris.MediaPlayer2. Termify has no window to raise and is quit from its terminal.
+type root struct{}
+
+func (root) Raise() *dbus.Error {
+	return nil
+}
+
+func (root) Quit() *dbus.Error {
+	return nil
+}
+
+func rootProperties() map[string]dbus.Variant {
+	return map[string]dbus.Variant{
+		"CanQuit":             dbus.MakeVariant(false),
+		"CanRaise":            dbus.MakeVariant(false),
+		"HasTrackList":        dbus.MakeVariant(false),
+		"Identity":            dbus.MakeVariant("Termify"),
+		"SupportedUriSchemes": dbus.MakeVariant([]string{}),
+		"SupportedMimeTypes":  dbus.MakeVariant([]string{}),
+	}
+}
+
+// player implements org.mpris.MediaPlayer2.Player
+type player struct {
+	s *Server
+}
+
+func (p player) Next() *dbus.Error {
+	return p.s.run(control.Request{Command: control.CommandNext})
+}
+
+func (p player) Previous() *dbus.Error {
+	return p.s.run(control.Request{Command: control.CommandPrevious})
+}
+
+func (p player) Pause() *dbus.Error {
+	return p.s.run(control.Request{Command: control.CommandPause})
+}
+
+func (p player) PlayPause() *dbus.Error {
+	return p.s.run(control.Request{Command: control.CommandPlayPause})
+}
+
+// Stop pauses, Spotify has no stopped state to return to
+func (p player) Stop() *dbus.Error {
+	return p.s.run(control.Request{Command: control.CommandPause})
+}
+
+func (p player) Play() *dbus.Error {
+	return p.s.run(control.Request{Command: control.CommandPlay})
+}
+
+// SeekBy is exported as Seek, it moves by the offset in microseconds and past the end plays the next track
+func (p player) SeekBy(offset int64) *dbus.Error {
+	status := p.s.snapshot()
+	if status.URI == "" {
+		return nil
+	}
+	position := max(int64(status.ProgressMs)+offset/1000, 0)
+	if position > int64(status.DurationMs) {
+		return p.Next()
+	}
+	return p.seekTo(position)
+}
+
+// SetPosition moves to the position in microseconds if the track is still playing
+func (p player) SetPosition(trackID dbus.ObjectPath, position int64) *dbus.Error {
+	status := p.s.snapshot()
+	if trackID != trackObjectPath(status.URI) || position < 0 || position/1000 > int64(status.DurationMs) {
+		return nil
+	}
+	return p.seekTo(position / 1000)
+}
+
+func (p player) seekTo(positionMs int64) *dbus.Error {
+	value := fmt.Sprintf("%d.%03d", positionMs/1000, positionMs%1000)
+	return p.s.run(control.Request{Command: control.CommandSeek, Value: value})
+}
+
+func (p player) OpenUri(uri string) *dbus.Error {
+	return dbus.MakeFailedError(errors.New("opening URIs is not supported"))
+}
+
+// playerProperties describes the status as player properties, without the position which
+// is only read on request
+func playerProperties(status control.Status) map[string]dbus.Variant {
+	playbackStatus := "Stopped"
+	switch {
+	case status.URI != "" && status.Playing:
+		playbackStatus = "Playing"
+	case status.URI != "":
+		playbackStatus = "Paused"
+	}
+	loopStatus, ok := loopStatuses[status.Repeat]
+	if !ok {
+		loopStatus = "None"
+	}
+
+	return map[string]dbus.Variant{
+		"PlaybackStatus": dbus.MakeVariant(playbackStatus),
+		"LoopStatus":     dbus.MakeVariant(loopStatus),
+		"Rate":           dbus.MakeVariant(1.0),
+		"Shuffle":        dbus.MakeVariant(status.Shuffle),
+		"Metadata":       dbus.MakeVariant(metadata(status)),
+		"Volume":         dbus.MakeVariant(float64(status.Volume) / 100),
+		"MinimumRate":    dbus.MakeVariant(1.0),
+		"MaximumRate":    dbus.MakeVariant(1.0),
+		"CanGoNext":      dbus.MakeVariant(true),
+		"CanGoPrevious":  dbus.MakeVariant(true),
+		"CanPlay":        dbus.MakeVariant(true),
+		"CanPause":       dbus.MakeVariant(true),
+		"CanSeek":        dbus.MakeVariant(status.URI != ""),
+		"CanControl":     dbus.MakeVariant(true),
+	}
+}
+
+func metadata(status control.Status) map[string]dbus.Variant {
+	if status.URI == "" {
+		return map[string]dbus.Variant{"mpris:trackid": dbus.MakeVariant(noTrack)}
+	}
+
+	metadata := map[string]dbus.Variant{
+		"mpris:trackid": dbus.MakeVariant(trackObjectPath(status.URI)),
+		"mpris:length":  dbus.MakeVariant(int64(status.DurationMs) * 1000),
+		"xesam:title":   dbus.MakeVariant(status.Track),
+		"xesam:artist":  dbus.MakeVariant(append([]string{}, status.Artists...)),
+		"xesam:album":   dbus.MakeVariant(status.Album),
+	}
+	if status.ArtURL != "" {
+		metadata["mpris:artUrl"] = dbus.MakeVariant(status.ArtURL)
+	}
+	if link, ok := state.ParseSpotifyLink(status.URI); ok {
+		metadata["xesam:url"] = dbus.MakeVariant(link.URL())
+	}
+	return metadata
+}
+
+// trackObjectPath turns a spotify: URI into a track ID, which D-Bus wants as an object path
+func trackObjectPath(uri string) dbus.ObjectPath {
+	link, ok := state.ParseSpotifyLink(uri)
+	if !ok {
+		return noTrack
+	}
+	path := dbus.ObjectPath("/com/termify/" + link.Kind + "/" + string(link.ID))
+	if !path.IsValid() {
+		return noTrack
+	}
+	return path
+}
+
+// properties implements org.freedesktop.DBus.Properties for both interfaces
+type properties struct {
+	s *Server
+}
+
+func (p properties) Get(iface, name string) (dbus.Variant, *dbus.Error) {
+	all, err := p.GetAll(iface)
+	if err != nil {
+		return dbus.Variant{}, err
+	}
+	value, ok := all[name]
+	if !ok {
+		return dbus.Variant{}, dbus.NewError("org.freedesktop.DBus.Error.UnknownProperty", []any{name})
+	}
+	return value, nil
+}
+
+func (p properties) GetAll(iface string) (map[string]dbus.Variant, *dbus.Error) {
+	switch iface {
+	case rootInterface:
+		return rootProperties(), nil
+	case playerInterface:
+		status := p.s.snapshot()
+		all := playerProperties(status)
+		all["Position"] = dbus.MakeVariant(int64(status.ProgressMs) * 1000)
+		return all, nil
+	}
+	return nil, dbus.NewError("org.freedesktop.DBus.Error.UnknownInterface", []any{iface})
+}
+
+// Set changes the volume, shuffle and loop status, the change is signalled once Spotify made it
+func (p properties) Set(iface, name string, value dbus.Variant) *dbus.Error {
+	if iface != playerInterface {
+		return dbus.NewError("org.freedesktop.DBus.Error.PropertyReadOnly", []any{name})
+	}
+	invalid := dbus.NewError("org.freedesktop.DBus.Error.InvalidArgs", []any{"invalid value for " + name})
+
+	switch name {
+	case "Volume":
+		volume, ok := value.Value().(float64)
+		if !ok {
+			return invalid
+		}
+		percent := int(math.Round(max(0, min(volume, 1)) * 100))
+		return p.s.run(control.Request{Command: control.CommandVolume, Value: strconv.Itoa(percent)})
+
+	case "Shuffle":
+		shuffle, ok := value.Value().(bool)
+		if !ok {
+			return invalid
+		}
+		request := control.Request{Command: control.CommandShuffle, Value: "off"}
+		if shuffle {
+			request.Value = "on"
+		}
+		return p.s.run(request)
+
+	case "LoopStatus":
+		loopStatus, ok := value.Value().(string)
+		if !ok {
+			return invalid
+		}
+		for mode, status := range loopStatuses {
+			if status == loopStatus {
+				return p.s.run(control.Request{Command: control.CommandRepeat, Value: mode})
+			}
+		}
+		return invalid
+
+	case "Rate":
+		// Spotify only plays at normal speed
+		return nil
+	}
+	return dbus.NewError("org.freedesktop.DBus.Error.PropertyReadOnly", []any{name})
+}
+
+const introspection = `<node>
+  <interface name="org.mpris.MediaPlayer2">
+    <method name="Raise"/>
+    <method name="Quit"/>
+    <property name="CanQuit" type="b" access="read"/>
+    <property name="CanRaise" type="b" access="read"/>
+    <property name="HasTrackList" type="b" access="read"/>
+    <property name="Identity" type="s" access="read"/>
+    <property name="SupportedUriSchemes" type="as" access="read"/>
+    <property name="SupportedMimeTypes" type="as" access="read"/>
+  </interface>
+  <interface name="org.mpris.MediaPlayer2.Player">
+    <method name="Next"/>
+    <method name="Previous"/>
+    <method name="Pause"/>
+    <method name="PlayPause"/>
+    <method name="Stop"/>
+    <method name="Play"/>
+    <method name="Seek">
+      <arg name="Offset" type="x" direction="in"/>
+    </method>
+    <method name="SetPosition">
+      <arg name="TrackId" type="o" direction="in"/>
+      <arg name="Position" type="x" direction="in"/>
+    </method>
+    <method name="OpenUri">
+      <arg name="Uri" type="s" direction="in"/>
+    </method>
+    <signal name="Seeked">
+      <arg name="Position" type="x"/>
+    </signal>
+    <property name="PlaybackStatus" type="s" access="read"/>
+    <property name="LoopStatus" type="s" access="readwrite"/>
+    <property name="Rate" type="d" access="readwrite"/>
+    <property name="Shuffle" type="b" access="readwrite"/>
+    <property name="Metadata" type="a{sv}" access="read"/>
+    <property name="Volume" type="d" access="readwrite"/>
+    <property name="Position" type="x" access="read"/>
+    <property name="MinimumRate" type="d" access="read"/>
+    <property name="MaximumRate" type="d" access="read"/>
+    <property name="CanGoNext" type="b" access="read"/>
+    <property name="CanGoPrevious" type="b" access="read"/>
+    <property name="CanPlay" type="b" access="read"/>
+    <property name="CanPause" type="b" access="read"/>
+    <property name="CanSeek" type="b" access="read"/>
+    <property name="CanControl" type="b" access="read"/>
+  </interface>
+  <interface name="org.freedesktop.DBus.Properties">
+    <method name="Get">
+      <arg name="interface" type="s" direction="in"/>
+      <arg name="property" type="s" direction="in"/>
+      <arg name="value" type="v" direction="out"/>
+    </method>
+    <method name="GetAll">
+      <arg name="interface" type="s" direction="in"/>
+      <arg name="properties" type="a{sv}" direction="out"/>
+    </method>
+    <method name="Set">
+      <arg name="interface" type="s" direction="in"/>
+      <arg name="property" type="s" direction="in"/>
+      <arg name="value" type="v" direction="in"/>
+    </method>
+    <signal name="PropertiesChanged">
+      <arg name="interface" type="s"/>
+      <arg name="changed_properties" type="a{sv}"/>
+      <arg name="invalidated_properties" type="as"/>
+    </signal>
+  </interface>
+  <interface name="org.freedesktop.DBus.Introspectable">
+    <method name="Introspect">
+      <arg name="data" type="s" direction="out"/>
+    </method>
+  </interface>
+</node>`
@@ -0,0 +1,186 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/dietzy1/termify/internal/control"
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+// Names from the MPRIS2 specification
+const (
+	busName             = "org.mpris.MediaPlayer2.termify"
+	objectPath          = dbus.ObjectPath("/org/mpris/MediaPlayer2")
+	rootInterface       = "org.mpris.MediaPlayer2"
+	playerInterface     = "org.mpris.MediaPlayer2.Player"
+	propertiesInterface = "org.freedesktop.DBus.Properties"
+)
+
+// How far the position may move from where it was expected before clients are told about a seek
+const seekThreshold = 2 * time.Second
+
+// Server shows the running instance as a media player on the D-Bus session bus. Method calls
+// and property changes become commands sent through the program, like socket commands, and
+// player events become PropertiesChanged signals.
+type Server struct {
+	conn    *dbus.Conn
+	events  *control.Broker
+	ctx     context.Context
+	program control.Sender
+
+	mu      sync.Mutex
+	status  control.Status
+	updated time.Time // When the status arrived, to advance the position while playing
+}
+
+// Connect connects to the session bus. The player shows up once Serve runs.
+func Connect(events *control.Broker) (*Server, error) {
+	address, err := sessionBusAddress()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the session bus: %w", err)
+	}
+	return &Server{conn: conn, events: events}, nil
+}
+
+// sessionBusAddress finds the session bus without autolaunching one like godbus would
+func sessionBusAddress() (string, error) {
+	if address := os.Getenv("DBUS_SESSION_BUS_ADDRESS"); address != "" {
+		return address, nil
+	}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		path := filepath.Join(runtimeDir, "bus")
+		if _, err := os.Stat(path); err == nil {
+			return "unix:path=" + path, nil
+		}
+	}
+	return "", errors.New("no D-Bus session bus found")
+}
+
+// Serve registers the player and follows playback until the context is cancelled
+func (s *Server) Serve(ctx context.Context, program control.Sender) {
+	defer s.conn.Close()
+	s.ctx, s.program = ctx, program
+
+	events, unsubscribe := s.events.Subscribe()
+	defer unsubscribe()
+
+	if err := s.export(); err != nil {
+		log.Printf("MPRIS: Failed to export the player: %v", err)
+		return
+	}
+	name, err := s.requestName()
+	if err != nil {
+		log.Printf("MPRIS: %v", err)
+		return
+	}
+	log.Printf("MPRIS: Registered as %s", name)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("MPRIS: Stopped")
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Type == control.EventPlayer && event.Status != nil {
+				s.update(*event.Status)
+			}
+		}
+	}
+}
+
+func (s *Server) export() error {
+	exports := []struct {
+		v       any
+		iface   string
+		mapping map[string]string
+	}{
+		{root{}, rootInterface, nil},
+		// Seek would clash with io.Seeker
+		{player{s}, playerInterface, map[string]string{"SeekBy": "Seek"}},
+		{properties{s}, propertiesInterface, nil},
+		{introspect.Introspectable(introspection), "org.freedesktop.DBus.Introspectable", nil},
+	}
+	for _, export := range exports {
+		if err := s.conn.ExportWithMap(export.v, export.mapping, objectPath, export.iface); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requestName takes the well-known name, or a name for this instance when another one has it
+func (s *Server) requestName() (string, error) {
+	for _, name := range []string{busName, fmt.Sprintf("%s.instance%d", busName, os.Getpid())} {
+		reply, err := s.conn.RequestName(name, dbus.NameFlagDoNotQueue)
+		if err != nil {
+			return "", fmt.Errorf("failed to request %s: %w", name, err)
+		}
+		if reply == dbus.RequestNameReplyPrimaryOwner {
+			return name, nil
+		}
+	}
+	return "", errors.New("no bus name available")
+}
+
+// update stores the playback status and signals the properties that changed
+func (s *Server) update(status control.Status) {
+	s.mu.Lock()
+	previous := s.status
+	expected := previous.Advanced(time.Since(s.updated)).ProgressMs
+	s.status, s.updated = status, time.Now()
+	s.mu.Unlock()
+
+	before, after := playerProperties(previous), playerProperties(status)
+	changed := make(map[string]dbus.Variant)
+	for name, value := range after {
+		if !reflect.DeepEqual(before[name].Value(), value.Value()) {
+			changed[name] = value
+		}
+	}
+	if len(changed) > 0 {
+		if err := s.conn.Emit(objectPath, propertiesInterface+".PropertiesChanged", playerInterface, changed, []string{}); err != nil {
+			log.Printf("MPRIS: Failed to signal changed properties: %v", err)
+		}
+	}
+
+	// The position isn't signalled as it changes, only when it jumps
+	jump := time.Duration(status.ProgressMs-expected) * time.Millisecond
+	if status.URI == previous.URI && status.URI != "" && (jump > seekThreshold || jump < -seekThreshold) {
+		if err := s.conn.Emit(objectPath, playerInterface+".Seeked", int64(status.ProgressMs)*1000); err != nil {
+			log.Printf("MPRIS: Failed to signal seek: %v", err)
+		}
+	}
+}
+
+// snapshot returns the status with the position advanced to now
+func (s *Server) snapshot() control.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status.Advanced(time.Since(s.updated))
+}
+
+// run sends a command through the program and turns a failure into a D-Bus error
+func (s *Server) run(request control.Request) *dbus.Error {
+	response := control.Dispatch(s.ctx, s.program, request)
+	if !response.OK {
+		log.Printf("MPRIS: %s failed: %s", request.Command, response.Error)
+		return dbus.MakeFailedError(errors.New(response.Error))
+	}
+	return nil
+}
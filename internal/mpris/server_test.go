@@ -0,0 +1,247 @@
+package mpris
+
+import (
+	"bufio"
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dietzy1/termify/internal/control"
+	"github.com/godbus/dbus/v5"
+)
+
+const testTrackURI = "spotify:track:4uLU6hMCjMI75M1A2tKUQC"
+
+// fakeProgram answers every command and records it
+type fakeProgram struct {
+	requests chan control.Request
+}
+
+func (p fakeProgram) Send(msg tea.Msg) {
+	if command, ok := msg.(control.CommandMsg); ok {
+		p.requests <- command.Request
+		command.Reply(control.Response{OK: true})
+	}
+}
+
+// startBus runs a private session bus for the test and points Connect at it
+func startBus(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("dbus-daemon"); err != nil {
+		t.Skip("dbus-daemon is not installed")
+	}
+	cmd := exec.Command("dbus-daemon", "--session", "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read the bus address: %v", err)
+	}
+	address = strings.TrimSpace(address)
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", address)
+	return address
+}
+
+// startServer serves the player on a private bus and returns a client connection to it
+func startServer(t *testing.T) (*dbus.Conn, *control.Broker, fakeProgram) {
+	t.Helper()
+	address := startBus(t)
+
+	events := control.NewBroker()
+	server, err := Connect(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	program := fakeProgram{requests: make(chan control.Request, 10)}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		server.Serve(ctx, program)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	client, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	// The name is taken once Serve subscribed to events
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		var owned bool
+		if err := client.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, busName).Store(&owned); err != nil {
+			t.Fatal(err)
+		}
+		if owned {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the player never registered on the bus")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return client, events, program
+}
+
+func publish(events *control.Broker, status control.Status) {
+	events.Publish(control.Event{Type: control.EventPlayer, Status: &status})
+}
+
+func TestPropertiesChanged(t *testing.T) {
+	client, events, _ := startServer(t)
+
+	if err := client.AddMatchSignal(
+		dbus.WithMatchObjectPath(objectPath),
+		dbus.WithMatchInterface(propertiesInterface),
+		dbus.WithMatchMember("PropertiesChanged"),
+	); err != nil {
+		t.Fatal(err)
+	}
+	signals := make(chan *dbus.Signal, 10)
+	client.Signal(signals)
+
+	playing := control.Status{
+		Playing:    true,
+		Track:      "Song",
+		Artists:    []string{"Artist"},
+		Album:      "Album",
+		URI:        testTrackURI,
+		DurationMs: 200000,
+	}
+	paused := playing
+	paused.Playing = false
+
+	tests := []struct {
+		name           string
+		status         control.Status
+		playbackStatus string
+		title          string // Empty when the metadata shouldn't change
+	}{
+		{"track starts", playing, "Playing", "Song"},
+		{"paused", paused, "Paused", ""},
+		{"stopped", control.Status{}, "Stopped", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publish(events, tt.status)
+
+			var signal *dbus.Signal
+			select {
+			case signal = <-signals:
+			case <-time.After(5 * time.Second):
+				t.Fatal("no PropertiesChanged signal")
+			}
+			if iface := signal.Body[0].(string); iface != playerInterface {
+				t.Fatalf("signal for %s, want %s", iface, playerInterface)
+			}
+			changed := signal.Body[1].(map[string]dbus.Variant)
+
+			if got := changed["PlaybackStatus"].Value(); got != tt.playbackStatus {
+				t.Errorf("PlaybackStatus = %v, want %s", got, tt.playbackStatus)
+			}
+			metadata, hasMetadata := changed["Metadata"]
+			if tt.title == "" {
+				if hasMetadata && tt.status.URI != "" {
+					t.Errorf("Metadata changed to %v, want it unchanged", metadata)
+				}
+				return
+			}
+			if !hasMetadata {
+				t.Fatal("Metadata didn't change")
+			}
+			fields := metadata.Value().(map[string]dbus.Variant)
+			if got := fields["xesam:title"].Value(); got != tt.title {
+				t.Errorf("xesam:title = %v, want %s", got, tt.title)
+			}
+			if got := fields["mpris:trackid"].Value(); got != trackObjectPath(testTrackURI) {
+				t.Errorf("mpris:trackid = %v, want %s", got, trackObjectPath(testTrackURI))
+			}
+			if got := fields["mpris:length"].Value(); got != int64(200000000) {
+				t.Errorf("mpris:length = %v, want 200000000", got)
+			}
+		})
+	}
+}
+
+func TestMethodsSendCommands(t *testing.T) {
+	client, events, program := startServer(t)
+
+	// Paused, so the position doesn't move while the test runs
+	publish(events, control.Status{
+		Track:      "Song",
+		Artists:    []string{"Artist"},
+		URI:        testTrackURI,
+		ProgressMs: 10000,
+		DurationMs: 200000,
+	})
+	// Properties are read from the published status once it arrived
+	player := client.Object(busName, objectPath)
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		position, err := player.GetProperty(playerInterface + ".Position")
+		if err == nil && position.Value() == int64(10000000) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the status never arrived, Position = %v, %v", position, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		args   []any
+		want   control.Request
+	}{
+		{"play pause", "PlayPause", nil, control.Request{Command: control.CommandPlayPause}},
+		{"next", "Next", nil, control.Request{Command: control.CommandNext}},
+		{"previous", "Previous", nil, control.Request{Command: control.CommandPrevious}},
+		{"stop pauses", "Stop", nil, control.Request{Command: control.CommandPause}},
+		{"seek forward", "Seek", []any{int64(5500000)}, control.Request{Command: control.CommandSeek, Value: "15.500"}},
+		{"seek back before the start", "Seek", []any{int64(-60000000)}, control.Request{Command: control.CommandSeek, Value: "0.000"}},
+		{"seek past the end", "Seek", []any{int64(300000000)}, control.Request{Command: control.CommandNext}},
+		{"set position", "SetPosition", []any{trackObjectPath(testTrackURI), int64(42250000)}, control.Request{Command: control.CommandSeek, Value: "42.250"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if call := player.Call(playerInterface+"."+tt.method, 0, tt.args...); call.Err != nil {
+				t.Fatalf("%s failed: %v", tt.method, call.Err)
+			}
+			select {
+			case got := <-program.requests:
+				if got.Command != tt.want.Command || got.Value != tt.want.Value {
+					t.Errorf("%s sent %+v, want %+v", tt.method, got, tt.want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("%s sent no command", tt.method)
+			}
+		})
+	}
+
+	// Positions for another track are ignored
+	if call := player.Call(playerInterface+".SetPosition", 0, noTrack, int64(1000000)); call.Err != nil {
+		t.Fatal(call.Err)
+	}
+	select {
+	case got := <-program.requests:
+		t.Errorf("SetPosition for another track sent %+v", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,73 @@
+package nowplaying
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/dietzy1/termify/internal/config"
+	"github.com/dietzy1/termify/internal/control"
+)
+
+// RunCLI runs `termify status [flags]`, printing the now playing line once, and returns the exit code.
+// When Termify isn't running it prints the line of a stopped player, so status bars clear it.
+func RunCLI(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("termify status", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", "", "Path to the config file of the instance to ask")
+	socketPath := flags.String("socket", "", "Path to the control socket, overrides --config")
+	format := flags.String("format", config.DefaultNowPlayingFormat, "Go template the line is rendered with")
+	waybar := flags.Bool("waybar", false, "Print JSON for waybar's custom module")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: termify status [flags]")
+		fmt.Fprintln(stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return control.ExitOK
+		}
+		return control.ExitUsage
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return control.ExitUsage
+	}
+
+	tmpl, err := ParseTemplate(*format)
+	if err != nil {
+		fmt.Fprintf(stderr, "termify status: %v\n", err)
+		return control.ExitUsage
+	}
+	path, err := control.SocketPath(*configPath, *socketPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "termify status: %v\n", err)
+		return control.ExitUsage
+	}
+
+	code := control.ExitOK
+	var status control.Status
+	response, err := control.Send(path, control.Request{Command: control.CommandStatus})
+	switch {
+	case errors.Is(err, control.ErrNotRunning):
+		code = control.ExitNotRunning
+	case err != nil:
+		fmt.Fprintf(stderr, "termify status: %v\n", err)
+		return control.ExitFailed
+	case !response.OK:
+		fmt.Fprintf(stderr, "termify status: %s\n", response.Error)
+		code = control.ExitFailed
+	case response.Status != nil:
+		status = *response.Status
+	}
+
+	line, err := Render(tmpl, status, *waybar)
+	if err != nil {
+		fmt.Fprintf(stderr, "termify status: %v\n", err)
+		return control.ExitUsage
+	}
+	fmt.Fprintln(stdout, line)
+	return code
+}
@@ -0,0 +1,128 @@
+package nowplaying
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/dietzy1/termify/internal/control"
+)
+
+// Data is what the now playing template is rendered with
+type Data struct {
+	Title       string
+	Artist      string // The artists joined with commas
+	Artists     []string
+	Album       string
+	URI         string
+	ArtURL      string
+	State       string // playing, paused or stopped
+	Playing     bool
+	Progress    string // m:ss
+	Duration    string // m:ss
+	ProgressMs  int
+	DurationMs  int
+	Percent     int // How far into the track, 0-100
+	Volume      int
+	Shuffle     bool
+	Repeat      string // off, context or track
+	Device      string
+	QueueLength int
+}
+
+// NewData describes the playback status for a template
+func NewData(status control.Status) Data {
+	data := Data{
+		Title:       status.Track,
+		Artist:      strings.Join(status.Artists, ", "),
+		Artists:     status.Artists,
+		Album:       status.Album,
+		URI:         status.URI,
+		ArtURL:      status.ArtURL,
+		State:       "stopped",
+		Playing:     status.Playing,
+		Progress:    control.FormatMs(status.ProgressMs),
+		Duration:    control.FormatMs(status.DurationMs),
+		ProgressMs:  status.ProgressMs,
+		DurationMs:  status.DurationMs,
+		Volume:      status.Volume,
+		Shuffle:     status.Shuffle,
+		Repeat:      status.Repeat,
+		Device:      status.Device,
+		QueueLength: status.QueueLength,
+	}
+	if status.Track != "" {
+		data.State = "paused"
+		if status.Playing {
+			data.State = "playing"
+		}
+	}
+	if status.DurationMs > 0 {
+		data.Percent = status.ProgressMs * 100 / status.DurationMs
+	}
+	return data
+}
+
+var templateFuncs = template.FuncMap{
+	// truncate shortens text to n characters, ending it with an ellipsis
+	"truncate": func(n int, text string) string {
+		runes := []rune(text)
+		if n < 1 || len(runes) <= n {
+			return text
+		}
+		return string(runes[:n-1]) + "…"
+	},
+}
+
+// ParseTemplate parses a now playing format, see Data for the fields it can use
+func ParseTemplate(format string) (*template.Template, error) {
+	tmpl, err := template.New("now_playing").Funcs(templateFuncs).Option("missingkey=zero").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid now playing format: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Render renders the line for the status, as plain text or as waybar JSON
+func Render(tmpl *template.Template, status control.Status, waybar bool) (string, error) {
+	data := NewData(status)
+
+	var text strings.Builder
+	if err := tmpl.Execute(&text, data); err != nil {
+		return "", fmt.Errorf("failed to render now playing format: %w", err)
+	}
+	// Status bars show a single line
+	line := strings.ReplaceAll(text.String(), "\n", " ")
+	if !waybar {
+		return line, nil
+	}
+
+	// https://github.com/Alexays/Waybar/wiki/Module:-Custom, used with "return-type": "json"
+	output := struct {
+		Text       string `json:"text"`
+		Tooltip    string `json:"tooltip"`
+		Alt        string `json:"alt"`
+		Class      string `json:"class"`
+		Percentage int    `json:"percentage"`
+	}{
+		Text:       line,
+		Alt:        data.State,
+		Class:      data.State,
+		Percentage: data.Percent,
+	}
+	if data.Title != "" {
+		var lines []string
+		for _, part := range []string{data.Title, data.Artist, data.Album, data.Progress + " / " + data.Duration} {
+			if part != "" {
+				lines = append(lines, part)
+			}
+		}
+		output.Tooltip = strings.Join(lines, "\n")
+	}
+	encoded, err := json.Marshal(output)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
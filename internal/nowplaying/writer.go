@@ -0,0 +1,165 @@
+package nowplaying
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/dietzy1/termify/internal/control"
+)
+
+// How often the progress is rendered again while playing
+const tickInterval = time.Second
+
+// How long a write waits for a reader to make room in a full pipe
+const pipeWriteTimeout = 100 * time.Millisecond
+
+// Writer keeps the now playing line in a file or named pipe up to date
+type Writer struct {
+	path     string
+	template *template.Template
+	waybar   bool
+	events   *control.Broker
+
+	status  control.Status
+	updated time.Time // When the status arrived, to advance the progress while playing
+	last    string    // The line last written, to only write changes
+	pipe    *os.File  // Open while a reader is attached to the named pipe
+	failing bool      // Whether the last write failed, to only log the first failure
+}
+
+// NewWriter writes to path, which a regular file is created at unless it is a named pipe
+func NewWriter(path, format string, waybar bool, events *control.Broker) (*Writer, error) {
+	tmpl, err := ParseTemplate(format)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{path: path, template: tmpl, waybar: waybar, events: events}, nil
+}
+
+// Serve writes on every player event and every tick until the context is cancelled,
+// then leaves the line of a stopped player behind
+func (w *Writer) Serve(ctx context.Context) {
+	events, unsubscribe := w.events.Subscribe()
+	defer unsubscribe()
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	log.Printf("NowPlaying: Writing to %s", w.path)
+	w.write()
+	defer func() {
+		w.status, w.updated = control.Status{}, time.Now()
+		w.write()
+		if w.pipe != nil {
+			w.pipe.Close()
+		}
+		log.Println("NowPlaying: Stopped")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Type == control.EventPlayer && event.Status != nil {
+				w.status, w.updated = *event.Status, time.Now()
+				w.write()
+			}
+		case <-ticker.C:
+			w.write()
+		}
+	}
+}
+
+func (w *Writer) write() {
+	line, err := Render(w.template, w.status.Advanced(time.Since(w.updated)), w.waybar)
+	if err != nil {
+		w.fail(err)
+		return
+	}
+
+	info, err := os.Stat(w.path)
+	isPipe := err == nil && info.Mode()&os.ModeNamedPipe != 0
+	// A pipe nobody reads yet is tried again, so a new reader gets the line
+	if line == w.last && (!isPipe || w.pipe != nil) {
+		return
+	}
+
+	if isPipe {
+		err = w.writePipe(line)
+	} else {
+		err = writeFile(w.path, line)
+	}
+	if err != nil {
+		w.fail(err)
+		return
+	}
+	w.last = line
+	w.failing = false
+}
+
+// writePipe writes a line to the named pipe once something reads it, like `cat` in a bar module
+func (w *Writer) writePipe(line string) error {
+	if w.pipe == nil {
+		pipe, err := os.OpenFile(w.path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+		if errors.Is(err, syscall.ENXIO) {
+			// Nobody is reading yet, the line is written once a reader opens the pipe
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		w.pipe = pipe
+	}
+
+	// A reader that stopped reading fills the pipe, which would block until it reads again
+	w.pipe.SetWriteDeadline(time.Now().Add(pipeWriteTimeout))
+	if _, err := w.pipe.WriteString(line + "\n"); err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, syscall.EAGAIN) {
+			// The pipe is full, drop this line and keep the reader
+			return nil
+		}
+		// The reader went away
+		w.pipe.Close()
+		w.pipe = nil
+		if errors.Is(err, syscall.EPIPE) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// writeFile replaces the file in one step, so readers never see a partial line
+func writeFile(path, line string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".now_playing-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(line + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (w *Writer) fail(err error) {
+	if !w.failing {
+		log.Printf("NowPlaying: Failed to write %s: %v", w.path, err)
+	}
+	w.failing = true
+}
@@ -0,0 +1,211 @@
+package scrobble
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dietzy1/termify/internal/config"
+)
+
+// The official API root, Libre.fm and other compatible servers have their own
+const lastFMURL = "https://ws.audioscrobbler.com/2.0/"
+
+// Most listens Last.fm takes in one request
+const lastFMBatchSize = 50
+
+// Last.fm error codes, https://www.last.fm/api/errorcodes
+const (
+	lastFMInvalidParameters = 6
+	lastFMInvalidSession    = 9
+)
+
+// lastFM submits listens with the Last.fm scrobbling API, https://www.last.fm/api/scrobbling
+type lastFM struct {
+	url        string
+	apiKey     string
+	apiSecret  string
+	sessionKey string
+	username   string
+	password   string
+	client     *http.Client
+}
+
+// lastFMError is the error Last.fm answers a failed call with
+type lastFMError struct {
+	Code    int    `json:"error"`
+	Message string `json:"message"`
+}
+
+func (e *lastFMError) Error() string {
+	return fmt.Sprintf("error %d: %s", e.Code, e.Message)
+}
+
+func newLastFM(settings config.Scrobble) *lastFM {
+	endpoint := settings.URL
+	if endpoint == "" {
+		endpoint = lastFMURL
+	}
+	return &lastFM{
+		url:        endpoint,
+		apiKey:     settings.APIKey,
+		apiSecret:  settings.APISecret,
+		sessionKey: settings.SessionKey,
+		username:   settings.Username,
+		password:   settings.Password,
+		client:     &http.Client{},
+	}
+}
+
+func (l *lastFM) batchSize() int {
+	return lastFMBatchSize
+}
+
+func (l *lastFM) nowPlaying(ctx context.Context, listen Listen) error {
+	params := url.Values{
+		"method":   {"track.updateNowPlaying"},
+		"artist":   {listen.Artists[0]},
+		"track":    {listen.Track},
+		"duration": {strconv.Itoa(listen.DurationMs / 1000)},
+	}
+	if listen.Album != "" {
+		params.Set("album", listen.Album)
+	}
+	return l.authorizedCall(ctx, params)
+}
+
+func (l *lastFM) submit(ctx context.Context, listens []Listen) error {
+	params := url.Values{"method": {"track.scrobble"}}
+	for i, listen := range listens {
+		// Last.fm credits a track to a single artist
+		params.Set(fmt.Sprintf("artist[%d]", i), listen.Artists[0])
+		params.Set(fmt.Sprintf("track[%d]", i), listen.Track)
+		params.Set(fmt.Sprintf("timestamp[%d]", i), strconv.FormatInt(listen.ListenedAt.Unix(), 10))
+		params.Set(fmt.Sprintf("duration[%d]", i), strconv.Itoa(listen.DurationMs/1000))
+		if listen.Album != "" {
+			params.Set(fmt.Sprintf("album[%d]", i), listen.Album)
+		}
+	}
+
+	err := l.authorizedCall(ctx, params)
+	var apiErr *lastFMError
+	if errors.As(err, &apiErr) && apiErr.Code == lastFMInvalidParameters {
+		return fmt.Errorf("%w: %v", errRejected, err)
+	}
+	return err
+}
+
+// authorizedCall makes a call as the user, logging in first when only a username and password are set
+func (l *lastFM) authorizedCall(ctx context.Context, params url.Values) error {
+	if l.sessionKey == "" {
+		if err := l.login(ctx); err != nil {
+			return err
+		}
+	}
+	params.Set("sk", l.sessionKey)
+
+	err := l.call(ctx, params, nil)
+	var apiErr *lastFMError
+	if errors.As(err, &apiErr) && apiErr.Code == lastFMInvalidSession {
+		if l.password == "" {
+			// A configured session key stays invalid
+			return fmt.Errorf("%w: %v", errUnauthorized, err)
+		}
+		// Log in again on the next call
+		l.sessionKey = ""
+	}
+	return err
+}
+
+// login gets a session key with the username and password
+func (l *lastFM) login(ctx context.Context) error {
+	if l.password == "" {
+		return errors.New("no Last.fm session key")
+	}
+	var result struct {
+		Session struct {
+			Key string `json:"key"`
+		} `json:"session"`
+	}
+	params := url.Values{
+		"method":   {"auth.getMobileSession"},
+		"username": {l.username},
+		"password": {l.password},
+	}
+	if err := l.call(ctx, params, &result); err != nil {
+		return fmt.Errorf("failed to log in to Last.fm: %w", err)
+	}
+	if result.Session.Key == "" {
+		return errors.New("failed to log in to Last.fm: no session key returned")
+	}
+	l.sessionKey = result.Session.Key
+	return nil
+}
+
+// call signs and POSTs the parameters, decoding the answer into result when it is set
+func (l *lastFM) call(ctx context.Context, params url.Values, result any) error {
+	params.Set("api_key", l.apiKey)
+	params.Set("api_sig", l.sign(params))
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.url, strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", "Termify")
+
+	method := params.Get("method")
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	var apiErr lastFMError
+	if json.Unmarshal(data, &apiErr) == nil && apiErr.Code != 0 {
+		return fmt.Errorf("%s: %w", method, &apiErr)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: %s", method, resp.Status)
+	}
+	if result != nil {
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("%s: invalid response: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// sign builds the api_sig, the MD5 of the parameters sorted by name and the secret
+func (l *lastFM) sign(params url.Values) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name != "format" && name != "callback" && name != "api_sig" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var text strings.Builder
+	for _, name := range names {
+		text.WriteString(name)
+		text.WriteString(params.Get(name))
+	}
+	text.WriteString(l.apiSecret)
+	sum := md5.Sum([]byte(text.String()))
+	return hex.EncodeToString(sum[:])
+}
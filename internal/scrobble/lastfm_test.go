@@ -0,0 +1,55 @@
+package scrobble
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestLastFMSign(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		params url.Values
+		want   string
+	}{
+		{
+			name:   "format and signature are left out",
+			secret: "secret",
+			params: url.Values{
+				"method":  {"track.updateNowPlaying"},
+				"track":   {"Song"},
+				"sk":      {"session"},
+				"api_key": {"key"},
+				"format":  {"json"},
+				"api_sig": {"stale"},
+			},
+			want: "de07bf22a23a241124674f427977655d",
+		},
+		{
+			name:   "batch parameters sorted by name",
+			secret: "secret",
+			params: url.Values{
+				"timestamp[1]": {"2"},
+				"artist[1]":    {"B"},
+				"method":       {"track.scrobble"},
+				"artist[0]":    {"A"},
+				"timestamp[0]": {"1"},
+				"api_key":      {"key"},
+			},
+			want: "2f4e3876c6cd3fa8168d3c2ef864ad3d",
+		},
+		{
+			name:   "no secret",
+			params: url.Values{"method": {"auth.getMobileSession"}, "username": {"me"}, "password": {"pw"}},
+			want:   "00411468ac7ff411aad1d7cc6e0d62c4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &lastFM{apiSecret: tt.secret}
+			if got := l.sign(tt.params); got != tt.want {
+				t.Errorf("sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
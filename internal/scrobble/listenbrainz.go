@@ -0,0 +1,123 @@
+package scrobble
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dietzy1/termify/internal/state"
+)
+
+// The official API root, Maloja and other compatible servers have their own
+const listenBrainzURL = "https://api.listenbrainz.org"
+
+// Most listens ListenBrainz takes in one request
+const listenBrainzBatchSize = 1000
+
+// listenBrainz submits listens with the ListenBrainz API, https://listenbrainz.readthedocs.io/en/latest/users/api/core.html
+type listenBrainz struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+type listenBrainzListen struct {
+	ListenedAt    int64                `json:"listened_at,omitempty"`
+	TrackMetadata listenBrainzMetadata `json:"track_metadata"`
+}
+
+type listenBrainzMetadata struct {
+	ArtistName     string         `json:"artist_name"`
+	TrackName      string         `json:"track_name"`
+	ReleaseName    string         `json:"release_name,omitempty"`
+	AdditionalInfo map[string]any `json:"additional_info"`
+}
+
+func newListenBrainz(url, token string) *listenBrainz {
+	if url == "" {
+		url = listenBrainzURL
+	}
+	return &listenBrainz{url: strings.TrimSuffix(url, "/"), token: token, client: &http.Client{}}
+}
+
+func (l *listenBrainz) batchSize() int {
+	return listenBrainzBatchSize
+}
+
+func (l *listenBrainz) nowPlaying(ctx context.Context, listen Listen) error {
+	return l.submitListens(ctx, "playing_now", []Listen{listen})
+}
+
+func (l *listenBrainz) submit(ctx context.Context, listens []Listen) error {
+	if len(listens) == 1 {
+		return l.submitListens(ctx, "single", listens)
+	}
+	return l.submitListens(ctx, "import", listens)
+}
+
+func (l *listenBrainz) submitListens(ctx context.Context, listenType string, listens []Listen) error {
+	payload := make([]listenBrainzListen, 0, len(listens))
+	for _, listen := range listens {
+		info := map[string]any{
+			"artist_names":      listen.Artists,
+			"duration_ms":       listen.DurationMs,
+			"music_service":     "spotify.com",
+			"submission_client": "Termify",
+		}
+		if link, ok := state.ParseSpotifyLink(listen.URI); ok {
+			info["spotify_id"] = link.URL()
+			info["origin_url"] = link.URL()
+		}
+		submitted := listenBrainzListen{TrackMetadata: listenBrainzMetadata{
+			ArtistName:     strings.Join(listen.Artists, ", "),
+			TrackName:      listen.Track,
+			ReleaseName:    listen.Album,
+			AdditionalInfo: info,
+		}}
+		// Playing now has no time yet
+		if listenType != "playing_now" {
+			submitted.ListenedAt = listen.ListenedAt.Unix()
+		}
+		payload = append(payload, submitted)
+	}
+
+	body, err := json.Marshal(map[string]any{"listen_type": listenType, "payload": payload})
+	if err != nil {
+		return err
+	}
+	endpoint := l.url + "/1/submit-listens"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Token "+l.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Termify")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+
+	var failure struct {
+		Error string `json:"error"`
+	}
+	message := resp.Status
+	if data, err := io.ReadAll(io.LimitReader(resp.Body, 4096)); err == nil && json.Unmarshal(data, &failure) == nil && failure.Error != "" {
+		message = fmt.Sprintf("%s: %s", resp.Status, failure.Error)
+	}
+	// A bad request is about the listens, which would fail every time. Anything else,
+	// like an invalid token or the server being down, can be fixed and retried.
+	if resp.StatusCode == http.StatusBadRequest {
+		return fmt.Errorf("POST %s: %w: %s", endpoint, errRejected, message)
+	}
+	return fmt.Errorf("POST %s: %s", endpoint, message)
+}
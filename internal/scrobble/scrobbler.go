@@ -0,0 +1,339 @@
+package scrobble
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dietzy1/termify/internal/config"
+	"github.com/dietzy1/termify/internal/control"
+	"github.com/dietzy1/termify/internal/state"
+)
+
+const (
+	// How often the time listened is counted while no player events arrive
+	tickInterval = 5 * time.Second
+	// Most time counted between two checks, so a suspended machine doesn't count as listening
+	maxStep = 3 * tickInterval
+	// How often listens waiting in the queue are submitted again
+	retryInterval = time.Minute
+	// How long a request to the service may take
+	requestTimeout = 30 * time.Second
+	// Oldest listens are dropped once this many wait in the queue
+	maxQueued = 10000
+)
+
+// The scrobbling rule: tracks shorter than 30 seconds aren't scrobbled, others are once half
+// of them or 4 minutes have been listened to, whichever comes first
+const (
+	minDuration = 30 * time.Second
+	maxRequired = 4 * time.Minute
+)
+
+// A track jumping back to within this of its start is played again
+const restartMargin = 10 * time.Second
+
+// Listen is a track played, as queued on disk and submitted to the service
+type Listen struct {
+	Track      string    `json:"track"`
+	Artists    []string  `json:"artists"`
+	Album      string    `json:"album,omitempty"`
+	URI        string    `json:"uri"`
+	DurationMs int       `json:"duration_ms"`
+	ListenedAt time.Time `json:"listened_at"` // When the track started playing
+}
+
+// service submits listens to a scrobbling API
+type service interface {
+	nowPlaying(ctx context.Context, listen Listen) error
+	submit(ctx context.Context, listens []Listen) error
+	// Most listens submitted in one request
+	batchSize() int
+}
+
+// errRejected is wrapped by errors for listens the service will never accept, which are dropped
+// rather than retried
+var errRejected = errors.New("rejected")
+
+// errUnauthorized is wrapped by errors for credentials the service won't accept until the config
+// is changed. Nothing is sent after it, listens stay queued for the next run.
+var errUnauthorized = errors.New("unauthorized")
+
+// Scrobbler follows playback and submits the tracks listened to. Listens are queued on disk
+// until the service accepted them, so they survive being offline and restarts.
+type Scrobbler struct {
+	service   service
+	name      string
+	queuePath string
+	events    *control.Broker
+
+	// Owned by Serve: the last status and the track being played
+	status  control.Status
+	updated time.Time // When the status was last advanced, to count the time listened
+	current *play
+	pending []Listen // Listens the worker was too busy to take yet
+
+	// Handed from Serve to the worker, which owns the queue and makes the requests
+	listens      chan Listen
+	nowPlaying   chan Listen
+	queue        []Listen
+	failing      bool // Whether the last submission failed, to only log the first failure
+	unauthorized bool // Whether the service refused the credentials, which stops the requests
+}
+
+// play is a track from when it started playing
+type play struct {
+	listen    Listen
+	listened  time.Duration
+	announced bool // Whether it was sent as now playing
+	submitted bool
+}
+
+// New loads the listens queued by an earlier run and creates the client for the configured service
+func New(settings config.Scrobble, queuePath string, events *control.Broker) (*Scrobbler, error) {
+	s := &Scrobbler{
+		name:       settings.Service,
+		queuePath:  queuePath,
+		events:     events,
+		listens:    make(chan Listen, 16),
+		nowPlaying: make(chan Listen, 1),
+	}
+	switch settings.Service {
+	case "listenbrainz":
+		s.service = newListenBrainz(settings.URL, settings.Token)
+	case "lastfm":
+		s.service = newLastFM(settings)
+	default:
+		return nil, fmt.Errorf("unknown scrobble service '%s'", settings.Service)
+	}
+
+	data, err := os.ReadFile(queuePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read queued listens: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &s.queue); err != nil {
+			return nil, fmt.Errorf("failed to parse queued listens in %s: %w", queuePath, err)
+		}
+	}
+	return s, nil
+}
+
+// Serve follows player events until the context is cancelled. Listens not yet accepted stay
+// queued for the next run.
+func (s *Scrobbler) Serve(ctx context.Context) {
+	events, unsubscribe := s.events.Subscribe()
+	defer unsubscribe()
+
+	var worker sync.WaitGroup
+	worker.Go(func() { s.work(ctx) })
+	defer func() {
+		// The worker gives up on requests once cancelled, so it takes the rest quickly
+		for _, listen := range s.pending {
+			s.listens <- listen
+		}
+		close(s.listens)
+		worker.Wait()
+		log.Println("Scrobble: Stopped")
+	}()
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	log.Printf("Scrobble: Submitting listens to %s, %d queued", s.name, len(s.queue))
+	s.updated = time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Type == control.EventPlayer && event.Status != nil {
+				s.update(*event.Status)
+			}
+		case <-ticker.C:
+			s.advance()
+			s.check()
+			s.handOver()
+		}
+	}
+}
+
+// advance counts the time since the last check as listened if the track was playing
+func (s *Scrobbler) advance() {
+	now := time.Now()
+	elapsed := now.Sub(s.updated)
+	if s.current != nil && s.status.Playing {
+		s.current.listened += min(elapsed, maxStep)
+	}
+	s.status, s.updated = s.status.Advanced(elapsed), now
+}
+
+// update follows a new player status, starting a play when the track changed or started over
+func (s *Scrobbler) update(status control.Status) {
+	s.advance()
+	previous := s.status
+	s.status = status
+
+	restarted := status.URI == previous.URI &&
+		time.Duration(status.ProgressMs)*time.Millisecond < restartMargin &&
+		time.Duration(previous.ProgressMs-status.ProgressMs)*time.Millisecond > restartMargin
+	if s.current == nil || status.URI != s.current.listen.URI || restarted {
+		s.current = nil
+		if scrobbleable(status) {
+			s.current = &play{listen: Listen{
+				Track:      status.Track,
+				Artists:    status.Artists,
+				Album:      status.Album,
+				URI:        status.URI,
+				DurationMs: status.DurationMs,
+				ListenedAt: time.Now().Add(-time.Duration(status.ProgressMs) * time.Millisecond).Truncate(time.Second),
+			}}
+		}
+	}
+	s.check()
+}
+
+// check announces the track once it plays and submits it once enough of it was listened to
+func (s *Scrobbler) check() {
+	current := s.current
+	if current == nil {
+		return
+	}
+	if s.status.Playing && !current.announced {
+		current.announced = true
+		// Only the latest track is worth announcing, replace one the worker didn't get to
+		select {
+		case <-s.nowPlaying:
+		default:
+		}
+		s.nowPlaying <- current.listen
+	}
+
+	duration := time.Duration(current.listen.DurationMs) * time.Millisecond
+	if current.submitted || current.listened < min(duration/2, maxRequired) {
+		return
+	}
+	current.submitted = true
+	s.pending = append(s.pending, current.listen)
+	s.handOver()
+}
+
+// handOver passes the pending listens to the worker without waiting for it, those it can't
+// take yet are passed on a later check
+func (s *Scrobbler) handOver() {
+	for len(s.pending) > 0 {
+		select {
+		case s.listens <- s.pending[0]:
+			s.pending = s.pending[1:]
+		default:
+			return
+		}
+	}
+}
+
+// scrobbleable returns whether the status is a track that counts as a listen, podcasts don't
+func scrobbleable(status control.Status) bool {
+	if status.Track == "" || len(status.Artists) == 0 || strings.HasPrefix(status.URI, "spotify:episode:") {
+		return false
+	}
+	return time.Duration(status.DurationMs)*time.Millisecond >= minDuration
+}
+
+// work makes the requests to the service until Serve is done handing over listens
+func (s *Scrobbler) work(ctx context.Context) {
+	retry := time.NewTicker(retryInterval)
+	defer retry.Stop()
+
+	s.flush(ctx)
+	for {
+		select {
+		case listen, ok := <-s.listens:
+			if !ok {
+				return
+			}
+			s.enqueue(listen)
+			s.flush(ctx)
+		case listen := <-s.nowPlaying:
+			if s.unauthorized {
+				continue
+			}
+			requestCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+			if err := s.service.nowPlaying(requestCtx, listen); err != nil && ctx.Err() == nil {
+				if errors.Is(err, errUnauthorized) {
+					s.refused(err)
+				} else {
+					log.Printf("Scrobble: Failed to send now playing: %v", err)
+				}
+			}
+			cancel()
+		case <-retry.C:
+			s.flush(ctx)
+		}
+	}
+}
+
+// enqueue stores a listen on disk before it is submitted
+func (s *Scrobbler) enqueue(listen Listen) {
+	s.queue = append(s.queue, listen)
+	if len(s.queue) > maxQueued {
+		log.Printf("Scrobble: Dropped %d listens, more than %d were queued", len(s.queue)-maxQueued, maxQueued)
+		s.queue = s.queue[len(s.queue)-maxQueued:]
+	}
+	s.save()
+}
+
+// flush submits the queued listens in batches, oldest first, until the service fails
+func (s *Scrobbler) flush(ctx context.Context) {
+	for len(s.queue) > 0 && ctx.Err() == nil && !s.unauthorized {
+		batch := s.queue[:min(len(s.queue), s.service.batchSize())]
+
+		requestCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+		err := s.service.submit(requestCtx, batch)
+		cancel()
+
+		switch {
+		case err == nil:
+			log.Printf("Scrobble: Submitted %d listens", len(batch))
+			s.failing = false
+		case errors.Is(err, errRejected):
+			log.Printf("Scrobble: Dropped %d listens: %v", len(batch), err)
+		case errors.Is(err, errUnauthorized):
+			s.refused(err)
+			return
+		default:
+			if !s.failing && ctx.Err() == nil {
+				log.Printf("Scrobble: Failed to submit %d listens, retrying later: %v", len(s.queue), err)
+			}
+			s.failing = true
+			return
+		}
+		s.queue = s.queue[len(batch):]
+		s.save()
+	}
+}
+
+// refused stops the requests once the service refused the credentials, retrying can't fix them
+func (s *Scrobbler) refused(err error) {
+	s.unauthorized = true
+	log.Printf("Scrobble: %s refused the credentials, not submitting until they are changed, %d listens stay queued: %v", s.name, len(s.queue), err)
+}
+
+func (s *Scrobbler) save() {
+	data, err := json.Marshal(s.queue)
+	if err != nil {
+		log.Printf("Scrobble: Failed to encode queued listens: %v", err)
+		return
+	}
+	if err := state.WriteFileAtomic(s.queuePath, data); err != nil {
+		log.Printf("Scrobble: Failed to save queued listens: %v", err)
+	}
+}
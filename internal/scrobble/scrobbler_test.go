@@ -0,0 +1,260 @@
+package scrobble
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dietzy1/termify/internal/control"
+)
+
+const (
+	testTrack   = "spotify:track:4uLU6hMCjMI75M1A2tKUQC"
+	otherTrack  = "spotify:track:7ouMYWpwJ422jRcDASZB7P"
+	testEpisode = "spotify:episode:512ojhOuo1ktJprKbVcKyQ"
+)
+
+func newTestScrobbler() *Scrobbler {
+	return &Scrobbler{
+		listens:    make(chan Listen, 16),
+		nowPlaying: make(chan Listen, 1),
+		updated:    time.Now(),
+	}
+}
+
+func status(uri string, playing bool, progress, duration time.Duration) *control.Status {
+	return &control.Status{
+		Playing:    playing,
+		Track:      "Song",
+		Artists:    []string{"Artist"},
+		URI:        uri,
+		ProgressMs: int(progress.Milliseconds()),
+		DurationMs: int(duration.Milliseconds()),
+	}
+}
+
+// step is a player event, or a tick when status is nil, the time after the previous step
+type step struct {
+	elapsed time.Duration
+	status  *control.Status
+}
+
+// run makes the scrobbler follow the step as Serve would
+func (st step) run(s *Scrobbler) {
+	s.updated = time.Now().Add(-st.elapsed)
+	if st.status != nil {
+		s.update(*st.status)
+		return
+	}
+	s.advance()
+	s.check()
+}
+
+// ticks is a player event followed by ticks of maxStep until the time was counted
+func ticks(event *control.Status, total time.Duration) []step {
+	steps := []step{{status: event}}
+	for ; total > 0; total -= maxStep {
+		steps = append(steps, step{elapsed: min(total, maxStep)})
+	}
+	return steps
+}
+
+func TestScrobbleRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		steps     []step
+		submitted int
+		announced int
+	}{
+		{
+			name:      "half of the track",
+			steps:     ticks(status(testTrack, true, 0, time.Minute), 30*time.Second),
+			submitted: 1,
+			announced: 1,
+		},
+		{
+			name:      "less than half of the track",
+			steps:     ticks(status(testTrack, true, 0, time.Minute), 29*time.Second),
+			submitted: 0,
+			announced: 1,
+		},
+		{
+			name:      "4 minutes of a long track",
+			steps:     ticks(status(testTrack, true, 0, 20*time.Minute), 4*time.Minute),
+			submitted: 1,
+			announced: 1,
+		},
+		{
+			name:      "less than 4 minutes of a long track",
+			steps:     ticks(status(testTrack, true, 0, 20*time.Minute), 4*time.Minute-time.Second),
+			submitted: 0,
+			announced: 1,
+		},
+		{
+			name:      "track shorter than 30 seconds",
+			steps:     ticks(status(testTrack, true, 0, 29*time.Second), 29*time.Second),
+			submitted: 0,
+			announced: 0,
+		},
+		{
+			name:      "podcast episode",
+			steps:     ticks(status(testEpisode, true, 0, time.Minute), time.Minute),
+			submitted: 0,
+			announced: 0,
+		},
+		{
+			name:      "paused time doesn't count",
+			steps:     ticks(status(testTrack, false, 0, time.Minute), time.Minute),
+			submitted: 0,
+			announced: 0,
+		},
+		{
+			name: "a suspended machine counts one step",
+			steps: []step{
+				{status: status(testTrack, true, 0, time.Minute)},
+				{elapsed: time.Hour},
+			},
+			submitted: 0,
+			announced: 1,
+		},
+		{
+			name: "played again from the start",
+			steps: append(
+				ticks(status(testTrack, true, 0, time.Minute), 30*time.Second),
+				ticks(status(testTrack, true, 2*time.Second, time.Minute), 30*time.Second)...,
+			),
+			submitted: 2,
+			announced: 2,
+		},
+		{
+			name: "seeking back is the same play",
+			steps: append(
+				ticks(status(testTrack, true, 0, time.Minute), 30*time.Second),
+				ticks(status(testTrack, true, 20*time.Second, time.Minute), 30*time.Second)...,
+			),
+			submitted: 1,
+			announced: 1,
+		},
+		{
+			name: "skipped before half",
+			steps: append(
+				ticks(status(testTrack, true, 0, time.Minute), 20*time.Second),
+				ticks(status(otherTrack, true, 0, time.Minute), 30*time.Second)...,
+			),
+			submitted: 1,
+			announced: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScrobbler()
+			announced := 0
+			for _, step := range tt.steps {
+				step.run(s)
+				select {
+				case <-s.nowPlaying:
+					announced++
+				default:
+				}
+			}
+
+			if submitted := len(s.listens) + len(s.pending); submitted != tt.submitted {
+				t.Errorf("submitted %d listens, want %d", submitted, tt.submitted)
+			}
+			if announced != tt.announced {
+				t.Errorf("announced %d tracks, want %d", announced, tt.announced)
+			}
+		})
+	}
+}
+
+func TestCheckDoesNotWaitForWorker(t *testing.T) {
+	s := newTestScrobbler()
+	s.listens = make(chan Listen) // Nobody is receiving
+
+	done := make(chan struct{})
+	go func() {
+		for _, step := range ticks(status(testTrack, true, 0, time.Minute), 30*time.Second) {
+			step.run(s)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("check blocked on the worker")
+	}
+	if len(s.pending) != 1 {
+		t.Fatalf("%d listens pending, want 1", len(s.pending))
+	}
+
+	// Handed over once the worker takes it
+	received := make(chan Listen, 1)
+	go func() { received <- <-s.listens }()
+	for deadline := time.Now().Add(5 * time.Second); len(s.pending) > 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("the pending listen was never handed over")
+		}
+		s.handOver()
+		time.Sleep(time.Millisecond)
+	}
+	if listen := <-received; listen.URI != testTrack {
+		t.Errorf("handed over %s, want %s", listen.URI, testTrack)
+	}
+}
+
+func TestEnqueueTrimsQueue(t *testing.T) {
+	listens := func(from, to int) []Listen {
+		var list []Listen
+		for i := from; i < to; i++ {
+			list = append(list, Listen{Track: strconv.Itoa(i)})
+		}
+		return list
+	}
+
+	tests := []struct {
+		name   string
+		queued int
+		length int
+		oldest string
+	}{
+		{"empty queue", 0, 1, "new"},
+		{"below the limit", 2, 3, "0"},
+		{"one below the limit", maxQueued - 1, maxQueued, "0"},
+		{"full queue drops the oldest", maxQueued, maxQueued, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Scrobbler{
+				queuePath: filepath.Join(t.TempDir(), "scrobble_queue.json"),
+				queue:     listens(0, tt.queued),
+			}
+			s.enqueue(Listen{Track: "new"})
+
+			if len(s.queue) != tt.length {
+				t.Fatalf("%d listens queued, want %d", len(s.queue), tt.length)
+			}
+			if s.queue[0].Track != tt.oldest {
+				t.Errorf("oldest listen is %s, want %s", s.queue[0].Track, tt.oldest)
+			}
+			if newest := s.queue[len(s.queue)-1].Track; newest != "new" {
+				t.Errorf("newest listen is %s, want new", newest)
+			}
+
+			data, err := os.ReadFile(s.queuePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var saved []Listen
+			if err := json.Unmarshal(data, &saved); err != nil {
+				t.Fatal(err)
+			}
+			if len(saved) != tt.length {
+				t.Errorf("%d listens saved, want %d", len(saved), tt.length)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/dietzy1/termify/internal/config"
+	"github.com/dietzy1/termify/internal/control"
+	"github.com/dietzy1/termify/internal/hooks"
+	"github.com/dietzy1/termify/internal/mpris"
+	"github.com/dietzy1/termify/internal/nowplaying"
+	"github.com/dietzy1/termify/internal/scrobble"
+)
+
+// Start runs the configured services that follow playback events, for whichever of the TUI
+// or the daemon owns playback. Each runs on the wait group until the context is cancelled.
+// A service that fails to start is logged and left out, the others still run.
+func Start(ctx context.Context, c *config.Config, events *control.Broker, program control.Sender, wg *sync.WaitGroup) {
+	// Show up to media keys and desktop widgets
+	if c.IsMPRISEnabled() {
+		if server, err := mpris.Connect(events); err != nil {
+			log.Printf("MPRIS disabled: %v", err)
+		} else {
+			wg.Go(func() { server.Serve(ctx, program) })
+		}
+	}
+
+	// Keep the now playing line for status bars up to date
+	if output := c.GetNowPlayingOutput(); output != "" {
+		if writer, err := nowplaying.NewWriter(output, c.GetNowPlayingFormat(), c.IsNowPlayingWaybar(), events); err != nil {
+			log.Printf("Now playing output disabled: %v", err)
+		} else {
+			wg.Go(func() { writer.Serve(ctx) })
+		}
+	}
+
+	// Run the commands and webhooks configured for events
+	if configured := c.GetHooks(); len(configured) > 0 {
+		runner := hooks.NewRunner(configured, events)
+		wg.Go(func() { runner.Serve(ctx) })
+	}
+
+	// Submit the tracks listened to
+	if c.IsScrobbleEnabled() {
+		if scrobbler, err := scrobble.New(c.GetScrobble(), c.GetScrobbleQueueFilePath(), events); err != nil {
+			log.Printf("Scrobbling disabled: %v", err)
+		} else {
+			wg.Go(func() { scrobbler.Serve(ctx) })
+		}
+	}
+}
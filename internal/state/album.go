@@ -29,10 +29,18 @@ func (s *SpotifyState) FetchAlbumTracks(ctx context.Context, albumId spotify.ID)
 				albumId, len(cachedEntry.Tracks))
 
 			s.mu.Lock()
-			s.tracks = make([]spotify.SimpleTrack, len(cachedEntry.Tracks))
-			copy(s.tracks, cachedEntry.Tracks)
+			selected := s.selectedID == albumId
+			if selected {
+				s.tracks = make([]spotify.SimpleTrack, len(cachedEntry.Tracks))
+				copy(s.tracks, cachedEntry.Tracks)
+			}
 			s.mu.Unlock()
 
+			// Another source was selected while this one was queued up
+			if !selected {
+				return nil
+			}
+
 			return TracksUpdatedMsg{
 				SourceID: albumId,
 				Tracks:   cachedEntry.Tracks,
@@ -41,7 +49,14 @@ func (s *SpotifyState) FetchAlbumTracks(ctx context.Context, albumId spotify.ID)
 		}
 
 		log.Printf("SpotifyState: No cache found, fetching from API for album %s", albumId)
+		ctx, cancel := s.selectionContext(ctx, albumId)
+		defer cancel()
+
 		albumTracks, err := s.client.GetAlbum(ctx, albumId)
+		if ctx.Err() != nil {
+			log.Printf("SpotifyState: Fetch of album %s superseded", albumId)
+			return nil
+		}
 		if err != nil {
 			log.Printf("SpotifyState: Error fetching album info: %v", err)
 			return ErrorMsg{
@@ -58,6 +73,10 @@ func (s *SpotifyState) FetchAlbumTracks(ctx context.Context, albumId spotify.ID)
 			if err == spotify.ErrNoMorePages {
 				break
 			}
+			if ctx.Err() != nil {
+				log.Printf("SpotifyState: Fetch of album %s superseded", albumId)
+				return nil
+			}
 			if err != nil {
 				log.Printf("SpotifyState: Error fetching next page of album tracks: %v", err)
 				break
@@ -68,7 +87,8 @@ func (s *SpotifyState) FetchAlbumTracks(ctx context.Context, albumId spotify.ID)
 
 		simpleTracks := make([]spotify.SimpleTrack, 0, len(allTracks))
 		for _, item := range allTracks {
-			item.Album.Name = albumTracks.Name
+			// Album tracks don't embed their album, the release date is needed for the year column
+			item.Album = albumTracks.SimpleAlbum
 			simpleTracks = append(simpleTracks, item)
 		}
 
@@ -76,6 +96,10 @@ func (s *SpotifyState) FetchAlbumTracks(ctx context.Context, albumId spotify.ID)
 
 		log.Printf("SpotifyState: Successfully fetched and cached %d tracks for album %s", len(simpleTracks), albumId)
 
+		if !s.isSelected(albumId) {
+			return nil
+		}
+
 		return TracksUpdatedMsg{
 			SourceID: albumId,
 			Tracks:   simpleTracks,
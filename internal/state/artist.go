@@ -29,10 +29,18 @@ func (s *SpotifyState) FetchTopTracks(ctx context.Context, artistId spotify.ID)
 				artistId, len(cachedEntry.Tracks))
 
 			s.mu.Lock()
-			s.tracks = make([]spotify.SimpleTrack, len(cachedEntry.Tracks))
-			copy(s.tracks, cachedEntry.Tracks)
+			selected := s.selectedID == artistId
+			if selected {
+				s.tracks = make([]spotify.SimpleTrack, len(cachedEntry.Tracks))
+				copy(s.tracks, cachedEntry.Tracks)
+			}
 			s.mu.Unlock()
 
+			// Another source was selected while this one was queued up
+			if !selected {
+				return nil
+			}
+
 			return TracksUpdatedMsg{
 				SourceID: artistId,
 				Tracks:   cachedEntry.Tracks,
@@ -40,7 +48,14 @@ func (s *SpotifyState) FetchTopTracks(ctx context.Context, artistId spotify.ID)
 			}
 		}
 
+		ctx, cancel := s.selectionContext(ctx, artistId)
+		defer cancel()
+
 		topTracks, err := s.client.GetArtistsTopTracks(ctx, artistId, "US")
+		if ctx.Err() != nil {
+			log.Printf("SpotifyState: Fetch of artist %s superseded", artistId)
+			return nil
+		}
 		if err != nil {
 			log.Printf("SpotifyState: Error fetching top tracks for artist %s: %v", artistId, err)
 			return ErrorMsg{
@@ -56,9 +71,14 @@ func (s *SpotifyState) FetchTopTracks(ctx context.Context, artistId spotify.ID)
 		}
 
 		s.updateCacheEntry(artistId, simpleTracks, nil, false, len(simpleTracks), 0, false)
+		s.recordPopularity(topTracks)
 
 		log.Printf("SpotifyState: Successfully fetched and cached %d top tracks for artist %s", len(simpleTracks), artistId)
 
+		if !s.isSelected(artistId) {
+			return nil
+		}
+
 		return TracksUpdatedMsg{
 			SourceID: artistId,
 			Tracks:   simpleTracks,
@@ -0,0 +1,258 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmb3/spotify/v2"
+)
+
+// AutoplayFallback decides what plays once the current view runs out of tracks
+type AutoplayFallback int
+
+const (
+	FallbackStop AutoplayFallback = iota
+	FallbackLoopContext
+	FallbackArtistTopTracks
+	FallbackAlbum
+	FallbackLikedSongs
+	FallbackPlaylist
+)
+
+// Upper bound on liked songs fetched for the shuffled fallback
+const maxLikedSongsFallback = 200
+
+var autoplayFallbackNames = map[AutoplayFallback]string{
+	FallbackStop:            "stop",
+	FallbackLoopContext:     "loop",
+	FallbackArtistTopTracks: "artist",
+	FallbackAlbum:           "album",
+	FallbackLikedSongs:      "liked",
+	FallbackPlaylist:        "playlist",
+}
+
+var autoplayFallbackLabels = map[AutoplayFallback]string{
+	FallbackStop:            "Stop",
+	FallbackLoopContext:     "Loop context",
+	FallbackArtistTopTracks: "Artist top tracks",
+	FallbackAlbum:           "Album",
+	FallbackLikedSongs:      "Shuffled liked songs",
+	FallbackPlaylist:        "Fallback playlist",
+}
+
+// AutoplayFallbackLoadedMsg is emitted once fallback tracks have been added to the queue
+type AutoplayFallbackLoadedMsg struct {
+	Count int
+}
+
+func (f AutoplayFallback) String() string {
+	return autoplayFallbackNames[f]
+}
+
+// Label returns the human readable name of the fallback
+func (f AutoplayFallback) Label() string {
+	return autoplayFallbackLabels[f]
+}
+
+// ParseAutoplayFallback converts a config value into an AutoplayFallback
+func ParseAutoplayFallback(name string) (AutoplayFallback, error) {
+	for fallback, n := range autoplayFallbackNames {
+		if n == name {
+			return fallback, nil
+		}
+	}
+	return FallbackStop, fmt.Errorf("unknown autoplay fallback %q", name)
+}
+
+func (s *SpotifyState) GetAutoplayFallback() AutoplayFallback {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.autoplay.fallback
+}
+
+func (s *SpotifyState) SetAutoplayFallback(fallback AutoplayFallback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.autoplay.fallback = fallback
+}
+
+func (s *SpotifyState) SetFallbackPlaylist(playlistID string) {
+	// Accept both playlist IDs and spotify:playlist: URIs
+	if strings.HasPrefix(playlistID, "spotify:playlist:") {
+		playlistID = strings.Split(playlistID, ":")[2]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.autoplay.fallbackPlaylist = spotify.ID(playlistID)
+}
+
+// CycleAutoplayFallback switches to the next fallback, skipping the playlist fallback when none is configured
+func (s *SpotifyState) CycleAutoplayFallback() AutoplayFallback {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := (s.autoplay.fallback + 1) % AutoplayFallback(len(autoplayFallbackNames))
+	if next == FallbackPlaylist && s.autoplay.fallbackPlaylist == "" {
+		next = FallbackStop
+	}
+	s.autoplay.fallback = next
+
+	log.Printf("SpotifyState: Autoplay fallback set to %s", next)
+	return next
+}
+
+// LoadAutoplayFallback fetches tracks for the active fallback strategy and adds them to the queue
+func (s *SpotifyState) LoadAutoplayFallback(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		s.mu.RLock()
+		fallback := s.autoplay.fallback
+		fallbackPlaylist := s.autoplay.fallbackPlaylist
+		current := s.playerState.Item
+		s.mu.RUnlock()
+
+		log.Printf("SpotifyState: Loading autoplay fallback %s", fallback)
+
+		var tracks []spotify.SimpleTrack
+		var err error
+
+		switch fallback {
+		case FallbackArtistTopTracks:
+			tracks, err = s.fetchArtistFallback(ctx, current)
+		case FallbackAlbum:
+			tracks, err = s.fetchAlbumFallback(ctx, current)
+		case FallbackLikedSongs:
+			tracks, err = s.fetchLikedSongsFallback(ctx)
+		case FallbackPlaylist:
+			tracks, err = s.fetchPlaylistFallback(ctx, fallbackPlaylist)
+		default:
+			return nil
+		}
+
+		if err != nil {
+			log.Printf("SpotifyState: Error loading autoplay fallback %s: %v", fallback, err)
+			return ErrorMsg{
+				Title:   fmt.Sprintf("Failed to Continue With %s", fallback.Label()),
+				Message: err.Error(),
+			}
+		}
+
+		var currentID spotify.ID
+		if current != nil {
+			currentID = current.ID
+		}
+
+		count := 0
+		for _, track := range tracks {
+			if track.ID == "" || track.ID == currentID {
+				continue
+			}
+			s.Queue.Enqueue(track)
+			count++
+		}
+
+		log.Printf("SpotifyState: Queued %d tracks from autoplay fallback %s", count, fallback)
+		return AutoplayFallbackLoadedMsg{Count: count}
+	}
+}
+
+func (s *SpotifyState) fetchArtistFallback(ctx context.Context, current *spotify.FullTrack) ([]spotify.SimpleTrack, error) {
+	if current == nil || len(current.Artists) == 0 {
+		return nil, fmt.Errorf("no artist found for the current track")
+	}
+
+	topTracks, err := s.client.GetArtistsTopTracks(ctx, current.Artists[0].ID, spotify.MarketFromToken)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]spotify.SimpleTrack, 0, len(topTracks))
+	for _, track := range topTracks {
+		track.SimpleTrack.Album = track.Album
+		tracks = append(tracks, track.SimpleTrack)
+	}
+	return tracks, nil
+}
+
+func (s *SpotifyState) fetchAlbumFallback(ctx context.Context, current *spotify.FullTrack) ([]spotify.SimpleTrack, error) {
+	if current == nil || current.Album.ID == "" {
+		return nil, fmt.Errorf("no album found for the current track")
+	}
+
+	album, err := s.client.GetAlbum(ctx, current.Album.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]spotify.SimpleTrack, 0, len(album.Tracks.Tracks))
+	for _, track := range album.Tracks.Tracks {
+		track.Album = album.SimpleAlbum
+		tracks = append(tracks, track)
+	}
+
+	// Continue after the current track, or from the start if it was the last one
+	for i, track := range tracks {
+		if track.ID == current.ID && i < len(tracks)-1 {
+			return tracks[i+1:], nil
+		}
+	}
+	return tracks, nil
+}
+
+func (s *SpotifyState) fetchLikedSongsFallback(ctx context.Context) ([]spotify.SimpleTrack, error) {
+	page, err := s.client.CurrentUsersTracks(ctx, spotify.Limit(50), spotify.Market(spotify.MarketFromToken))
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]spotify.SimpleTrack, 0, maxLikedSongsFallback)
+	for {
+		for _, saved := range page.Tracks {
+			track := saved.SimpleTrack
+			track.Album = saved.Album
+			tracks = append(tracks, track)
+		}
+
+		if len(tracks) >= maxLikedSongsFallback {
+			break
+		}
+		if err := s.client.NextPage(ctx, page); err != nil {
+			if err == spotify.ErrNoMorePages {
+				break
+			}
+			return nil, err
+		}
+	}
+
+	rand.Shuffle(len(tracks), func(i, j int) {
+		tracks[i], tracks[j] = tracks[j], tracks[i]
+	})
+	return tracks, nil
+}
+
+func (s *SpotifyState) fetchPlaylistFallback(ctx context.Context, playlistID spotify.ID) ([]spotify.SimpleTrack, error) {
+	if playlistID == "" {
+		return nil, fmt.Errorf("no fallback playlist configured")
+	}
+
+	page, err := s.client.GetPlaylistItems(ctx, playlistID, spotify.Limit(100), spotify.Market(spotify.MarketFromToken))
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]spotify.SimpleTrack, 0, page.Total)
+	for {
+		tracks = append(tracks, s.convertPlaylistItemsToSimpleTracks(page.Items)...)
+		if err := s.client.NextPage(ctx, page); err != nil {
+			if err == spotify.ErrNoMorePages {
+				break
+			}
+			return nil, err
+		}
+	}
+	return tracks, nil
+}
@@ -0,0 +1,85 @@
+package state
+
+import (
+	"context"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmb3/spotify/v2"
+)
+
+// BulkQueueProgressMsg reports progress while a playlist, album or artist is added to the queue
+type BulkQueueProgressMsg struct {
+	SourceID spotify.ID
+	Kind     string
+	Loaded   int
+	Total    int
+	Done     bool
+	Err      *ErrorMsg // Why queueing stopped, set on the final progress when it failed
+}
+
+// QueueSource adds every track of a playlist, album or artist's top tracks to the queue.
+// Playlists are loaded one page per command, so callers re-issue it until the returned progress is Done.
+func (s *SpotifyState) QueueSource(ctx context.Context, sourceID spotify.ID, kind string) tea.Cmd {
+	return func() tea.Msg {
+		if _, cached := s.GetCachedTracks(sourceID); !cached {
+			log.Printf("SpotifyState: Loading %s %s before queueing", kind, sourceID)
+
+			var fetch tea.Cmd
+			switch kind {
+			case "album":
+				fetch = s.FetchAlbumTracks(ctx, sourceID)
+			case "artist":
+				fetch = s.FetchTopTracks(ctx, sourceID)
+			default:
+				fetch = s.FetchPlaylistTracks(ctx, sourceID)
+			}
+			if errMsg, ok := fetch().(ErrorMsg); ok {
+				return bulkQueueFailed(sourceID, kind, errMsg)
+			}
+		}
+
+		entry, cached := s.GetCachedTracks(sourceID)
+		if !cached {
+			return bulkQueueFailed(sourceID, kind, ErrorMsg{
+				Title:   "Failed to Queue Tracks",
+				Message: "Could not load the tracks to queue.",
+			})
+		}
+
+		if entry.HasMore && entry.NextPage != nil {
+			switch msg := s.FetchNextTracksPage(ctx, sourceID, entry.NextPage)().(type) {
+			case ErrorMsg:
+				return bulkQueueFailed(sourceID, kind, msg)
+			case nil:
+				// The page is already being fetched for the playlist view, give it a moment
+				time.Sleep(100 * time.Millisecond)
+			}
+
+			loaded, total, _, _, _ := s.GetCacheStats(sourceID)
+			return BulkQueueProgressMsg{
+				SourceID: sourceID,
+				Kind:     kind,
+				Loaded:   loaded,
+				Total:    total,
+			}
+		}
+
+		s.Queue.EnqueueAll(entry.Tracks)
+		log.Printf("SpotifyState: Queued %d tracks from %s %s", len(entry.Tracks), kind, sourceID)
+
+		return BulkQueueProgressMsg{
+			SourceID: sourceID,
+			Kind:     kind,
+			Loaded:   len(entry.Tracks),
+			Total:    len(entry.Tracks),
+			Done:     true,
+		}
+	}
+}
+
+// bulkQueueFailed finishes the bulk enqueue with the error, so its progress is cleared
+func bulkQueueFailed(sourceID spotify.ID, kind string, errMsg ErrorMsg) tea.Msg {
+	return BulkQueueProgressMsg{SourceID: sourceID, Kind: kind, Done: true, Err: &errMsg}
+}
@@ -1,6 +1,8 @@
 package state
 
 import (
+	"time"
+
 	"github.com/zmb3/spotify/v2"
 )
 
@@ -11,6 +13,9 @@ type CacheEntry struct {
 	TotalTracks         int
 	OriginalTotalTracks int // Track the original total from Spotify
 	FilteredCount       int // Track cumulative filtered items
+
+	// When each track was added, only set for playlists
+	AddedAt map[spotify.ID]time.Time
 }
 
 func (s *SpotifyState) updateCacheEntry(sourceID spotify.ID, tracks []spotify.SimpleTrack,
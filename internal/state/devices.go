@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -71,6 +72,26 @@ func (s *SpotifyState) FetchDevices(ctx context.Context) tea.Cmd {
 	}
 }
 
+// RefreshDevices fetches the device list without activating a device
+func (s *SpotifyState) RefreshDevices(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		devices, err := s.client.PlayerDevices(ctx)
+		if err != nil {
+			log.Printf("SpotifyState: Error fetching player devices: %v", err)
+			return ErrorMsg{
+				Title:   "Failed to Fetch Devices",
+				Message: err.Error(),
+			}
+		}
+
+		s.mu.Lock()
+		s.deviceState = devices
+		s.mu.Unlock()
+
+		return DevicesUpdatedMsg{}
+	}
+}
+
 func (s *SpotifyState) SelectDevice(ctx context.Context, deviceID spotify.ID) tea.Cmd {
 	return func() tea.Msg {
 
@@ -102,3 +123,54 @@ func (s *SpotifyState) SelectDevice(ctx context.Context, deviceID spotify.ID) te
 		return DevicesUpdatedMsg{}
 	}
 }
+
+// SelectDeviceByName transfers playback to the device with the given name, ignoring case.
+// A device whose name starts with the given name is used when none matches exactly.
+func (s *SpotifyState) SelectDeviceByName(ctx context.Context, name string) tea.Cmd {
+	return func() tea.Msg {
+		devices, err := s.client.PlayerDevices(ctx)
+		if err != nil {
+			log.Printf("SpotifyState: Error fetching player devices: %v", err)
+			return ErrorMsg{
+				Title:   "Failed to Fetch Devices",
+				Message: err.Error(),
+			}
+		}
+
+		s.mu.Lock()
+		s.deviceState = devices
+		s.mu.Unlock()
+
+		device, ok := findDevice(devices, name)
+		if !ok {
+			names := make([]string, len(devices))
+			for i, d := range devices {
+				names[i] = d.Name
+			}
+			log.Printf("SpotifyState: No device named %q among %v", name, names)
+			return ErrorMsg{
+				Title:   fmt.Sprintf("Device %q Not Found", name),
+				Message: fmt.Sprintf("Available devices: %s", strings.Join(names, ", ")),
+			}
+		}
+		if device.Active {
+			log.Printf("SpotifyState: Device %s is already active", device.Name)
+			return DevicesUpdatedMsg{}
+		}
+		return s.SelectDevice(ctx, device.ID)()
+	}
+}
+
+func findDevice(devices []spotify.PlayerDevice, name string) (spotify.PlayerDevice, bool) {
+	for _, device := range devices {
+		if strings.EqualFold(device.Name, name) {
+			return device, true
+		}
+	}
+	for _, device := range devices {
+		if strings.HasPrefix(strings.ToLower(device.Name), strings.ToLower(name)) {
+			return device, true
+		}
+	}
+	return spotify.PlayerDevice{}, false
+}
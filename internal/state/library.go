@@ -0,0 +1,302 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmb3/spotify/v2"
+)
+
+// LibraryUpdatedMsg is emitted after an item was followed or unfollowed and the cached library was patched in place
+type LibraryUpdatedMsg struct {
+	ID       spotify.ID
+	Followed bool
+}
+
+func (s *SpotifyState) GetFollowedArtists() []spotify.FullArtist {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.followedArtists == nil {
+		return nil
+	}
+	artistsCopy := make([]spotify.FullArtist, len(s.followedArtists))
+	copy(artistsCopy, s.followedArtists)
+	return artistsCopy
+}
+
+func (s *SpotifyState) GetSavedAlbums() []spotify.SimpleAlbum {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.savedAlbums == nil {
+		return nil
+	}
+	albumsCopy := make([]spotify.SimpleAlbum, len(s.savedAlbums))
+	copy(albumsCopy, s.savedAlbums)
+	return albumsCopy
+}
+
+// IsFollowingPlaylist reports whether the playlist is followed. The user's own playlists are
+// in the library too but aren't followed, unfollowing one would delete it.
+func (s *SpotifyState) IsFollowingPlaylist(id spotify.ID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.ContainsFunc(s.playlists, func(p spotify.SimplePlaylist) bool {
+		return p.ID == id && !s.ownsPlaylist(p)
+	})
+}
+
+// IsOwnPlaylist reports whether the playlist is one of the current user's library playlists
+func (s *SpotifyState) IsOwnPlaylist(id spotify.ID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.ContainsFunc(s.playlists, func(p spotify.SimplePlaylist) bool {
+		return p.ID == id && s.ownsPlaylist(p)
+	})
+}
+
+// ownsPlaylist must be called with the lock held
+func (s *SpotifyState) ownsPlaylist(playlist spotify.SimplePlaylist) bool {
+	return s.userID != "" && playlist.Owner.ID == string(s.userID)
+}
+
+func (s *SpotifyState) IsFollowingArtist(id spotify.ID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.ContainsFunc(s.followedArtists, func(a spotify.FullArtist) bool { return a.ID == id })
+}
+
+func (s *SpotifyState) IsAlbumSaved(id spotify.ID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.ContainsFunc(s.savedAlbums, func(a spotify.SimpleAlbum) bool { return a.ID == id })
+}
+
+// ToggleFollowPlaylist follows the playlist if it isn't in the library yet, otherwise unfollows it.
+// The user's own playlists are left alone, unfollowing them would delete them.
+func (s *SpotifyState) ToggleFollowPlaylist(ctx context.Context, id spotify.ID) tea.Cmd {
+	return func() tea.Msg {
+		if s.IsOwnPlaylist(id) {
+			return ErrorMsg{
+				Title:   "Can't Unfollow Playlist",
+				Message: "This is your own playlist, unfollowing it would delete it. Delete it in Spotify to remove it.",
+			}
+		}
+		if s.IsFollowingPlaylist(id) {
+			log.Printf("SpotifyState: Unfollowing playlist %s", id)
+			if err := s.client.UnfollowPlaylist(ctx, id); err != nil {
+				log.Printf("SpotifyState: Error unfollowing playlist: %v", err)
+				return ErrorMsg{
+					Title:   "Failed to Unfollow Playlist",
+					Message: err.Error(),
+				}
+			}
+
+			s.mu.Lock()
+			s.playlists = slices.DeleteFunc(s.playlists, func(p spotify.SimplePlaylist) bool { return p.ID == id })
+			s.mu.Unlock()
+			return LibraryUpdatedMsg{ID: id, Followed: false}
+		}
+
+		playlist, err := s.resolvePlaylist(ctx, id)
+		if err != nil {
+			log.Printf("SpotifyState: Error resolving playlist %s: %v", id, err)
+			return ErrorMsg{
+				Title:   "Failed to Follow Playlist",
+				Message: err.Error(),
+			}
+		}
+
+		log.Printf("SpotifyState: Following playlist %s", id)
+		if err := s.client.FollowPlaylist(ctx, id, true); err != nil {
+			log.Printf("SpotifyState: Error following playlist: %v", err)
+			return ErrorMsg{
+				Title:   "Failed to Follow Playlist",
+				Message: err.Error(),
+			}
+		}
+
+		s.mu.Lock()
+		s.playlists = append([]spotify.SimplePlaylist{playlist}, s.playlists...)
+		s.mu.Unlock()
+		return LibraryUpdatedMsg{ID: id, Followed: true}
+	}
+}
+
+// ToggleFollowArtist follows the artist if it isn't followed yet, otherwise unfollows it
+func (s *SpotifyState) ToggleFollowArtist(ctx context.Context, id spotify.ID) tea.Cmd {
+	return func() tea.Msg {
+		if s.IsFollowingArtist(id) {
+			log.Printf("SpotifyState: Unfollowing artist %s", id)
+			if err := s.client.UnfollowArtist(ctx, id); err != nil {
+				log.Printf("SpotifyState: Error unfollowing artist: %v", err)
+				return ErrorMsg{
+					Title:   "Failed to Unfollow Artist",
+					Message: err.Error(),
+				}
+			}
+
+			s.mu.Lock()
+			s.followedArtists = slices.DeleteFunc(s.followedArtists, func(a spotify.FullArtist) bool { return a.ID == id })
+			s.mu.Unlock()
+			return LibraryUpdatedMsg{ID: id, Followed: false}
+		}
+
+		artist, err := s.resolveArtist(ctx, id)
+		if err != nil {
+			log.Printf("SpotifyState: Error resolving artist %s: %v", id, err)
+			return ErrorMsg{
+				Title:   "Failed to Follow Artist",
+				Message: err.Error(),
+			}
+		}
+
+		log.Printf("SpotifyState: Following artist %s", id)
+		if err := s.client.FollowArtist(ctx, id); err != nil {
+			log.Printf("SpotifyState: Error following artist: %v", err)
+			return ErrorMsg{
+				Title:   "Failed to Follow Artist",
+				Message: err.Error(),
+			}
+		}
+
+		s.mu.Lock()
+		s.followedArtists = append([]spotify.FullArtist{artist}, s.followedArtists...)
+		s.mu.Unlock()
+		return LibraryUpdatedMsg{ID: id, Followed: true}
+	}
+}
+
+// ToggleSaveAlbum saves the album to the library if it isn't saved yet, otherwise removes it
+func (s *SpotifyState) ToggleSaveAlbum(ctx context.Context, id spotify.ID) tea.Cmd {
+	return func() tea.Msg {
+		if s.IsAlbumSaved(id) {
+			log.Printf("SpotifyState: Removing album %s from library", id)
+			if err := s.client.RemoveAlbumsFromLibrary(ctx, id); err != nil {
+				log.Printf("SpotifyState: Error removing album: %v", err)
+				return ErrorMsg{
+					Title:   "Failed to Remove Album",
+					Message: err.Error(),
+				}
+			}
+
+			s.mu.Lock()
+			s.savedAlbums = slices.DeleteFunc(s.savedAlbums, func(a spotify.SimpleAlbum) bool { return a.ID == id })
+			s.mu.Unlock()
+			return LibraryUpdatedMsg{ID: id, Followed: false}
+		}
+
+		album, err := s.resolveAlbum(ctx, id)
+		if err != nil {
+			log.Printf("SpotifyState: Error resolving album %s: %v", id, err)
+			return ErrorMsg{
+				Title:   "Failed to Save Album",
+				Message: err.Error(),
+			}
+		}
+
+		log.Printf("SpotifyState: Saving album %s to library", id)
+		if err := s.client.AddAlbumsToLibrary(ctx, id); err != nil {
+			log.Printf("SpotifyState: Error saving album: %v", err)
+			return ErrorMsg{
+				Title:   "Failed to Save Album",
+				Message: err.Error(),
+			}
+		}
+
+		s.mu.Lock()
+		s.savedAlbums = append([]spotify.SimpleAlbum{album}, s.savedAlbums...)
+		s.mu.Unlock()
+		return LibraryUpdatedMsg{ID: id, Followed: true}
+	}
+}
+
+// resolvePlaylist looks the playlist up in the search results before asking the API
+func (s *SpotifyState) resolvePlaylist(ctx context.Context, id spotify.ID) (spotify.SimplePlaylist, error) {
+	for _, playlist := range s.GetSearchResultPlaylists() {
+		if playlist.ID == id {
+			return playlist, nil
+		}
+	}
+
+	playlist, err := s.client.GetPlaylist(ctx, id)
+	if err != nil {
+		return spotify.SimplePlaylist{}, err
+	}
+	return playlist.SimplePlaylist, nil
+}
+
+// resolveArtist looks the artist up in the search results and top artists before asking the API
+func (s *SpotifyState) resolveArtist(ctx context.Context, id spotify.ID) (spotify.FullArtist, error) {
+	candidates := s.GetSearchResultArtists()
+	for _, timeRange := range TopTimeRanges {
+		candidates = append(candidates, s.GetTopArtists(timeRange)...)
+	}
+	for _, artist := range candidates {
+		if artist.ID == id {
+			return artist, nil
+		}
+	}
+
+	artist, err := s.client.GetArtist(ctx, id)
+	if err != nil {
+		return spotify.FullArtist{}, err
+	}
+	return *artist, nil
+}
+
+// resolveAlbum looks the album up in the search results before asking the API
+func (s *SpotifyState) resolveAlbum(ctx context.Context, id spotify.ID) (spotify.SimpleAlbum, error) {
+	for _, album := range s.GetSearchResultAlbums() {
+		if album.ID == id {
+			return album, nil
+		}
+	}
+
+	album, err := s.client.GetAlbum(ctx, id)
+	if err != nil {
+		return spotify.SimpleAlbum{}, err
+	}
+	return album.SimpleAlbum, nil
+}
+
+func (s *SpotifyState) fetchFollowedArtists(ctx context.Context) ([]spotify.FullArtist, error) {
+	var artists []spotify.FullArtist
+	opts := []spotify.RequestOption{spotify.Limit(50)}
+
+	for {
+		page, err := s.client.CurrentUsersFollowedArtists(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch followed artists: %w", err)
+		}
+		artists = append(artists, page.Artists...)
+
+		if page.Cursor.After == "" || len(page.Artists) == 0 {
+			return artists, nil
+		}
+		opts = []spotify.RequestOption{spotify.Limit(50), spotify.After(page.Cursor.After)}
+	}
+}
+
+func (s *SpotifyState) fetchSavedAlbums(ctx context.Context) ([]spotify.SimpleAlbum, error) {
+	page, err := s.client.CurrentUsersAlbums(ctx, spotify.Limit(50), spotify.Market(spotify.MarketFromToken))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch saved albums: %w", err)
+	}
+
+	var albums []spotify.SimpleAlbum
+	for {
+		for _, saved := range page.Albums {
+			albums = append(albums, saved.SimpleAlbum)
+		}
+
+		if err := s.client.NextPage(ctx, page); err != nil {
+			if err == spotify.ErrNoMorePages {
+				return albums, nil
+			}
+			return nil, fmt.Errorf("failed to fetch saved albums: %w", err)
+		}
+	}
+}
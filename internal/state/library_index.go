@@ -0,0 +1,327 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmb3/spotify/v2"
+)
+
+// Attempts at loading a library playlist before the index leaves it out
+const maxLibraryIndexAttempts = 3
+
+// LibraryIndexProgressMsg reports one step of warming the library index
+type LibraryIndexProgressMsg struct {
+	Indexed int // Library playlists with every track loaded
+	Failed  int // Library playlists left out after failing to load
+	Total   int
+	Done    bool
+	Err     error
+}
+
+// LibraryLocation is a row of a library playlist holding a track
+type LibraryLocation struct {
+	PlaylistID   spotify.ID
+	PlaylistName string
+	Index        int // Position in the playlist's loaded tracks
+}
+
+// LibraryMatch is a track found in the library with every playlist row it appears on
+type LibraryMatch struct {
+	Track     spotify.SimpleTrack
+	Locations []LibraryLocation
+}
+
+// libraryIndex maps the tracks of every cached library playlist to the rows they appear on
+type libraryIndex struct {
+	tracks []indexedTrack
+	// Cache entries and loaded track counts the index was built from, used to detect changes
+	sources map[spotify.ID]indexedSource
+}
+
+type indexedTrack struct {
+	track     spotify.SimpleTrack
+	haystack  string // Lowercased title, artists and album
+	locations []LibraryLocation
+}
+
+type indexedSource struct {
+	entry  *CacheEntry
+	loaded int
+}
+
+// GetLibraryIndexProgress returns how many library playlists are fully loaded into the index,
+// and how many were left out after failing to load
+func (s *SpotifyState) GetLibraryIndexProgress() (indexed, failed, total int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, playlist := range s.playlists {
+		entry, ok := s.tracksCache[playlist.ID]
+		switch {
+		case ok && (!entry.HasMore || entry.NextPage == nil):
+			indexed++
+		case s.libraryIndexFailures[playlist.ID] >= maxLibraryIndexAttempts:
+			failed++
+		}
+	}
+	return indexed, failed, len(s.playlists)
+}
+
+// WarmLibraryIndex loads one more page of the first library playlist that isn't fully cached,
+// skipping those that failed to load too often. The caller schedules the next step, which keeps
+// the request rate under its control.
+func (s *SpotifyState) WarmLibraryIndex(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		s.mu.RLock()
+		var target spotify.ID
+		var nextPage *spotify.PlaylistItemPage
+		indexed, failed := 0, 0
+		for _, playlist := range s.playlists {
+			entry, ok := s.tracksCache[playlist.ID]
+			switch {
+			case ok && (!entry.HasMore || entry.NextPage == nil):
+				indexed++
+			case s.libraryIndexFailures[playlist.ID] >= maxLibraryIndexAttempts:
+				failed++
+			case !ok:
+				if target == "" {
+					target = playlist.ID
+				}
+			default:
+				if target == "" {
+					target, nextPage = playlist.ID, entry.NextPage
+				}
+			}
+		}
+		total := len(s.playlists)
+		s.mu.RUnlock()
+
+		if target == "" {
+			log.Printf("SpotifyState: Library index is warm, %d playlists loaded, %d failed", indexed, failed)
+			return LibraryIndexProgressMsg{Indexed: indexed, Failed: failed, Total: total, Done: true}
+		}
+
+		var err error
+		if nextPage == nil {
+			err = s.warmFirstPage(ctx, target)
+		} else {
+			err = s.warmNextPage(ctx, target, nextPage)
+		}
+		if err != nil && ctx.Err() == nil {
+			log.Printf("SpotifyState: Error warming library index with playlist %s: %v", target, err)
+			s.recordLibraryIndexFailure(target, err)
+		}
+		return LibraryIndexProgressMsg{Indexed: indexed, Failed: failed, Total: total, Err: err}
+	}
+}
+
+// recordLibraryIndexFailure counts a failed attempt at loading the playlist. Errors a retry can't
+// fix, like a deleted playlist, leave it out of the index right away.
+func (s *SpotifyState) recordLibraryIndexFailure(playlistID spotify.ID, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.libraryIndexFailures == nil {
+		s.libraryIndexFailures = make(map[spotify.ID]int)
+	}
+	var apiErr spotify.Error
+	if errors.As(err, &apiErr) && apiErr.Status >= 400 && apiErr.Status < 500 && apiErr.Status != http.StatusTooManyRequests {
+		s.libraryIndexFailures[playlistID] = maxLibraryIndexAttempts
+	} else {
+		s.libraryIndexFailures[playlistID]++
+	}
+	if s.libraryIndexFailures[playlistID] >= maxLibraryIndexAttempts {
+		log.Printf("SpotifyState: Leaving playlist %s out of the library index", playlistID)
+	}
+}
+
+func (s *SpotifyState) warmFirstPage(ctx context.Context, playlistID spotify.ID) error {
+	playlistItems, err := s.client.GetPlaylistItems(ctx, playlistID, spotify.Limit(50), spotify.Market(spotify.MarketFromToken))
+	if err != nil {
+		return err
+	}
+
+	simpleTracks := s.convertPlaylistItemsToSimpleTracks(playlistItems.Items)
+	filteredCount := len(playlistItems.Items) - len(simpleTracks)
+	hasMore := len(playlistItems.Items) > 0 && playlistItems.Next != ""
+
+	// The playlist may have been opened while the request was in flight
+	s.mu.RLock()
+	_, exists := s.tracksCache[playlistID]
+	s.mu.RUnlock()
+	if exists {
+		return nil
+	}
+
+	s.updateCacheEntry(playlistID, simpleTracks, playlistItems, hasMore, int(playlistItems.Total), filteredCount, false)
+	s.recordPlaylistItemDetails(playlistID, playlistItems.Items)
+	return nil
+}
+
+func (s *SpotifyState) warmNextPage(ctx context.Context, playlistID spotify.ID, nextPage *spotify.PlaylistItemPage) error {
+	s.mu.Lock()
+	if s.fetchingPages[nextPage.Next] {
+		// The playlist view is loading the same page
+		s.mu.Unlock()
+		return nil
+	}
+	s.fetchingPages[nextPage.Next] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.fetchingPages, nextPage.Next)
+		s.mu.Unlock()
+	}()
+
+	pageToFetch := *nextPage
+	if err := s.client.NextPage(ctx, &pageToFetch); err != nil {
+		if err == spotify.ErrNoMorePages {
+			s.mu.Lock()
+			if entry, exists := s.tracksCache[playlistID]; exists {
+				entry.HasMore = false
+				entry.NextPage = nil
+			}
+			s.mu.Unlock()
+			return nil
+		}
+		return err
+	}
+
+	if !s.isNextPage(playlistID, nextPage) {
+		return nil
+	}
+
+	simpleTracks := s.convertPlaylistItemsToSimpleTracks(pageToFetch.Items)
+	filteredCount := len(pageToFetch.Items) - len(simpleTracks)
+	hasMore := len(pageToFetch.Items) > 0 && pageToFetch.Next != ""
+
+	s.updateCacheEntry(playlistID, simpleTracks, &pageToFetch, hasMore, int(pageToFetch.Total), filteredCount, true)
+	s.recordPlaylistItemDetails(playlistID, pageToFetch.Items)
+	return nil
+}
+
+// isNextPage reports whether page is still the last page loaded for the source, so the page after it
+// hasn't been appended yet by another fetch
+func (s *SpotifyState) isNextPage(sourceID spotify.ID, page *spotify.PlaylistItemPage) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.tracksCache[sourceID]
+	return exists && entry.NextPage != nil && entry.NextPage.Next == page.Next
+}
+
+// SearchLibrary finds tracks of the cached library playlists whose title, artists or album contain
+// every word of the query, each with the playlist rows it appears on
+func (s *SpotifyState) SearchLibrary(query string) []LibraryMatch {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	if s.libraryIndexStale() {
+		s.rebuildLibraryIndex()
+	}
+	index := s.libraryIndex
+	s.mu.Unlock()
+
+	var matches []LibraryMatch
+	for _, track := range index.tracks {
+		if containsAll(track.haystack, terms) {
+			matches = append(matches, LibraryMatch{
+				Track:     track.track,
+				Locations: track.locations,
+			})
+		}
+	}
+	return matches
+}
+
+func containsAll(haystack string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// libraryIndexStale reports whether a library playlist was loaded, changed or removed since the last build.
+// The caller must hold the lock.
+func (s *SpotifyState) libraryIndexStale() bool {
+	cached := 0
+	for _, playlist := range s.playlists {
+		entry, ok := s.tracksCache[playlist.ID]
+		if !ok {
+			continue
+		}
+		cached++
+		source, indexed := s.libraryIndex.sources[playlist.ID]
+		if !indexed || source.entry != entry || source.loaded != len(entry.Tracks) {
+			return true
+		}
+	}
+	return cached != len(s.libraryIndex.sources)
+}
+
+// rebuildLibraryIndex indexes every cached library playlist. The caller must hold the lock.
+func (s *SpotifyState) rebuildLibraryIndex() {
+	index := libraryIndex{sources: make(map[spotify.ID]indexedSource)}
+	byKey := make(map[string]int)
+
+	for _, playlist := range s.playlists {
+		entry, ok := s.tracksCache[playlist.ID]
+		if !ok {
+			continue
+		}
+		index.sources[playlist.ID] = indexedSource{entry: entry, loaded: len(entry.Tracks)}
+
+		for i, track := range entry.Tracks {
+			key := libraryTrackKey(track)
+			position, seen := byKey[key]
+			if !seen {
+				position = len(index.tracks)
+				byKey[key] = position
+				index.tracks = append(index.tracks, indexedTrack{
+					track:    track,
+					haystack: libraryHaystack(track),
+				})
+			}
+			index.tracks[position].locations = append(index.tracks[position].locations, LibraryLocation{
+				PlaylistID:   playlist.ID,
+				PlaylistName: playlist.Name,
+				Index:        i,
+			})
+		}
+	}
+
+	log.Printf("SpotifyState: Rebuilt library index, %d tracks across %d playlists", len(index.tracks), len(index.sources))
+	s.libraryIndex = index
+}
+
+// libraryTrackKey identifies a track across playlists, local files have no ID and fall back to their name
+func libraryTrackKey(track spotify.SimpleTrack) string {
+	if track.ID != "" {
+		return string(track.ID)
+	}
+	artist := ""
+	if len(track.Artists) > 0 {
+		artist = track.Artists[0].Name
+	}
+	return fmt.Sprintf("local:%s:%s", track.Name, artist)
+}
+
+func libraryHaystack(track spotify.SimpleTrack) string {
+	parts := []string{track.Name, track.Album.Name}
+	for _, artist := range track.Artists {
+		parts = append(parts, artist.Name)
+	}
+	return strings.ToLower(strings.Join(parts, " "))
+}
@@ -0,0 +1,196 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmb3/spotify/v2"
+)
+
+// Kinds of Spotify items a link can point to
+var spotifyLinkKinds = map[string]bool{
+	"track":    true,
+	"album":    true,
+	"artist":   true,
+	"playlist": true,
+	"show":     true,
+	"episode":  true,
+}
+
+// Most IDs the tracks endpoint accepts per request
+const getTracksBatchSize = 50
+
+// SpotifyLink is an item referenced by an open.spotify.com URL or a spotify: URI
+type SpotifyLink struct {
+	Kind string // track, album, artist, playlist, show or episode
+	ID   spotify.ID
+}
+
+// URI returns the spotify: URI of the item
+func (l SpotifyLink) URI() spotify.URI {
+	return spotify.URI(fmt.Sprintf("spotify:%s:%s", l.Kind, l.ID))
+}
+
+// URL returns the open.spotify.com URL of the item
+func (l SpotifyLink) URL() string {
+	return fmt.Sprintf("https://open.spotify.com/%s/%s", l.Kind, l.ID)
+}
+
+// ParseSpotifyLink recognizes open.spotify.com URLs, with or without scheme, locale and query,
+// and spotify: URIs including the legacy spotify:user:<name>:playlist:<id> form
+func ParseSpotifyLink(s string) (SpotifyLink, bool) {
+	s = strings.Trim(strings.TrimSpace(s), `<>"'()`)
+
+	var segments []string
+	if strings.HasPrefix(s, "spotify:") {
+		segments = strings.Split(strings.TrimPrefix(s, "spotify:"), ":")
+	} else {
+		if !strings.Contains(s, "://") {
+			s = "https://" + s
+		}
+		u, err := url.Parse(s)
+		if err != nil || (u.Host != "open.spotify.com" && u.Host != "play.spotify.com") {
+			return SpotifyLink{}, false
+		}
+		segments = strings.Split(strings.Trim(u.Path, "/"), "/")
+	}
+
+	// The kind and ID are always the last two segments, after any locale, embed or user prefix
+	if len(segments) < 2 {
+		return SpotifyLink{}, false
+	}
+	kind, id := segments[len(segments)-2], segments[len(segments)-1]
+	if !spotifyLinkKinds[kind] || !isBase62(id) {
+		return SpotifyLink{}, false
+	}
+	return SpotifyLink{Kind: kind, ID: spotify.ID(id)}, true
+}
+
+// ParseSpotifyLinks splits pasted text on whitespace and commas and parses every part.
+// It only succeeds when every part is a link, so a search query is never mistaken for links.
+func ParseSpotifyLinks(text string) ([]SpotifyLink, bool) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	if len(fields) == 0 {
+		return nil, false
+	}
+
+	links := make([]SpotifyLink, 0, len(fields))
+	for _, field := range fields {
+		link, ok := ParseSpotifyLink(field)
+		if !ok {
+			return nil, false
+		}
+		links = append(links, link)
+	}
+	return links, true
+}
+
+func isBase62(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// LinksQueuedMsg reports how many tracks the pasted links added to the queue
+type LinksQueuedMsg struct {
+	Queued  int
+	Skipped []SpotifyLink // Links that point to podcasts or failed to load
+}
+
+// PlayLink plays a track or episode on its own, or a show from its first episode
+func (s *SpotifyState) PlayLink(ctx context.Context, link SpotifyLink) tea.Cmd {
+	operation := func(ctx context.Context) error {
+		playOptions := &spotify.PlayOptions{}
+		switch link.Kind {
+		case "track", "episode":
+			playOptions.URIs = []spotify.URI{link.URI()}
+		default:
+			uri := link.URI()
+			playOptions.PlaybackContext = &uri
+		}
+		return s.client.PlayOpt(ctx, playOptions)
+	}
+	return s.executeWithStateUpdate(ctx, operation, fmt.Sprintf("Play %s", link.URI()))
+}
+
+// QueueLinks adds the tracks behind every link to the queue in the order they were pasted.
+// Playlists, albums and artists add all their tracks, podcasts can't be queued and are skipped.
+func (s *SpotifyState) QueueLinks(ctx context.Context, links []SpotifyLink) tea.Cmd {
+	return func() tea.Msg {
+		var msg LinksQueuedMsg
+		var pending []spotify.ID // Consecutive track links are looked up together
+
+		flush := func() {
+			for len(pending) > 0 {
+				batch := pending[:min(len(pending), getTracksBatchSize)]
+				pending = pending[len(batch):]
+
+				tracks, err := s.client.GetTracks(ctx, batch)
+				if err != nil {
+					log.Printf("SpotifyState: Error loading %d linked tracks: %v", len(batch), err)
+					for _, id := range batch {
+						msg.Skipped = append(msg.Skipped, SpotifyLink{Kind: "track", ID: id})
+					}
+					continue
+				}
+				for _, track := range tracks {
+					if track == nil {
+						continue
+					}
+					track.SimpleTrack.Album = track.Album
+					s.Queue.Enqueue(track.SimpleTrack)
+					msg.Queued++
+				}
+			}
+		}
+
+		for _, link := range links {
+			switch link.Kind {
+			case "track":
+				pending = append(pending, link.ID)
+			case "playlist", "album", "artist":
+				flush()
+				queued, err := s.queueSourceNow(ctx, link.ID, link.Kind)
+				if err != nil {
+					log.Printf("SpotifyState: Error queueing linked %s %s: %v", link.Kind, link.ID, err)
+					msg.Skipped = append(msg.Skipped, link)
+					continue
+				}
+				msg.Queued += queued
+			default:
+				msg.Skipped = append(msg.Skipped, link)
+			}
+		}
+		flush()
+
+		log.Printf("SpotifyState: Queued %d tracks from %d links, skipped %d", msg.Queued, len(links), len(msg.Skipped))
+		return msg
+	}
+}
+
+// queueSourceNow runs QueueSource to completion, returning how many tracks it added
+func (s *SpotifyState) queueSourceNow(ctx context.Context, sourceID spotify.ID, kind string) (int, error) {
+	for {
+		if msg, ok := s.QueueSource(ctx, sourceID, kind)().(BulkQueueProgressMsg); ok && msg.Done {
+			if msg.Err != nil {
+				return 0, fmt.Errorf("%s: %s", msg.Err.Title, msg.Err.Message)
+			}
+			return msg.Loaded, nil
+		}
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestParseSpotifyLink(t *testing.T) {
+	const id = "4uLU6hMCjMI75M1A2tKUQC"
+
+	tests := []struct {
+		name  string
+		input string
+		want  SpotifyLink
+		ok    bool
+	}{
+		{"url", "https://open.spotify.com/track/" + id, SpotifyLink{"track", id}, true},
+		{"url with query", "https://open.spotify.com/album/" + id + "?si=abc123", SpotifyLink{"album", id}, true},
+		{"url without scheme", "open.spotify.com/artist/" + id, SpotifyLink{"artist", id}, true},
+		{"url with trailing slash", "https://open.spotify.com/show/" + id + "/", SpotifyLink{"show", id}, true},
+		{"locale", "https://open.spotify.com/intl-de/track/" + id, SpotifyLink{"track", id}, true},
+		{"embed", "https://open.spotify.com/embed/playlist/" + id, SpotifyLink{"playlist", id}, true},
+		{"legacy user playlist url", "https://open.spotify.com/user/someone/playlist/" + id, SpotifyLink{"playlist", id}, true},
+		{"play.spotify.com", "https://play.spotify.com/episode/" + id, SpotifyLink{"episode", id}, true},
+		{"uri", "spotify:track:" + id, SpotifyLink{"track", id}, true},
+		{"legacy user playlist uri", "spotify:user:someone:playlist:" + id, SpotifyLink{"playlist", id}, true},
+		{"quoted and padded", ` "<https://open.spotify.com/track/` + id + `>" `, SpotifyLink{"track", id}, true},
+		{"id with a dash", "spotify:track:4uLU6hMCjMI7-5M1A2tKUQC", SpotifyLink{}, false},
+		{"id with a dot", "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC.mp3", SpotifyLink{}, false},
+		{"empty id", "spotify:track:", SpotifyLink{}, false},
+		{"missing id", "https://open.spotify.com/track", SpotifyLink{}, false},
+		{"user uri", "spotify:user:someone", SpotifyLink{}, false},
+		{"unknown kind", "https://open.spotify.com/genre/" + id, SpotifyLink{}, false},
+		{"other host", "https://example.com/track/" + id, SpotifyLink{}, false},
+		{"search text", "daft punk", SpotifyLink{}, false},
+		{"empty", "", SpotifyLink{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseSpotifyLink(tt.input)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ParseSpotifyLink(%q) = %+v, %t, want %+v, %t", tt.input, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseSpotifyLinks(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []spotify.ID
+		ok    bool
+	}{
+		{"one per line", "spotify:track:4uLU6hMCjMI75M1A2tKUQC\nspotify:track:7ouMYWpwJ422jRcDASZB7P", []spotify.ID{"4uLU6hMCjMI75M1A2tKUQC", "7ouMYWpwJ422jRcDASZB7P"}, true},
+		{"comma separated", "spotify:track:4uLU6hMCjMI75M1A2tKUQC, spotify:track:7ouMYWpwJ422jRcDASZB7P", []spotify.ID{"4uLU6hMCjMI75M1A2tKUQC", "7ouMYWpwJ422jRcDASZB7P"}, true},
+		{"a link and text", "spotify:track:4uLU6hMCjMI75M1A2tKUQC is great", nil, false},
+		{"only separators", " ,\n", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, ok := ParseSpotifyLinks(tt.input)
+			if ok != tt.ok || len(links) != len(tt.want) {
+				t.Fatalf("ParseSpotifyLinks(%q) = %+v, %t, want %d links, %t", tt.input, links, ok, len(tt.want), tt.ok)
+			}
+			for i, link := range links {
+				if link.ID != tt.want[i] {
+					t.Errorf("link %d is %s, want %s", i, link.ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
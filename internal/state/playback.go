@@ -156,6 +156,14 @@ func (s *SpotifyState) PreviousTrack(ctx context.Context) tea.Cmd {
 	return s.executeWithStateUpdate(ctx, operation, "Skip to Previous Track")
 }
 
+// Seek moves playback of the current track to the position in milliseconds
+func (s *SpotifyState) Seek(ctx context.Context, positionMs int) tea.Cmd {
+	operation := func(ctx context.Context) error {
+		return s.client.Seek(ctx, max(positionMs, 0))
+	}
+	return s.executeWithStateUpdate(ctx, operation, "Seek")
+}
+
 func (s *SpotifyState) PlayTrack(ctx context.Context, trackID spotify.ID) tea.Cmd {
 	operation := func(ctx context.Context) error {
 		playOptions := &spotify.PlayOptions{
@@ -210,3 +218,19 @@ func (s *SpotifyState) ToggleRepeatMode(ctx context.Context) tea.Cmd {
 		return cmd()
 	}
 }
+
+// SetShuffle turns shuffle on or off
+func (s *SpotifyState) SetShuffle(ctx context.Context, shuffle bool) tea.Cmd {
+	operation := func(ctx context.Context) error {
+		return s.client.Shuffle(ctx, shuffle)
+	}
+	return s.executeWithStateUpdate(ctx, operation, fmt.Sprintf("Set Shuffle to %t", shuffle))
+}
+
+// SetRepeatMode sets the repeat mode to off, context or track
+func (s *SpotifyState) SetRepeatMode(ctx context.Context, mode string) tea.Cmd {
+	operation := func(ctx context.Context) error {
+		return s.client.Repeat(ctx, mode)
+	}
+	return s.executeWithStateUpdate(ctx, operation, fmt.Sprintf("Set Repeat Mode to %s", mode))
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strings"
 
@@ -14,6 +15,8 @@ type PlaylistsUpdatedMsg struct {
 
 type PlaylistSelectedMsg struct {
 	PlaylistID string
+	// Kind is the URI type of the selection: playlist, artist or album
+	Kind string
 }
 
 func (s *SpotifyState) FetchPlaylists(ctx context.Context) tea.Cmd {
@@ -28,7 +31,7 @@ func (s *SpotifyState) FetchPlaylists(ctx context.Context) tea.Cmd {
 			}
 		}
 
-		playlists, err := s.client.CurrentUsersPlaylists(ctx, spotify.Market(spotify.MarketFromToken))
+		page, err := s.client.CurrentUsersPlaylists(ctx, spotify.Limit(50), spotify.Market(spotify.MarketFromToken))
 		if err != nil {
 			log.Printf("SpotifyState: Error fetching playlists: %v", err)
 			return ErrorMsg{
@@ -37,29 +40,130 @@ func (s *SpotifyState) FetchPlaylists(ctx context.Context) tea.Cmd {
 			}
 		}
 
+		// The full list is needed to know whether a playlist is already followed
+		playlists := page.Playlists
+		for {
+			err := s.client.NextPage(ctx, page)
+			if err == spotify.ErrNoMorePages {
+				break
+			}
+			if err != nil {
+				log.Printf("SpotifyState: Error fetching next page of playlists: %v", err)
+				break
+			}
+			playlists = append(playlists, page.Playlists...)
+		}
+
+		// Artists and albums are optional, older tokens may lack the scopes to read them
+		artists, err := s.fetchFollowedArtists(ctx)
+		if err != nil {
+			log.Printf("SpotifyState: %v", err)
+		}
+		albums, err := s.fetchSavedAlbums(ctx)
+		if err != nil {
+			log.Printf("SpotifyState: %v", err)
+		}
+		var userID spotify.ID
+		if user, err := s.client.CurrentUser(ctx); err != nil {
+			log.Printf("SpotifyState: Error fetching current user: %v", err)
+		} else {
+			userID = spotify.ID(user.ID)
+		}
+
 		s.mu.Lock()
-		s.playlists = playlists.Playlists
+		s.playlists = playlists
+		s.followedArtists = artists
+		s.savedAlbums = albums
+		if userID != "" {
+			s.userID = userID
+		}
 		s.mu.Unlock()
 
-		log.Printf("SpotifyState: Successfully fetched %d playlists", len(playlists.Playlists))
+		log.Printf("SpotifyState: Successfully fetched %d playlists, %d artists and %d albums",
+			len(playlists), len(artists), len(albums))
 		return PlaylistsUpdatedMsg{}
 	}
 }
 
+// SelectPlaylist selects a library entry by ID or by playlist, artist or album URI
 func (s *SpotifyState) SelectPlaylist(playlistID string) tea.Cmd {
 	return func() tea.Msg {
-		// Clean up the playlist ID format
-		if strings.HasPrefix(playlistID, "spotify:playlist:") {
-			playlistID = strings.Split(playlistID, ":")[2]
+		kind := "playlist"
+		// Clean up the URI format
+		if parts := strings.Split(playlistID, ":"); len(parts) == 3 && parts[0] == "spotify" {
+			kind, playlistID = parts[1], parts[2]
 		}
 
 		s.mu.Lock()
-		s.selectedID = spotify.ID(playlistID)
+		s.setSelectedID(spotify.ID(playlistID))
 		s.mu.Unlock()
 
 		log.Printf("SpotifyState: Selected playlist ID: %s", playlistID)
 		return PlaylistSelectedMsg{
 			PlaylistID: playlistID,
+			Kind:       kind,
+		}
+	}
+}
+
+// Maximum number of tracks the API accepts per add-to-playlist request
+const playlistAddBatchSize = 100
+
+// CreatePlaylist creates a playlist for the current user, fills it with the given tracks and adds it to the library
+func (s *SpotifyState) CreatePlaylist(ctx context.Context, name, description string, public bool, trackIDs []spotify.ID) tea.Cmd {
+	return func() tea.Msg {
+		log.Printf("SpotifyState: Creating playlist %q with %d tracks", name, len(trackIDs))
+
+		user, err := s.client.CurrentUser(ctx)
+		if err != nil {
+			log.Printf("SpotifyState: Error fetching current user: %v", err)
+			return ErrorMsg{
+				Title:   "Failed to Create Playlist",
+				Message: err.Error(),
+			}
+		}
+
+		playlist, err := s.client.CreatePlaylistForUser(ctx, user.ID, name, description, public, false)
+		if err != nil {
+			log.Printf("SpotifyState: Error creating playlist: %v", err)
+			return ErrorMsg{
+				Title:   "Failed to Create Playlist",
+				Message: err.Error(),
+			}
+		}
+
+		// Insert the playlist before adding tracks so a partial failure still shows up in the library
+		s.mu.Lock()
+		s.playlists = append([]spotify.SimplePlaylist{playlist.SimplePlaylist}, s.playlists...)
+		s.mu.Unlock()
+
+		for start := 0; start < len(trackIDs); start += playlistAddBatchSize {
+			end := min(start+playlistAddBatchSize, len(trackIDs))
+			if _, err := s.client.AddTracksToPlaylist(ctx, playlist.ID, trackIDs[start:end]...); err != nil {
+				log.Printf("SpotifyState: Error adding tracks %d-%d to playlist %s: %v", start, end, playlist.ID, err)
+				return tea.BatchMsg{
+					func() tea.Msg { return LibraryUpdatedMsg{ID: playlist.ID, Followed: true} },
+					func() tea.Msg {
+						return ErrorMsg{
+							Title:   "Failed to Add Tracks to Playlist",
+							Message: fmt.Sprintf("Created %q but only %d of %d tracks were added: %v", name, start, len(trackIDs), err),
+						}
+					},
+				}
+			}
+		}
+
+		// Keep the cached track count in sync with what was just added
+		s.mu.Lock()
+		for i := range s.playlists {
+			if s.playlists[i].ID == playlist.ID {
+				s.playlists[i].Tracks.Total = spotify.Numeric(len(trackIDs))
+				break
+			}
 		}
+		s.mu.Unlock()
+
+		log.Printf("SpotifyState: Created playlist %s with %d tracks", playlist.ID, len(trackIDs))
+		return LibraryUpdatedMsg{ID: playlist.ID, Followed: true}
 	}
 }
@@ -2,6 +2,8 @@ package state
 
 import (
 	"errors"
+	"math/rand"
+	"slices"
 	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -27,6 +29,12 @@ func (q *QueueManager) Enqueue(track spotify.SimpleTrack) {
 	q.tracks = append(q.tracks, track)
 }
 
+func (q *QueueManager) EnqueueAll(tracks []spotify.SimpleTrack) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.tracks = append(q.tracks, tracks...)
+}
+
 func (q *QueueManager) Dequeue() (spotify.SimpleTrack, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -86,3 +94,77 @@ func (q *QueueManager) PopAt(index int) (spotify.SimpleTrack, error) {
 
 	return track, nil
 }
+
+// EnqueueNext inserts a track at the front of the queue so it plays next
+func (q *QueueManager) EnqueueNext(track spotify.SimpleTrack) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.tracks = append([]spotify.SimpleTrack{track}, q.tracks...)
+}
+
+// Move moves the track at index from to index to, shifting the tracks in between
+func (q *QueueManager) Move(from, to int) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if from < 0 || from >= len(q.tracks) || to < 0 || to >= len(q.tracks) {
+		return errors.New("index out of range")
+	}
+
+	track := q.tracks[from]
+	q.tracks = slices.Delete(q.tracks, from, from+1)
+	q.tracks = slices.Insert(q.tracks, to, track)
+	return nil
+}
+
+func (q *QueueManager) Clear() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.tracks = nil
+}
+
+// Replace swaps the whole queue for the given tracks
+func (q *QueueManager) Replace(tracks []spotify.SimpleTrack) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.tracks = slices.Clone(tracks)
+}
+
+func (q *QueueManager) Shuffle() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	rand.Shuffle(len(q.tracks), func(i, j int) {
+		q.tracks[i], q.tracks[j] = q.tracks[j], q.tracks[i]
+	})
+}
+
+// RemoveDuplicates keeps the first occurrence of every track and returns how many were removed
+func (q *QueueManager) RemoveDuplicates() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	seen := make(map[spotify.ID]bool, len(q.tracks))
+	before := len(q.tracks)
+	q.tracks = slices.DeleteFunc(q.tracks, func(track spotify.SimpleTrack) bool {
+		if seen[track.ID] {
+			return true
+		}
+		seen[track.ID] = true
+		return false
+	})
+	return before - len(q.tracks)
+}
+
+// RemoveByArtist removes every track featuring the artist and returns how many were removed
+func (q *QueueManager) RemoveByArtist(artistID spotify.ID) int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	before := len(q.tracks)
+	q.tracks = slices.DeleteFunc(q.tracks, func(track spotify.SimpleTrack) bool {
+		return slices.ContainsFunc(track.Artists, func(artist spotify.SimpleArtist) bool {
+			return artist.ID == artistID
+		})
+	})
+	return before - len(q.tracks)
+}
@@ -0,0 +1,99 @@
+package state
+
+import (
+	"context"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmb3/spotify/v2"
+)
+
+// Spotify caps the recently played endpoint at 50 items per request
+const recentlyPlayedPageSize = 50
+
+type RecentlyPlayedUpdatedMsg struct{}
+
+// FetchRecentlyPlayed fetches the first page of recently played tracks and resets the history
+func (s *SpotifyState) FetchRecentlyPlayed(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		log.Printf("SpotifyState: Fetching recently played tracks")
+
+		items, err := s.client.PlayerRecentlyPlayedOpt(ctx, &spotify.RecentlyPlayedOptions{
+			Limit: recentlyPlayedPageSize,
+		})
+		if err != nil {
+			log.Printf("SpotifyState: Error fetching recently played tracks: %v", err)
+			return ErrorMsg{
+				Title:   "Failed to Fetch Recently Played",
+				Message: err.Error(),
+			}
+		}
+
+		s.mu.Lock()
+		s.recentlyPlayed.items = items
+		s.recentlyPlayed.hasMore = len(items) == recentlyPlayedPageSize
+		s.mu.Unlock()
+
+		log.Printf("SpotifyState: Successfully fetched %d recently played tracks", len(items))
+		return RecentlyPlayedUpdatedMsg{}
+	}
+}
+
+// FetchMoreRecentlyPlayed fetches the page of history played before the oldest loaded item
+func (s *SpotifyState) FetchMoreRecentlyPlayed(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		s.mu.Lock()
+		if !s.recentlyPlayed.hasMore || s.recentlyPlayed.fetching || len(s.recentlyPlayed.items) == 0 {
+			s.mu.Unlock()
+			return nil
+		}
+		s.recentlyPlayed.fetching = true
+		// The played_at timestamp of the oldest item acts as the "before" cursor
+		before := s.recentlyPlayed.items[len(s.recentlyPlayed.items)-1].PlayedAt.UnixMilli()
+		s.mu.Unlock()
+
+		defer func() {
+			s.mu.Lock()
+			s.recentlyPlayed.fetching = false
+			s.mu.Unlock()
+		}()
+
+		log.Printf("SpotifyState: Fetching recently played tracks before %d", before)
+		items, err := s.client.PlayerRecentlyPlayedOpt(ctx, &spotify.RecentlyPlayedOptions{
+			Limit:         recentlyPlayedPageSize,
+			BeforeEpochMs: before,
+		})
+		if err != nil {
+			log.Printf("SpotifyState: Error fetching more recently played tracks: %v", err)
+			return ErrorMsg{
+				Title:   "Failed to Fetch Recently Played",
+				Message: err.Error(),
+			}
+		}
+
+		s.mu.Lock()
+		s.recentlyPlayed.items = append(s.recentlyPlayed.items, items...)
+		s.recentlyPlayed.hasMore = len(items) == recentlyPlayedPageSize
+		s.mu.Unlock()
+
+		log.Printf("SpotifyState: Successfully appended %d recently played tracks", len(items))
+		return RecentlyPlayedUpdatedMsg{}
+	}
+}
+
+func (s *SpotifyState) GetRecentlyPlayed() []spotify.RecentlyPlayedItem {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.recentlyPlayed.items == nil {
+		return nil
+	}
+	itemsCopy := make([]spotify.RecentlyPlayedItem, len(s.recentlyPlayed.items))
+	copy(itemsCopy, s.recentlyPlayed.items)
+	return itemsCopy
+}
+
+func (s *SpotifyState) HasMoreRecentlyPlayed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.recentlyPlayed.hasMore
+}
@@ -10,6 +10,29 @@ import (
 
 type SearchResultsUpdatedMsg struct{}
 
+// SearchCategory is one kind of search result
+type SearchCategory int
+
+const (
+	SearchTracks SearchCategory = iota
+	SearchArtists
+	SearchAlbums
+	SearchPlaylists
+)
+
+var searchTypes = [...]spotify.SearchType{
+	SearchTracks:    spotify.SearchTypeTrack,
+	SearchArtists:   spotify.SearchTypeArtist,
+	SearchAlbums:    spotify.SearchTypeAlbum,
+	SearchPlaylists: spotify.SearchTypePlaylist,
+}
+
+const (
+	searchPageSize = 20
+	// The search endpoint rejects offsets past this
+	maxSearchOffset = 1000
+)
+
 func (s *SpotifyState) SearchEverything(ctx context.Context, query string) tea.Cmd {
 	return func() tea.Msg {
 		log.Printf("SpotifyState: Searching for: %s", query)
@@ -17,7 +40,18 @@ func (s *SpotifyState) SearchEverything(ctx context.Context, query string) tea.C
 			return nil
 		}
 
-		results, err := s.client.Search(ctx, query, spotify.SearchTypeTrack|spotify.SearchTypeArtist|spotify.SearchTypeAlbum|spotify.SearchTypePlaylist, spotify.Market("from_token"))
+		// A newer search cancels this one and an older response can't overwrite newer results
+		s.mu.Lock()
+		generation := s.searchWork.supersede()
+		ctx, cancel := s.searchWork.context(ctx)
+		s.mu.Unlock()
+		defer cancel()
+
+		results, err := s.client.Search(ctx, query, spotify.SearchTypeTrack|spotify.SearchTypeArtist|spotify.SearchTypeAlbum|spotify.SearchTypePlaylist, spotify.Market("from_token"), spotify.Limit(searchPageSize))
+		if ctx.Err() != nil {
+			log.Printf("SpotifyState: Search for %q superseded", query)
+			return nil
+		}
 		if err != nil {
 			log.Printf("SpotifyState: Error searching: %v", err)
 			return ErrorMsg{
@@ -31,25 +65,136 @@ func (s *SpotifyState) SearchEverything(ctx context.Context, query string) tea.C
 		log.Printf("SpotifyState: Found %d albums", len(results.Albums.Albums))
 		log.Printf("SpotifyState: Found %d playlists", len(results.Playlists.Playlists))
 
-		// Filter out playlists that are null for some reason
-		filteredPlaylists := make([]spotify.SimplePlaylist, 0, len(results.Playlists.Playlists))
-		for _, p := range results.Playlists.Playlists {
-			if p.Name != "" || p.ID != "" {
-				filteredPlaylists = append(filteredPlaylists, p)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if generation != s.searchWork.generation {
+			return nil
+		}
+
+		s.searchResults.tracks = results.Tracks.Tracks
+		s.searchResults.artists = results.Artists.Artists
+		s.searchResults.albums = results.Albums.Albums
+		s.searchResults.playlists = filterPlaylists(results.Playlists.Playlists)
+		s.searchResults.query = query
+		s.searchResults.totals = [4]int{
+			SearchTracks:    int(results.Tracks.Total),
+			SearchArtists:   int(results.Artists.Total),
+			SearchAlbums:    int(results.Albums.Total),
+			SearchPlaylists: int(results.Playlists.Total),
+		}
+		// Offsets count what the API returned, including playlists that were filtered out
+		s.searchResults.offsets = [4]int{
+			SearchTracks:    len(results.Tracks.Tracks),
+			SearchArtists:   len(results.Artists.Artists),
+			SearchAlbums:    len(results.Albums.Albums),
+			SearchPlaylists: len(results.Playlists.Playlists),
+		}
+
+		return SearchResultsUpdatedMsg{}
+	}
+}
+
+// SearchMore appends the next page of one category to the current search results
+func (s *SpotifyState) SearchMore(ctx context.Context, category SearchCategory) tea.Cmd {
+	return func() tea.Msg {
+		s.mu.Lock()
+		query := s.searchResults.query
+		offset := s.searchResults.offsets[category]
+		if query == "" || s.searchResults.loadingMore[category] || !s.hasMoreSearchResults(category) {
+			s.mu.Unlock()
+			return nil
+		}
+		s.searchResults.loadingMore[category] = true
+		generation := s.searchWork.generation
+		// Pages of a superseded search are cancelled with it
+		ctx, cancel := s.searchWork.context(ctx)
+		s.mu.Unlock()
+		defer cancel()
+
+		defer func() {
+			s.mu.Lock()
+			s.searchResults.loadingMore[category] = false
+			s.mu.Unlock()
+		}()
+
+		log.Printf("SpotifyState: Loading more search results for %q (category %d, offset %d)", query, category, offset)
+		results, err := s.client.Search(ctx, query, searchTypes[category], spotify.Market("from_token"),
+			spotify.Limit(searchPageSize), spotify.Offset(offset))
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			log.Printf("SpotifyState: Error loading more search results: %v", err)
+			return ErrorMsg{
+				Title:   "Failed to Load More Results",
+				Message: err.Error(),
 			}
 		}
 
-		s.setSearchResults(
-			results.Tracks.Tracks,
-			results.Artists.Artists,
-			results.Albums.Albums,
-			filteredPlaylists,
-		)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// A new search replaced the results while this page was loading
+		if generation != s.searchWork.generation || s.searchResults.offsets[category] != offset {
+			return nil
+		}
+
+		received := 0
+		switch category {
+		case SearchTracks:
+			received = len(results.Tracks.Tracks)
+			s.searchResults.tracks = append(s.searchResults.tracks, results.Tracks.Tracks...)
+		case SearchArtists:
+			received = len(results.Artists.Artists)
+			s.searchResults.artists = append(s.searchResults.artists, results.Artists.Artists...)
+		case SearchAlbums:
+			received = len(results.Albums.Albums)
+			s.searchResults.albums = append(s.searchResults.albums, results.Albums.Albums...)
+		case SearchPlaylists:
+			received = len(results.Playlists.Playlists)
+			s.searchResults.playlists = append(s.searchResults.playlists, filterPlaylists(results.Playlists.Playlists)...)
+		}
 
+		s.searchResults.offsets[category] += received
+		if received == 0 {
+			// Totals are estimates, stop once a page comes back empty
+			s.searchResults.totals[category] = s.searchResults.offsets[category]
+		}
 		return SearchResultsUpdatedMsg{}
 	}
 }
 
+// HasMoreSearchResults reports whether another page of the category can be loaded
+func (s *SpotifyState) HasMoreSearchResults(category SearchCategory) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.hasMoreSearchResults(category)
+}
+
+// hasMoreSearchResults is HasMoreSearchResults for callers holding the lock
+func (s *SpotifyState) hasMoreSearchResults(category SearchCategory) bool {
+	offset := s.searchResults.offsets[category]
+	return offset < s.searchResults.totals[category] && offset < maxSearchOffset
+}
+
+// GetSearchResultTotal returns how many results the API reports for the category
+func (s *SpotifyState) GetSearchResultTotal(category SearchCategory) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.searchResults.totals[category]
+}
+
+// filterPlaylists drops the null playlists the search endpoint sometimes returns
+func filterPlaylists(playlists []spotify.SimplePlaylist) []spotify.SimplePlaylist {
+	filtered := make([]spotify.SimplePlaylist, 0, len(playlists))
+	for _, p := range playlists {
+		if p.Name != "" || p.ID != "" {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
 // TODO: Work in progress
 /* func (s *SpotifyState) PlayRecommendTrack( trackID spotify.ID) tea.Cmd {
 	return func() tea.Msg {
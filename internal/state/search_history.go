@@ -0,0 +1,83 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxSearchHistory is how many past queries are kept
+const maxSearchHistory = 100
+
+// SearchHistory holds past search queries, most recent first
+type SearchHistory struct {
+	Queries []string `json:"queries"`
+}
+
+// Add moves the query to the front of the history, dropping an older copy that only differs in case
+func (h *SearchHistory) Add(query string) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return
+	}
+
+	queries := make([]string, 0, min(len(h.Queries)+1, maxSearchHistory))
+	queries = append(queries, query)
+	for _, q := range h.Queries {
+		if len(queries) == maxSearchHistory {
+			break
+		}
+		if !strings.EqualFold(q, query) {
+			queries = append(queries, q)
+		}
+	}
+	h.Queries = queries
+}
+
+// Remove drops the query from the history, returning whether it was there
+func (h *SearchHistory) Remove(query string) bool {
+	queries := make([]string, 0, len(h.Queries))
+	for _, q := range h.Queries {
+		if !strings.EqualFold(q, query) {
+			queries = append(queries, q)
+		}
+	}
+	removed := len(queries) != len(h.Queries)
+	h.Queries = queries
+	return removed
+}
+
+// Clear forgets every query
+func (h *SearchHistory) Clear() {
+	h.Queries = nil
+}
+
+// SaveSearchHistory writes the history to path
+func SaveSearchHistory(path string, history SearchHistory) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal search history: %w", err)
+	}
+	if err := WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to save search history: %w", err)
+	}
+	return nil
+}
+
+// LoadSearchHistory reads the history saved at path, returning an empty history if there is none
+func LoadSearchHistory(path string) (SearchHistory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return SearchHistory{}, nil
+		}
+		return SearchHistory{}, fmt.Errorf("failed to read search history: %w", err)
+	}
+
+	var history SearchHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return SearchHistory{}, fmt.Errorf("failed to unmarshal search history: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,92 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmb3/spotify/v2"
+)
+
+// Session is the part of the application state that survives a restart
+type Session struct {
+	Queue []spotify.SimpleTrack `json:"queue"`
+
+	// The playlist, album or artist open in the table view
+	SelectedID   spotify.ID `json:"selected_id,omitempty"`
+	SelectedKind string     `json:"selected_kind,omitempty"`
+	Focus        string     `json:"focus,omitempty"`
+	TablePage    int        `json:"table_page,omitempty"`
+	TableCursor  int        `json:"table_cursor,omitempty"`
+
+	// What was playing when the session was saved
+	ContextURI spotify.URI `json:"context_uri,omitempty"`
+	TrackID    spotify.ID  `json:"track_id,omitempty"`
+	TrackName  string      `json:"track_name,omitempty"`
+	ArtistName string      `json:"artist_name,omitempty"`
+	ProgressMs int         `json:"progress_ms,omitempty"`
+
+	SavedAt time.Time `json:"saved_at"`
+}
+
+// CanResume reports whether the session has a track to resume playback from
+func (s Session) CanResume() bool {
+	return s.TrackID != ""
+}
+
+// SaveSession writes the session to path without ever leaving a truncated file behind
+func SaveSession(path string, session Session) error {
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if err := WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// LoadSession reads the session saved at path, returning nil if there is none
+func LoadSession(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, nil
+}
+
+// ResumePlayback starts the saved track again at the saved position, inside its context when possible
+func (s *SpotifyState) ResumePlayback(ctx context.Context, session Session) tea.Cmd {
+	operation := func(ctx context.Context) error {
+		trackURI := spotify.URI("spotify:track:" + session.TrackID)
+		playOptions := &spotify.PlayOptions{
+			PositionMs: spotify.Numeric(session.ProgressMs),
+		}
+
+		// Offsets are only supported for album and playlist contexts
+		contextURI := session.ContextURI
+		if strings.HasPrefix(string(contextURI), "spotify:album:") || strings.HasPrefix(string(contextURI), "spotify:playlist:") {
+			playOptions.PlaybackContext = &contextURI
+			playOptions.PlaybackOffset = &spotify.PlaybackOffset{URI: trackURI}
+		} else {
+			playOptions.URIs = []spotify.URI{trackURI}
+		}
+
+		log.Printf("SpotifyState: Resuming %s at %dms (context %q)", session.TrackID, session.ProgressMs, contextURI)
+		return s.client.PlayOpt(ctx, playOptions)
+	}
+	return s.executeWithStateUpdate(ctx, operation, "Resume Playback")
+}
@@ -28,28 +28,68 @@ type SpotifyState struct {
 	playlists []spotify.SimplePlaylist
 	tracks    []spotify.SimpleTrack
 
+	// Followed artists and saved albums shown in the library next to the playlists
+	followedArtists []spotify.FullArtist
+	savedAlbums     []spotify.SimpleAlbum
+
 	fetchingPages map[string]bool
 	tracksCache   map[spotify.ID]*CacheEntry
 
+	// Popularity of every full track seen, SimpleTrack doesn't include it
+	trackPopularity map[spotify.ID]int
+
+	// Tracks of the cached library playlists, rebuilt on search when the cache changed
+	libraryIndex libraryIndex
+	// Failed attempts at loading library playlists for the index, which gives up on them at maxLibraryIndexAttempts
+	libraryIndexFailures map[spotify.ID]int
+
 	searchResults struct {
 		tracks    []spotify.FullTrack
 		artists   []spotify.FullArtist
 		albums    []spotify.SimpleAlbum
 		playlists []spotify.SimplePlaylist
+
+		// Paging of the current query per SearchCategory
+		query       string
+		totals      [4]int
+		offsets     [4]int
+		loadingMore [4]bool
+	}
+
+	recentlyPlayed struct {
+		items    []spotify.RecentlyPlayedItem
+		hasMore  bool
+		fetching bool
+	}
+
+	topItems map[spotify.Range]topItems
+
+	autoplay struct {
+		fallback         AutoplayFallback
+		fallbackPlaylist spotify.ID
 	}
 
 	Queue QueueManager
 
 	selectedID spotify.ID
+
+	// In-flight loads of the selected source and of the current search, cancelled once superseded
+	selectionWork supersedable
+	searchWork    supersedable
+
+	// ID of the logged in user, used to tell which playlists can be edited
+	userID spotify.ID
 }
 
 func NewSpotifyState(client *spotify.Client) *SpotifyState {
 	log.Printf("Creating new SpotifyState with client: %v", client != nil)
 	return &SpotifyState{
-		client:        client,
-		mu:            sync.RWMutex{},
-		tracksCache:   make(map[spotify.ID]*CacheEntry),
-		fetchingPages: make(map[string]bool),
+		client:          client,
+		mu:              sync.RWMutex{},
+		tracksCache:     make(map[spotify.ID]*CacheEntry),
+		trackPopularity: make(map[spotify.ID]int),
+		fetchingPages:   make(map[string]bool),
+		topItems:        make(map[spotify.Range]topItems),
 	}
 }
 
@@ -155,18 +195,8 @@ func (s *SpotifyState) GetSelectedID() spotify.ID {
 	return s.selectedID
 }
 
-func (s *SpotifyState) setSearchResults(tracks []spotify.FullTrack, artists []spotify.FullArtist,
-	albums []spotify.SimpleAlbum, playlists []spotify.SimplePlaylist) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.searchResults.tracks = tracks
-	s.searchResults.artists = artists
-	s.searchResults.albums = albums
-	s.searchResults.playlists = playlists
-}
-
 func (s *SpotifyState) SetSelectedID(id spotify.ID) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.selectedID = id
+	s.setSelectedID(id)
 }
@@ -0,0 +1,83 @@
+package state
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// supersedable tracks the in-flight requests of one kind of work, such as a search or loading the
+// selected source. Starting new work cancels every request of the previous generation, and results
+// that arrive late can tell they are stale by comparing generations. The caller must hold the lock.
+type supersedable struct {
+	generation uint64
+
+	// The requests in flight by ID. Requests remove themselves once they are done, without the
+	// caller's lock, so the map has its own.
+	mu      sync.Mutex
+	nextID  uint64
+	cancels map[uint64]context.CancelFunc
+}
+
+// supersede cancels the requests of the current generation and starts a new one
+func (w *supersedable) supersede() uint64 {
+	w.mu.Lock()
+	for _, cancel := range w.cancels {
+		cancel()
+	}
+	w.cancels = nil
+	w.mu.Unlock()
+
+	w.generation++
+	return w.generation
+}
+
+// context derives a context that is cancelled when the current generation is superseded. Its cancel
+// function also stops tracking the request.
+func (w *supersedable) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancels == nil {
+		w.cancels = make(map[uint64]context.CancelFunc)
+	}
+	id := w.nextID
+	w.nextID++
+	w.cancels[id] = cancel
+
+	return ctx, func() {
+		cancel()
+		w.mu.Lock()
+		delete(w.cancels, id)
+		w.mu.Unlock()
+	}
+}
+
+// setSelectedID selects a source and cancels the loads of the previous one. The caller must hold the lock.
+func (s *SpotifyState) setSelectedID(id spotify.ID) {
+	if id != s.selectedID {
+		s.selectionWork.supersede()
+	}
+	s.selectedID = id
+}
+
+// selectionContext returns the context to load a source's tracks with. Loads of the selected source are
+// cancelled once another source is selected, other sources are loaded for queueing and run to completion.
+func (s *SpotifyState) selectionContext(ctx context.Context, sourceID spotify.ID) (context.Context, context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sourceID != s.selectedID {
+		return context.WithCancel(ctx)
+	}
+	return s.selectionWork.context(ctx)
+}
+
+// isSelected reports whether the source is still the selected one
+func (s *SpotifyState) isSelected(sourceID spotify.ID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectedID == sourceID
+}
@@ -0,0 +1,104 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmb3/spotify/v2"
+)
+
+// TopTimeRanges lists the time ranges supported by the top items endpoints, shortest first
+var TopTimeRanges = []spotify.Range{
+	spotify.ShortTermRange,
+	spotify.MediumTermRange,
+	spotify.LongTermRange,
+}
+
+// How long fetched top items are shown before they are fetched again, they change slowly
+const topItemsTTL = 10 * time.Minute
+
+type TopItemsUpdatedMsg struct {
+	TimeRange spotify.Range
+}
+
+type topItems struct {
+	tracks  []spotify.FullTrack
+	artists []spotify.FullArtist
+	fetched time.Time
+}
+
+// FetchTopItems fetches the current user's top tracks and artists for a time range. Items fetched
+// within topItemsTTL are reused, older ones stay shown until the new ones arrive.
+func (s *SpotifyState) FetchTopItems(ctx context.Context, timeRange spotify.Range) tea.Cmd {
+	return func() tea.Msg {
+		s.mu.RLock()
+		entry, exists := s.topItems[timeRange]
+		s.mu.RUnlock()
+
+		if exists && time.Since(entry.fetched) < topItemsTTL {
+			log.Printf("SpotifyState: Found cached top items for %s", timeRange)
+			return TopItemsUpdatedMsg{TimeRange: timeRange}
+		}
+
+		log.Printf("SpotifyState: Fetching top items for %s", timeRange)
+
+		tracks, err := s.client.CurrentUsersTopTracks(ctx, spotify.Limit(50), spotify.Timerange(timeRange))
+		if err != nil {
+			log.Printf("SpotifyState: Error fetching top tracks: %v", err)
+			return ErrorMsg{
+				Title:   fmt.Sprintf("Failed to Fetch Top Tracks (%s)", timeRange),
+				Message: err.Error(),
+			}
+		}
+
+		artists, err := s.client.CurrentUsersTopArtists(ctx, spotify.Limit(50), spotify.Timerange(timeRange))
+		if err != nil {
+			log.Printf("SpotifyState: Error fetching top artists: %v", err)
+			return ErrorMsg{
+				Title:   fmt.Sprintf("Failed to Fetch Top Artists (%s)", timeRange),
+				Message: err.Error(),
+			}
+		}
+
+		s.mu.Lock()
+		s.topItems[timeRange] = topItems{
+			tracks:  tracks.Tracks,
+			artists: artists.Artists,
+			fetched: time.Now(),
+		}
+		s.mu.Unlock()
+
+		log.Printf("SpotifyState: Successfully fetched %d top tracks and %d top artists for %s",
+			len(tracks.Tracks), len(artists.Artists), timeRange)
+		return TopItemsUpdatedMsg{TimeRange: timeRange}
+	}
+}
+
+func (s *SpotifyState) GetTopTracks(timeRange spotify.Range) []spotify.FullTrack {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.topItems[timeRange]
+	if !exists || entry.tracks == nil {
+		return nil
+	}
+	tracksCopy := make([]spotify.FullTrack, len(entry.tracks))
+	copy(tracksCopy, entry.tracks)
+	return tracksCopy
+}
+
+func (s *SpotifyState) GetTopArtists(timeRange spotify.Range) []spotify.FullArtist {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.topItems[timeRange]
+	if !exists || entry.artists == nil {
+		return nil
+	}
+	artistsCopy := make([]spotify.FullArtist, len(entry.artists))
+	copy(artistsCopy, entry.artists)
+	return artistsCopy
+}
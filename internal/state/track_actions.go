@@ -0,0 +1,188 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmb3/spotify/v2"
+)
+
+// PlaylistTracksChangedMsg is sent after tracks were added to or removed from a playlist
+type PlaylistTracksChangedMsg struct {
+	PlaylistID spotify.ID
+	Added      int
+	Removed    int
+}
+
+// SavedTracksUpdatedMsg is sent after tracks were saved to or removed from Liked Songs
+type SavedTracksUpdatedMsg struct {
+	Count int
+	Saved bool
+}
+
+// Maximum number of tracks the API accepts per library request
+const libraryBatchSize = 50
+
+// PlayTracks plays the tracks in order as an ad-hoc context
+func (s *SpotifyState) PlayTracks(ctx context.Context, trackIDs []spotify.ID) tea.Cmd {
+	operation := func(ctx context.Context) error {
+		uris := make([]spotify.URI, 0, len(trackIDs))
+		for _, id := range trackIDs {
+			uris = append(uris, spotify.URI("spotify:track:"+id))
+		}
+		return s.client.PlayOpt(ctx, &spotify.PlayOptions{URIs: uris})
+	}
+	return s.executeWithStateUpdate(ctx, operation, fmt.Sprintf("Play %d Tracks", len(trackIDs)))
+}
+
+// GetEditablePlaylists returns the library playlists the current user can add tracks to
+func (s *SpotifyState) GetEditablePlaylists() []spotify.SimplePlaylist {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	playlists := make([]spotify.SimplePlaylist, 0, len(s.playlists))
+	for _, playlist := range s.playlists {
+		// Without a known user every playlist is offered and the API decides
+		if s.userID == "" || playlist.Owner.ID == string(s.userID) || playlist.Collaborative {
+			playlists = append(playlists, playlist)
+		}
+	}
+	return playlists
+}
+
+// AddTracksToPlaylist appends the tracks to the playlist
+func (s *SpotifyState) AddTracksToPlaylist(ctx context.Context, playlistID spotify.ID, trackIDs []spotify.ID) tea.Cmd {
+	return func() tea.Msg {
+		log.Printf("SpotifyState: Adding %d tracks to playlist %s", len(trackIDs), playlistID)
+
+		for start := 0; start < len(trackIDs); start += playlistAddBatchSize {
+			end := min(start+playlistAddBatchSize, len(trackIDs))
+			if _, err := s.client.AddTracksToPlaylist(ctx, playlistID, trackIDs[start:end]...); err != nil {
+				log.Printf("SpotifyState: Error adding tracks %d-%d to playlist %s: %v", start, end, playlistID, err)
+				s.invalidateTracks(playlistID)
+				return ErrorMsg{
+					Title:   "Failed to Add Tracks to Playlist",
+					Message: fmt.Sprintf("Only %d of %d tracks were added: %v", start, len(trackIDs), err),
+				}
+			}
+		}
+
+		s.mu.Lock()
+		for i := range s.playlists {
+			if s.playlists[i].ID == playlistID {
+				s.playlists[i].Tracks.Total += spotify.Numeric(len(trackIDs))
+				break
+			}
+		}
+		s.mu.Unlock()
+		s.invalidateTracks(playlistID)
+
+		return PlaylistTracksChangedMsg{PlaylistID: playlistID, Added: len(trackIDs)}
+	}
+}
+
+// RemoveTracksFromPlaylist removes every occurrence of the tracks from the playlist
+func (s *SpotifyState) RemoveTracksFromPlaylist(ctx context.Context, playlistID spotify.ID, trackIDs []spotify.ID) tea.Cmd {
+	// A selection can hold the same track twice, one ID removes every occurrence
+	unique := make([]spotify.ID, 0, len(trackIDs))
+	seen := make(map[spotify.ID]bool, len(trackIDs))
+	for _, id := range trackIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	trackIDs = unique
+
+	return func() tea.Msg {
+		log.Printf("SpotifyState: Removing %d tracks from playlist %s", len(trackIDs), playlistID)
+
+		// Rows removed from the loaded tracks, every track is on at least one of them
+		s.mu.RLock()
+		removedRows := len(trackIDs)
+		if entry, ok := s.tracksCache[playlistID]; ok {
+			rows := 0
+			for _, track := range entry.Tracks {
+				if seen[track.ID] {
+					rows++
+				}
+			}
+			removedRows = max(rows, removedRows)
+		}
+		s.mu.RUnlock()
+
+		for start := 0; start < len(trackIDs); start += playlistAddBatchSize {
+			end := min(start+playlistAddBatchSize, len(trackIDs))
+			if _, err := s.client.RemoveTracksFromPlaylist(ctx, playlistID, trackIDs[start:end]...); err != nil {
+				log.Printf("SpotifyState: Error removing tracks %d-%d from playlist %s: %v", start, end, playlistID, err)
+				s.invalidateTracks(playlistID)
+				return ErrorMsg{
+					Title:   "Failed to Remove Tracks from Playlist",
+					Message: err.Error(),
+				}
+			}
+		}
+
+		s.mu.Lock()
+		for i := range s.playlists {
+			if s.playlists[i].ID == playlistID {
+				s.playlists[i].Tracks.Total = max(s.playlists[i].Tracks.Total-spotify.Numeric(removedRows), 0)
+				break
+			}
+		}
+		s.mu.Unlock()
+		s.invalidateTracks(playlistID)
+
+		return PlaylistTracksChangedMsg{PlaylistID: playlistID, Removed: len(trackIDs)}
+	}
+}
+
+// ToggleSavedTracks removes the tracks from Liked Songs if all of them are saved, otherwise saves them all
+func (s *SpotifyState) ToggleSavedTracks(ctx context.Context, trackIDs []spotify.ID) tea.Cmd {
+	return func() tea.Msg {
+		allSaved := true
+		for start := 0; start < len(trackIDs) && allSaved; start += libraryBatchSize {
+			end := min(start+libraryBatchSize, len(trackIDs))
+			saved, err := s.client.UserHasTracks(ctx, trackIDs[start:end]...)
+			if err != nil {
+				log.Printf("SpotifyState: Error checking saved tracks: %v", err)
+				return ErrorMsg{
+					Title:   "Failed to Update Liked Songs",
+					Message: err.Error(),
+				}
+			}
+			for _, ok := range saved {
+				allSaved = allSaved && ok
+			}
+		}
+
+		for start := 0; start < len(trackIDs); start += libraryBatchSize {
+			end := min(start+libraryBatchSize, len(trackIDs))
+			var err error
+			if allSaved {
+				err = s.client.RemoveTracksFromLibrary(ctx, trackIDs[start:end]...)
+			} else {
+				err = s.client.AddTracksToLibrary(ctx, trackIDs[start:end]...)
+			}
+			if err != nil {
+				log.Printf("SpotifyState: Error updating saved tracks: %v", err)
+				return ErrorMsg{
+					Title:   "Failed to Update Liked Songs",
+					Message: err.Error(),
+				}
+			}
+		}
+
+		log.Printf("SpotifyState: %d tracks saved: %v", len(trackIDs), !allSaved)
+		return SavedTracksUpdatedMsg{Count: len(trackIDs), Saved: !allSaved}
+	}
+}
+
+// invalidateTracks drops the cached tracks of a source so the next fetch reloads it
+func (s *SpotifyState) invalidateTracks(sourceID spotify.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tracksCache, sourceID)
+}
@@ -0,0 +1,70 @@
+package state
+
+import (
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// TrackDetails holds track metadata that spotify.SimpleTrack does not carry
+type TrackDetails struct {
+	// When the track was added to the playlist, zero outside playlists and for very old playlists
+	AddedAt time.Time
+	// Popularity between 0 and 100, -1 when the source didn't include it
+	Popularity int
+}
+
+// GetTrackDetails returns the extra metadata known for a track of a source
+func (s *SpotifyState) GetTrackDetails(sourceID, trackID spotify.ID) TrackDetails {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	details := TrackDetails{Popularity: -1}
+	if popularity, ok := s.trackPopularity[trackID]; ok {
+		details.Popularity = popularity
+	}
+	if entry, ok := s.tracksCache[sourceID]; ok {
+		details.AddedAt = entry.AddedAt[trackID]
+	}
+	return details
+}
+
+// recordPlaylistItemDetails stores the added date and popularity of playlist items.
+// It must run after updateCacheEntry since a first page replaces the cache entry.
+func (s *SpotifyState) recordPlaylistItemDetails(sourceID spotify.ID, items []spotify.PlaylistItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.tracksCache[sourceID]
+	if !ok {
+		return
+	}
+	if entry.AddedAt == nil {
+		entry.AddedAt = make(map[spotify.ID]time.Time, len(items))
+	}
+
+	for _, item := range items {
+		if item.Track.Track == nil || item.Track.Track.ID == "" {
+			continue
+		}
+		id := item.Track.Track.ID
+		s.trackPopularity[id] = int(item.Track.Track.Popularity)
+		// Duplicates keep the date they were first added
+		if _, seen := entry.AddedAt[id]; seen {
+			continue
+		}
+		if addedAt, err := time.Parse(spotify.TimestampLayout, item.AddedAt); err == nil {
+			entry.AddedAt[id] = addedAt
+		}
+	}
+}
+
+// recordPopularity stores the popularity of full tracks
+func (s *SpotifyState) recordPopularity(tracks []spotify.FullTrack) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, track := range tracks {
+		s.trackPopularity[track.ID] = int(track.Popularity)
+	}
+}
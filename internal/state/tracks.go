@@ -35,10 +35,18 @@ func (s *SpotifyState) FetchPlaylistTracks(ctx context.Context, playlistID spoti
 				playlistID, len(cachedEntry.Tracks))
 
 			s.mu.Lock()
-			s.tracks = make([]spotify.SimpleTrack, len(cachedEntry.Tracks))
-			copy(s.tracks, cachedEntry.Tracks)
+			selected := s.selectedID == playlistID
+			if selected {
+				s.tracks = make([]spotify.SimpleTrack, len(cachedEntry.Tracks))
+				copy(s.tracks, cachedEntry.Tracks)
+			}
 			s.mu.Unlock()
 
+			// Another source was selected while this one was queued up
+			if !selected {
+				return nil
+			}
+
 			return TracksUpdatedMsg{
 				SourceID: playlistID,
 				Tracks:   cachedEntry.Tracks,
@@ -47,7 +55,14 @@ func (s *SpotifyState) FetchPlaylistTracks(ctx context.Context, playlistID spoti
 		}
 
 		log.Printf("SpotifyState: No cache found, fetching first page from API for playlist %s", playlistID)
+		ctx, cancel := s.selectionContext(ctx, playlistID)
+		defer cancel()
+
 		playlistItems, err := s.client.GetPlaylistItems(ctx, playlistID, spotify.Limit(50), spotify.Market(spotify.MarketFromToken))
+		if ctx.Err() != nil {
+			log.Printf("SpotifyState: Fetch of playlist %s superseded", playlistID)
+			return nil
+		}
 		if err != nil {
 			log.Printf("SpotifyState: Error fetching playlist items: %v", err)
 			return ErrorMsg{
@@ -67,12 +82,18 @@ func (s *SpotifyState) FetchPlaylistTracks(ctx context.Context, playlistID spoti
 		}
 
 		s.updateCacheEntry(playlistID, simpleTracks, playlistItems, hasMore, int(totalTracks), filteredCount, false)
+		s.recordPlaylistItemDetails(playlistID, playlistItems.Items)
 
 		// Get the adjusted total from cache for logging
 		adjustedTotal := s.GetTotalTracks(playlistID)
 		log.Printf("SpotifyState: Successfully fetched first page: %d tracks (filtered %d, adjusted total: %d), hasMore: %v",
 			len(simpleTracks), filteredCount, adjustedTotal, hasMore)
 
+		// The page is cached either way, but only the selected source is shown
+		if !s.isSelected(playlistID) {
+			return nil
+		}
+
 		return TracksUpdatedMsg{
 			SourceID: playlistID,
 			Tracks:   simpleTracks,
@@ -107,7 +128,14 @@ func (s *SpotifyState) FetchNextTracksPage(ctx context.Context, sourceID spotify
 
 		pageToFetch := *nextPage
 
+		ctx, cancel := s.selectionContext(ctx, sourceID)
+		defer cancel()
+
 		err := s.client.NextPage(ctx, &pageToFetch)
+		if ctx.Err() != nil {
+			log.Printf("SpotifyState: Fetch of next page for source %s superseded", sourceID)
+			return nil
+		}
 		if err != nil {
 			if err == spotify.ErrNoMorePages {
 				log.Printf("SpotifyState: No more pages available")
@@ -126,6 +154,12 @@ func (s *SpotifyState) FetchNextTracksPage(ctx context.Context, sourceID spotify
 			}
 		}
 
+		// The library index warms pages in the background and may have appended this one already
+		if !s.isNextPage(sourceID, nextPage) {
+			log.Printf("SpotifyState: Page already loaded for source %s", sourceID)
+			return nil
+		}
+
 		log.Printf("SpotifyState: Successfully fetched next page, %d new items", len(pageToFetch.Items))
 
 		newSimpleTracks := s.convertPlaylistItemsToSimpleTracks(pageToFetch.Items)
@@ -138,6 +172,7 @@ func (s *SpotifyState) FetchNextTracksPage(ctx context.Context, sourceID spotify
 		}
 
 		s.updateCacheEntry(sourceID, newSimpleTracks, &pageToFetch, hasMore, int(pageToFetch.Total), filteredCount, true)
+		s.recordPlaylistItemDetails(sourceID, pageToFetch.Items)
 
 		// Get the adjusted total from cache for logging
 		adjustedTotal := s.GetTotalTracks(sourceID)
@@ -166,10 +201,17 @@ func (s *SpotifyState) FetchArtistTopTracks(ctx context.Context, artistID spotif
 			log.Printf("SpotifyState: Found cached tracks for artist %s", artistID)
 
 			s.mu.Lock()
-			s.tracks = make([]spotify.SimpleTrack, len(cachedEntry.Tracks))
-			copy(s.tracks, cachedEntry.Tracks)
+			selected := s.selectedID == artistID
+			if selected {
+				s.tracks = make([]spotify.SimpleTrack, len(cachedEntry.Tracks))
+				copy(s.tracks, cachedEntry.Tracks)
+			}
 			s.mu.Unlock()
 
+			if !selected {
+				return nil
+			}
+
 			return TracksUpdatedMsg{
 				SourceID: artistID,
 				Tracks:   cachedEntry.Tracks,
@@ -178,7 +220,14 @@ func (s *SpotifyState) FetchArtistTopTracks(ctx context.Context, artistID spotif
 		}
 
 		// Fetch artist top tracks
+		ctx, cancel := s.selectionContext(ctx, artistID)
+		defer cancel()
+
 		topTracks, err := s.client.GetArtistsTopTracks(ctx, artistID, country)
+		if ctx.Err() != nil {
+			log.Printf("SpotifyState: Fetch of top tracks for artist %s superseded", artistID)
+			return nil
+		}
 		if err != nil {
 			log.Printf("SpotifyState: Error fetching artist top tracks: %v", err)
 			return ErrorMsg{
@@ -195,9 +244,15 @@ func (s *SpotifyState) FetchArtistTopTracks(ctx context.Context, artistID spotif
 
 		// Update cache and current state - artist top tracks are usually all playable, so no filtering
 		s.updateCacheEntry(artistID, simpleTracks, nil, false, len(simpleTracks), 0, false)
+		s.recordPopularity(topTracks)
 
 		log.Printf("SpotifyState: Successfully fetched %d artist top tracks", len(simpleTracks))
 
+		// The tracks are cached either way, but only the selected source is shown
+		if !s.isSelected(artistID) {
+			return nil
+		}
+
 		return TracksUpdatedMsg{
 			SourceID: artistID,
 			Tracks:   simpleTracks,
@@ -229,9 +284,9 @@ func (s *SpotifyState) convertPlaylistItemsToSimpleTracks(items []spotify.Playli
 
 		track := item.Track.Track.SimpleTrack
 
-		// Fix album name issue - copy from full track if simple track album name is empty
+		// Fix album name issue - copy the album from the full track if the simple track has none
 		if track.Album.Name == "" && item.Track.Track.Album.Name != "" {
-			track.Album.Name = item.Track.Track.Album.Name
+			track.Album = item.Track.Track.Album
 		}
 
 		simpleTracks = append(simpleTracks, track)
@@ -91,3 +91,25 @@ func (s *SpotifyState) Mute(ctx context.Context) tea.Cmd {
 		return PlayerStateUpdatedMsg{}
 	}
 }
+
+// SetVolume sets the volume to a percentage between 0 and 100
+func (s *SpotifyState) SetVolume(ctx context.Context, percent int) tea.Cmd {
+	return func() tea.Msg {
+		newVolume := spotify.Numeric(max(0, min(percent, 100)))
+
+		err := s.client.Volume(ctx, int(newVolume))
+		if err != nil {
+			log.Printf("SpotifyState: Error setting volume: %v", err)
+			return ErrorMsg{
+				Title:   "Failed to Set Volume",
+				Message: err.Error(),
+			}
+		}
+
+		s.mu.Lock()
+		s.playerState.Device.Volume = newVolume
+		s.mu.Unlock()
+
+		return PlayerStateUpdatedMsg{}
+	}
+}
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
+)
+
+const (
+	addToPlaylistDialogWidth = 40
+	// Number of playlists listed at once, the list scrolls with the cursor
+	addToPlaylistVisibleRows = 8
+)
+
+type addToPlaylistDialogContent struct {
+	ctx          context.Context
+	width        int
+	height       int
+	spotifyState *state.SpotifyState
+	trackIDs     []spotify.ID
+	playlists    []spotify.SimplePlaylist
+	cursor       int
+}
+
+func NewAddToPlaylistDialog(ctx context.Context, spotifyState *state.SpotifyState, trackIDs []spotify.ID) DialogContent {
+	return &addToPlaylistDialogContent{
+		ctx:          ctx,
+		spotifyState: spotifyState,
+		trackIDs:     trackIDs,
+		playlists:    spotifyState.GetEditablePlaylists(),
+	}
+}
+
+func (m *addToPlaylistDialogContent) Init() tea.Cmd {
+	return nil
+}
+
+func (m *addToPlaylistDialogContent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return m, nil
+}
+
+func (m *addToPlaylistDialogContent) View() string {
+	if len(m.playlists) == 0 {
+		return lipgloss.NewStyle().
+			Width(addToPlaylistDialogWidth).
+			Align(lipgloss.Center).
+			Foreground(TextColor).
+			Render("No playlists you can edit")
+	}
+
+	itemStyle := lipgloss.NewStyle().
+		Width(addToPlaylistDialogWidth).
+		MaxWidth(addToPlaylistDialogWidth).
+		PaddingLeft(2)
+
+	start := max(0, min(m.cursor-addToPlaylistVisibleRows/2, len(m.playlists)-addToPlaylistVisibleRows))
+	end := min(start+addToPlaylistVisibleRows, len(m.playlists))
+
+	rows := []string{
+		lipgloss.NewStyle().
+			Foreground(TextColor).
+			Width(addToPlaylistDialogWidth).
+			Align(lipgloss.Center).
+			MarginBottom(1).
+			Render(fmt.Sprintf("Add %d tracks to", len(m.trackIDs))),
+	}
+	for i := start; i < end; i++ {
+		if i == m.cursor {
+			rows = append(rows, itemStyle.Foreground(WhiteTextColor).Bold(true).Render("→ "+m.playlists[i].Name))
+		} else {
+			rows = append(rows, itemStyle.Foreground(TextColor).Render("  "+m.playlists[i].Name))
+		}
+	}
+
+	navHint := lipgloss.NewStyle().
+		Foreground(TextColor).
+		Italic(true).
+		Width(addToPlaylistDialogWidth).
+		Align(lipgloss.Center).
+		MarginTop(1).
+		Render(fmt.Sprintf("Use ↑↓ to navigate (%d/%d)", m.cursor+1, len(m.playlists)))
+
+	return lipgloss.JoinVertical(lipgloss.Left, append(rows, navHint)...)
+}
+
+func (m *addToPlaylistDialogContent) GetTitle() string {
+	return "Add to Playlist"
+}
+
+func (m *addToPlaylistDialogContent) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m *addToPlaylistDialogContent) HandleDialogKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if len(m.playlists) == 0 {
+		return false, nil
+	}
+	switch {
+	case key.Matches(msg, key.NewBinding(key.WithKeys("up", "k"))):
+		m.cursor = (m.cursor - 1 + len(m.playlists)) % len(m.playlists)
+		return true, nil
+	case key.Matches(msg, key.NewBinding(key.WithKeys("down", "j"))):
+		m.cursor = (m.cursor + 1) % len(m.playlists)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (m *addToPlaylistDialogContent) GetActions() []DialogAction {
+	addCmd := func() tea.Msg {
+		if len(m.playlists) == 0 || len(m.trackIDs) == 0 {
+			return DialogMsg{Accepted: false}
+		}
+		return m.spotifyState.AddTracksToPlaylist(m.ctx, m.playlists[m.cursor].ID, m.trackIDs)()
+	}
+
+	return []DialogAction{
+		{
+			Label: "Add",
+			Key:   key.NewBinding(key.WithKeys("enter")),
+			Cmd:   addCmd,
+		},
+		{
+			Label: "Cancel",
+			Key:   key.NewBinding(key.WithKeys("esc")),
+			Cmd: func() tea.Msg {
+				return DialogMsg{Accepted: false}
+			},
+		},
+	}
+}
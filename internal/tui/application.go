@@ -2,6 +2,7 @@ package tui
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	"github.com/charmbracelet/bubbles/cursor"
@@ -9,6 +10,8 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/config"
+	"github.com/dietzy1/termify/internal/control"
 	"github.com/dietzy1/termify/internal/state"
 	"github.com/zmb3/spotify/v2"
 )
@@ -28,15 +31,49 @@ type applicationModel struct {
 	searchBar       searchbarModel
 	playlistView    playlistViewModel
 	searchView      searchViewModel
+	historyView     historyViewModel
+	topView         topViewModel
 	queueView       queueModel
 	playbackControl playbackControlsModel
 	audioPlayer     audioPlayerModel
 	deviceView      deviceDisplayModel
 	dialog          dialogModel
+
+	sessionPath     string
+	restoredSession *state.Session // Session loaded at startup, used to reselect the saved source and resume
+
+	libraryIndexing bool // The library index is being warmed in the background
+
+	launch *launchRequest // Items given on the command line, nil when there are none
+
+	clipboardMethod string // auto, system or osc52
+
+	events *control.Broker // Player and queue changes pushed to API clients
+
+	daemon *daemonLink // The daemon this TUI is attached to, nil when running on its own
 }
 
 func (m applicationModel) Init() tea.Cmd {
 	log.Println("Application: Initializing application model")
+
+	// Launch arguments say what to play, so don't offer to resume
+	launching := m.launch != nil && (m.launch.play || len(m.launch.links) > 0)
+
+	var resumeCmd tea.Cmd
+	// An attached TUI joins the daemon's playback instead of resuming its own
+	if m.restoredSession != nil && m.restoredSession.CanResume() && !launching && m.daemon == nil {
+		session := *m.restoredSession
+		resumeCmd = func() tea.Msg {
+			return ShowDialogWithContentMsg{Content: NewResumeDialog(m.ctx, m.spotifyState, session)}
+		}
+	}
+
+	// Selecting the launch device fetches the devices, fetching them too could activate another one
+	fetchDevices := m.spotifyState.FetchDevices(m.ctx)
+	if m.launch != nil && m.launch.device != "" {
+		fetchDevices = nil
+	}
+
 	return tea.Batch(
 		tea.WindowSize(),
 		m.searchBar.Init(),
@@ -44,24 +81,41 @@ func (m applicationModel) Init() tea.Cmd {
 		m.playlistView.Init(),
 		m.spotifyState.FetchPlaylists(m.ctx),
 		m.spotifyState.FetchPlaybackState(m.ctx),
-		m.spotifyState.FetchDevices(m.ctx),
+		fetchDevices,
+		scheduleSessionSave(),
+		resumeCmd,
+		m.launchCmd(),
+		m.attachCmd(),
 	)
 }
 
-func newApplication(ctx context.Context, client *spotify.Client) applicationModel {
+func newApplication(ctx context.Context, c *config.Config, client *spotify.Client, events *control.Broker, daemon *daemonLink) applicationModel {
 
 	spotifyState := state.NewSpotifyState(client)
 	log.Printf("Application: Created SpotifyState instance: %v", spotifyState != nil)
 
-	return applicationModel{
-		ctx:             ctx,
-		spotifyState:    spotifyState,
-		focusedModel:    FocusLibrary,
-		navbar:          newNavbar(),
-		library:         newLibrary(spotifyState),
-		searchBar:       newSearchbar(ctx, spotifyState),
-		playlistView:    newPlaylistView(ctx, spotifyState),
+	if fallback, err := state.ParseAutoplayFallback(c.GetAutoplayFallback()); err != nil {
+		log.Printf("Application: %v, autoplay will stop at the end of a view", err)
+	} else {
+		spotifyState.SetAutoplayFallback(fallback)
+	}
+	spotifyState.SetFallbackPlaylist(c.GetFallbackPlaylist())
+
+	m := applicationModel{
+		ctx:          ctx,
+		spotifyState: spotifyState,
+		focusedModel: FocusLibrary,
+		navbar:       newNavbar(),
+		library:      newLibrary(ctx, spotifyState),
+		searchBar:    newSearchbar(ctx, spotifyState, c.GetSearchHistoryFilePath()),
+		playlistView: newPlaylistView(ctx, spotifyState, map[tableView][]string{
+			playlistView:        c.GetColumns("playlist"),
+			albumTracksView:     c.GetColumns("album"),
+			artistTopTracksView: c.GetColumns("artist"),
+		}),
 		searchView:      newSearchView(ctx, spotifyState),
+		historyView:     newHistoryView(ctx, spotifyState),
+		topView:         newTopView(ctx, spotifyState),
 		queueView:       newQueue(spotifyState),
 		playbackControl: newPlaybackControlsModel(spotifyState),
 		audioPlayer:     newAudioPlayer(ctx, spotifyState),
@@ -69,7 +123,27 @@ func newApplication(ctx context.Context, client *spotify.Client) applicationMode
 		errorToast:      newErrorToast(),
 		dialog:          newDialog(),
 		activeViewport:  MainView,
+		sessionPath:     c.GetSessionFilePath(),
+		clipboardMethod: c.GetClipboardMethod(),
+		events:          events,
+		daemon:          daemon,
+	}
+
+	// The first model is only a placeholder until authentication completes
+	if client == nil {
+		return m
+	}
+
+	m.searchBar.loadHistory()
+	m.launch = newLaunchRequest(c)
+
+	session, err := state.LoadSession(m.sessionPath)
+	if err != nil {
+		log.Printf("Application: Failed to load session, starting fresh: %v", err)
+	} else if session != nil {
+		m.restoreSession(session)
 	}
+	return m
 }
 
 func (m applicationModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -124,12 +198,27 @@ func (m applicationModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 		m.navbar.queueCount = m.spotifyState.Queue.Size()
+		m.publishEvent(control.EventQueue)
+		cmds = append(cmds, m.syncQueue())
 		return m, tea.Batch(cmds...)
 
 	case AutoplayNextTrackMsg:
+		if m.daemon != nil {
+			// The daemon plays the next track
+			return m, nil
+		}
 		return m.handleAutoplay()
 
-	case clearQueuedHighlightMsg:
+	case state.AutoplayFallbackLoadedMsg:
+		if msg.Count == 0 {
+			return m, tea.Batch(
+				showErrorToast("Autoplay Stopped", "The autoplay fallback did not find any tracks to play"),
+				m.spotifyState.FetchPlaybackState(m.ctx),
+			)
+		}
+		return m.handleAutoplay()
+
+	case clearQueuedHighlightMsg, clearStatusMsg, state.SavedTracksUpdatedMsg:
 		if updatedPlaylistView, cmd, ok := updateSubmodel(m.playlistView, msg, m.playlistView); ok {
 			m.playlistView = updatedPlaylistView
 			cmds = append(cmds, cmd)
@@ -170,15 +259,78 @@ func (m applicationModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.audioPlayer = updatedAudioPlayer
 			cmds = append(cmds, cmd)
 		}
+		m.publishEvent(control.EventPlayer)
+		cmds = append(cmds, m.syncContext())
 		return m, tea.Batch(cmds...)
 
 	case state.PlaylistsUpdatedMsg:
-		if updatedLibrary, cmd, ok := updateSubmodel(m.library, msg, m.library); ok {
-			m.library = updatedLibrary
-			cmds = append(cmds, cmd)
+		updatedLibrary, cmd, ok := updateSubmodel(m.library, msg, m.library)
+		if !ok {
+			return m, tea.Batch(cmds...)
+		}
+		m.library = updatedLibrary
+
+		// Reopen the launch item or the source from the last session instead of the first library item
+		if uri := m.pendingSelection(); uri != "" {
+			m.library.selectURI(uri)
+			cmd = m.spotifyState.SelectPlaylist(uri)
 		}
+		cmds = append(cmds, cmd, m.startLibraryIndex(libraryIndexStartDelay))
 		return m, tea.Batch(cmds...)
 
+	case libraryIndexTickMsg:
+		return m, m.spotifyState.WarmLibraryIndex(m.ctx)
+
+	case state.LibraryIndexProgressMsg:
+		return m.handleLibraryIndexProgress(msg)
+
+	case libraryJumpMsg:
+		uri := "spotify:playlist:" + string(msg.PlaylistID)
+		m.searchBar.ExitSearchMode()
+		m.activeViewport = MainView
+		m.focusedModel = FocusPlaylistView
+		m.library.selectURI(uri)
+		m.playlistView.jumpTo = &trackJump{sourceID: msg.PlaylistID, index: msg.Index}
+		return m, tea.Batch(m.spotifyState.SelectPlaylist(uri), tea.WindowSize())
+
+	case copyRequestMsg:
+		return m, copyToClipboard(m.clipboardMethod, msg)
+
+	case copiedMsg:
+		m.navbar.status = msg.Summary
+		return m, clearCopiedAfterDelay()
+
+	case clearCopiedMsg:
+		m.navbar.status = ""
+		return m, nil
+
+	case daemonAttachedMsg:
+		return m, m.handleDaemonAttached(msg)
+
+	case daemonEventMsg:
+		return m, m.handleDaemonEvent(msg)
+
+	case daemonDetachedMsg:
+		return m, m.handleDaemonDetached(msg)
+
+	case control.CommandMsg:
+		return m, m.handleControl(msg)
+
+	case openLinksMsg:
+		return m, m.openLinks(msg.Links)
+
+	case state.LinksQueuedMsg:
+		return m, tea.Batch(m.handleLinksQueued(msg), m.playLaunchQueue(msg))
+
+	case launchMsg:
+		return m, m.handleLaunch()
+
+	case clearSearchHistoryMsg:
+		return m, m.searchBar.clearHistory()
+
+	case sessionSaveTickMsg:
+		return m, tea.Batch(m.saveSessionAsync(), scheduleSessionSave())
+
 	case state.TracksUpdatedMsg:
 		if updatedPlaylistView, cmd, ok := updateSubmodel(m.playlistView, msg, m.playlistView); ok {
 			m.playlistView = updatedPlaylistView
@@ -186,6 +338,20 @@ func (m applicationModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, tea.Batch(cmds...)
 
+	case state.RecentlyPlayedUpdatedMsg:
+		if updatedHistoryView, cmd, ok := updateSubmodel(m.historyView, msg, m.historyView); ok {
+			m.historyView = updatedHistoryView
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case state.TopItemsUpdatedMsg:
+		if updatedTopView, cmd, ok := updateSubmodel(m.topView, msg, m.topView); ok {
+			m.topView = updatedTopView
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
 	case state.DevicesUpdatedMsg:
 		if updatedDevices, cmd, ok := updateSubmodel(m.deviceView, msg, m.deviceView); ok {
 			m.deviceView = updatedDevices
@@ -204,10 +370,63 @@ func (m applicationModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(cmds...)
 
 	case state.PlaylistSelectedMsg:
-		cmds = append(cmds, m.spotifyState.FetchPlaylistTracks(m.ctx, spotify.ID(msg.PlaylistID)))
+		switch msg.Kind {
+		case "artist":
+			m.playlistView.setViewType(artistTopTracksView)
+			cmds = append(cmds, m.spotifyState.FetchTopTracks(m.ctx, spotify.ID(msg.PlaylistID)))
+		case "album":
+			m.playlistView.setViewType(albumTracksView)
+			cmds = append(cmds, m.spotifyState.FetchAlbumTracks(m.ctx, spotify.ID(msg.PlaylistID)))
+		default:
+			m.playlistView.setViewType(playlistView)
+			cmds = append(cmds, m.spotifyState.FetchPlaylistTracks(m.ctx, spotify.ID(msg.PlaylistID)))
+		}
+		return m, tea.Batch(cmds...)
+
+	case state.PlaylistTracksChangedMsg:
+		if updatedPlaylistView, cmd, ok := updateSubmodel(m.playlistView, msg, m.playlistView); ok {
+			m.playlistView = updatedPlaylistView
+			cmds = append(cmds, cmd)
+		}
+		// The cache was dropped so this reloads the open playlist with the change applied
+		if msg.PlaylistID == m.spotifyState.GetSelectedID() {
+			cmds = append(cmds, m.spotifyState.FetchPlaylistTracks(m.ctx, msg.PlaylistID))
+		}
+		return m, tea.Batch(cmds...)
+
+	case state.BulkQueueProgressMsg:
+		if !msg.Done {
+			m.navbar.queueProgress = fmt.Sprintf("• adding %d/%d", msg.Loaded, msg.Total)
+			return m, m.spotifyState.QueueSource(m.ctx, msg.SourceID, msg.Kind)
+		}
+		m.navbar.queueProgress = ""
+		if msg.Err != nil {
+			errMsg := *msg.Err
+			return m, func() tea.Msg { return errMsg }
+		}
+		return m, state.UpdateQueue()
+
+	case state.LibraryUpdatedMsg:
+		if updatedLibrary, cmd, ok := updateSubmodel(m.library, msg, m.library); ok {
+			m.library = updatedLibrary
+			cmds = append(cmds, cmd)
+		}
+		if updatedSearchView, cmd, ok := updateSubmodel(m.searchView, msg, m.searchView); ok {
+			m.searchView = updatedSearchView
+			cmds = append(cmds, cmd)
+		}
+		if updatedTopView, cmd, ok := updateSubmodel(m.topView, msg, m.topView); ok {
+			m.topView = updatedTopView
+			cmds = append(cmds, cmd)
+		}
 		return m, tea.Batch(cmds...)
 
 	case tea.KeyMsg:
+		// The filter prompt takes every key until it is closed
+		if m.focusedModel == FocusPlaylistView && m.playlistView.filter.typing {
+			return m.updateFocusedModel(msg)
+		}
+
 		updatedModel, cmd, handled := m.handleGlobalKeys(msg)
 		m = updatedModel
 		if handled {
@@ -335,6 +554,8 @@ func (m applicationModel) viewMain() string {
 	m.searchBar.isFocused = m.focusedModel == FocusSearchBar
 	m.playlistView.isFocused = m.focusedModel == FocusPlaylistView
 	m.queueView.isFocused = m.focusedModel == FocusQueue
+	m.historyView.isFocused = m.focusedModel == FocusHistoryView
+	m.topView.isFocused = m.isTopViewFocus()
 
 	// Set search view focus state
 	m.searchView.isFocused = m.isSearchViewFocus()
@@ -343,12 +564,20 @@ func (m applicationModel) viewMain() string {
 	if m.isSearchViewFocus() {
 		m.searchView.SetActiveList(m.focusedModel)
 	}
+	if m.isTopViewFocus() {
+		m.topView.SetActivePane(m.focusedModel)
+	}
 
 	// Determine which view to show based on search state
 	var viewport string
-	if m.searchBar.searching {
+	switch {
+	case m.focusedModel == FocusHistoryView:
+		viewport = m.historyView.View()
+	case m.isTopViewFocus():
+		viewport = m.topView.View()
+	case m.searchBar.searching:
 		viewport = m.searchView.View()
-	} else {
+	default:
 		viewport = m.playlistView.View()
 	}
 
@@ -450,6 +679,22 @@ func (m applicationModel) handleWindowSizeMsg(msg tea.WindowSizeMsg) (applicatio
 		cmds = append(cmds, cmd)
 	}
 
+	if updatedHistoryView, cmd, ok := updateSubmodel(m.historyView, tea.WindowSizeMsg{
+		Width:  mainContentViewWidth,
+		Height: mainViewportContentHeight,
+	}, m.historyView); ok {
+		m.historyView = updatedHistoryView
+		cmds = append(cmds, cmd)
+	}
+
+	if updatedTopView, cmd, ok := updateSubmodel(m.topView, tea.WindowSizeMsg{
+		Width:  mainContentViewWidth,
+		Height: mainViewportContentHeight,
+	}, m.topView); ok {
+		m.topView = updatedTopView
+		cmds = append(cmds, cmd)
+	}
+
 	if updatedQueueView, cmd, ok := updateSubmodel(m.queueView, tea.WindowSizeMsg{
 		Height: viewportHeight,
 	}, m.queueView); ok {
@@ -0,0 +1,221 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dietzy1/termify/internal/authentication"
+	"github.com/dietzy1/termify/internal/control"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
+	"golang.org/x/oauth2"
+)
+
+// daemonLink attaches the TUI to a running daemon, which owns the queue and autoplay. The TUI
+// mirrors the daemon's queue and sends its own changes back, so every attached TUI shares it.
+type daemonLink struct {
+	socketPath   string
+	subscription *control.Subscription
+
+	// The queue as last received from or sent to the daemon, to only send changes made here
+	syncedQueue []spotify.ID
+	// Track the daemon was last sent the autoplay context for
+	contextTrack spotify.ID
+}
+
+type daemonAttachedMsg struct {
+	subscription *control.Subscription
+}
+
+type daemonEventMsg struct {
+	event control.Event
+}
+
+type daemonDetachedMsg struct {
+	err error
+}
+
+// daemonLoginFailedMsg means the daemon has no login to share, an attached TUI can't log in itself
+// as the daemon serves the login callback
+type daemonLoginFailedMsg struct {
+	err error
+}
+
+// daemonTokenSource asks the daemon for its access token, the daemon keeps it refreshed
+type daemonTokenSource struct {
+	socketPath string
+}
+
+func (s daemonTokenSource) Token() (*oauth2.Token, error) {
+	response, err := control.Send(s.socketPath, control.Request{Command: control.CommandToken})
+	if err != nil {
+		return nil, err
+	}
+	if !response.OK {
+		return nil, errors.New(response.Error)
+	}
+	if response.Token == nil {
+		return nil, errors.New("the daemon sent no token")
+	}
+	return response.Token, nil
+}
+
+// daemonLogin logs in with the daemon's login
+func daemonLogin(ctx context.Context, socketPath string) tea.Cmd {
+	return func() tea.Msg {
+		source := daemonTokenSource{socketPath: socketPath}
+		token, err := source.Token()
+		if err != nil {
+			return daemonLoginFailedMsg{err: err}
+		}
+		log.Println("Application: Using the daemon's login")
+		httpClient := oauth2.NewClient(ctx, oauth2.ReuseTokenSource(token, source))
+		return authentication.LoginClientMsg{Client: spotify.New(httpClient, spotify.WithRetry(true))}
+	}
+}
+
+// attachCmd subscribes to the daemon's player and queue events
+func (m applicationModel) attachCmd() tea.Cmd {
+	if m.daemon == nil {
+		return nil
+	}
+	path := m.daemon.socketPath
+	return func() tea.Msg {
+		subscription, err := control.Subscribe(path)
+		if err != nil {
+			return daemonDetachedMsg{err: err}
+		}
+		return daemonAttachedMsg{subscription: subscription}
+	}
+}
+
+func waitForDaemonEvent(subscription *control.Subscription) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-subscription.Events()
+		if !ok {
+			return daemonDetachedMsg{err: errors.New("connection to the daemon lost")}
+		}
+		return daemonEventMsg{event: event}
+	}
+}
+
+func (m *applicationModel) handleDaemonAttached(msg daemonAttachedMsg) tea.Cmd {
+	if m.daemon == nil {
+		msg.subscription.Close()
+		return nil
+	}
+	log.Println("Application: Attached to the daemon")
+	m.daemon.subscription = msg.subscription
+	return waitForDaemonEvent(msg.subscription)
+}
+
+// handleDaemonDetached carries on without the daemon, autoplaying from this TUI again
+func (m *applicationModel) handleDaemonDetached(msg daemonDetachedMsg) tea.Cmd {
+	if m.daemon == nil {
+		return nil
+	}
+	log.Printf("Application: Detached from the daemon: %v", msg.err)
+	m.daemon = nil
+	return showErrorToast("Daemon Disconnected", "Termify continues on its own with the queue as it was.")
+}
+
+func (m *applicationModel) handleDaemonEvent(msg daemonEventMsg) tea.Cmd {
+	if m.daemon == nil {
+		return nil
+	}
+	next := waitForDaemonEvent(m.daemon.subscription)
+
+	switch msg.event.Type {
+	case control.EventQueue:
+		m.daemon.syncedQueue = trackIDs(msg.event.Tracks)
+		m.spotifyState.Queue.Replace(msg.event.Tracks)
+		return tea.Batch(state.UpdateQueue(), next)
+
+	case control.EventPlayer:
+		// The daemon moved on to another track or Spotify was controlled elsewhere, catch up
+		playerState := m.spotifyState.GetPlayerState()
+		var current string
+		if playerState.Item != nil {
+			current = string(playerState.Item.URI)
+		}
+		if status := msg.event.Status; status != nil && (status.URI != current || status.Playing != playerState.Playing) {
+			return tea.Batch(m.spotifyState.FetchPlaybackState(m.ctx), next)
+		}
+	}
+	return next
+}
+
+// syncQueue sends the queue to the daemon when it was changed in this TUI
+func (m *applicationModel) syncQueue() tea.Cmd {
+	if m.daemon == nil {
+		return nil
+	}
+	tracks := m.spotifyState.Queue.List()
+	ids := trackIDs(tracks)
+	if slices.Equal(ids, m.daemon.syncedQueue) {
+		return nil
+	}
+	m.daemon.syncedQueue = ids
+	return m.sendToDaemon(control.Request{Command: control.CommandSetQueue, Tracks: tracks})
+}
+
+// syncContext hands the daemon the view the playing track was started from, so it autoplays
+// through the view like the TUI does
+func (m *applicationModel) syncContext() tea.Cmd {
+	if m.daemon == nil {
+		return nil
+	}
+	playerState := m.spotifyState.GetPlayerState()
+	if playerState.Item == nil || playerState.Item.ID == m.daemon.contextTrack {
+		return nil
+	}
+	tracks := m.autoplayTracks()
+	if !slices.Contains(trackIDs(tracks), playerState.Item.ID) {
+		return nil
+	}
+	m.daemon.contextTrack = playerState.Item.ID
+	return m.sendToDaemon(control.Request{Command: control.CommandSetContext, Tracks: tracks})
+}
+
+// autoplayTracks returns the tracks of the view autoplay follows, in the order it plays them
+func (m applicationModel) autoplayTracks() []spotify.SimpleTrack {
+	var tracks []spotify.SimpleTrack
+	switch {
+	case m.focusedModel == FocusPlaylistView || m.focusedModel == FocusLibrary:
+		return m.playlistView.displayedTracks()
+	case m.focusedModel == FocusHistoryView:
+		for _, item := range m.spotifyState.GetRecentlyPlayed() {
+			tracks = append(tracks, item.Track)
+		}
+	case m.isTopViewFocus():
+		for _, track := range m.spotifyState.GetTopTracks(m.topView.timeRange) {
+			tracks = append(tracks, track.SimpleTrack)
+		}
+	case m.focusedModel == FocusSearchTracksView:
+		for _, track := range m.spotifyState.GetSearchResultTracks() {
+			tracks = append(tracks, track.SimpleTrack)
+		}
+	case m.isSearchViewFocus():
+		return m.spotifyState.GetTracks()
+	}
+	return tracks
+}
+
+// sendToDaemon runs a command on the daemon in the background
+func (m applicationModel) sendToDaemon(request control.Request) tea.Cmd {
+	path := m.daemon.socketPath
+	return func() tea.Msg {
+		response, err := control.Send(path, request)
+		if err != nil {
+			log.Printf("Application: Failed to send %s to the daemon: %v", request.Command, err)
+			return state.ErrorMsg{Title: "Daemon Unreachable", Message: err.Error()}
+		}
+		if !response.OK {
+			return state.ErrorMsg{Title: "Daemon Command Failed", Message: response.Error}
+		}
+		return nil
+	}
+}
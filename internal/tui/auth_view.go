@@ -6,7 +6,6 @@ import (
 	"log"
 	"time"
 
-	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -33,6 +32,7 @@ type authModel struct {
 	state         authState
 	err           error
 	loginURL      string
+	loginReason   string
 	copied        bool
 	config        *config.Config
 	authenticator authenticator
@@ -87,6 +87,7 @@ func (m authModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			log.Fatal(err)
 		}
 		m.loginURL = msg.Url
+		m.loginReason = msg.Reason
 		m.state = stateAwaitingLogin
 		m.copied = false
 
@@ -105,7 +106,7 @@ func (m authModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case key.Matches(msg, DefaultKeyMap.Copy):
 			if m.state == stateAwaitingLogin && msg.String() == "c" {
-				if err := clipboard.WriteAll(m.loginURL); err != nil {
+				if err := writeClipboard(m.config.GetClipboardMethod(), m.loginURL); err != nil {
 					log.Printf("Failed to copy to clipboard: %v", err)
 					return m, nil
 				}
@@ -239,12 +240,17 @@ func (m authModel) View() string {
 			statusText = "✓ URL copied to clipboard!"
 		}
 
+		instruction := "Please visit this URL to login:"
+		if m.loginReason != "" {
+			instruction = m.loginReason + " " + instruction
+		}
+
 		return containerStyle.Render(lipgloss.JoinVertical(
 			lipgloss.Center,
 			LogoStyle.Render(logo),
 			boxStyle.Render(lipgloss.JoinVertical(
 				lipgloss.Center,
-				instructionStyle.Render("Please visit this URL to login:"),
+				instructionStyle.Render(instruction),
 				urlStyle.Render(m.loginURL),
 				hintStyle.Render(statusText),
 			)),
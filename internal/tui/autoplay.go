@@ -5,6 +5,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
 )
 
 // TODO: I dont think this autoplay behaviour is needed I might have misunderstood what the fuck a spotify play context is.
@@ -35,13 +36,67 @@ func (m applicationModel) handleAutoplay() (applicationModel, tea.Cmd) {
 			return m, m.spotifyState.PlayTrack(m.ctx, nextTrack)
 		}
 
+	case m.focusedModel == FocusHistoryView:
+		if nextTrack := m.historyView.getNextTrack(); nextTrack != "" {
+			log.Printf("Playing next track from history: %s", nextTrack)
+			return m, m.spotifyState.PlayTrack(m.ctx, nextTrack)
+		}
+
+	case m.isTopViewFocus():
+		if nextTrack := m.topView.getNextTrack(); nextTrack != "" {
+			log.Printf("Playing next track from top tracks: %s", nextTrack)
+			return m, m.spotifyState.PlayTrack(m.ctx, nextTrack)
+		}
+
 	case m.isSearchViewFocus():
 		if nextTrack := m.searchView.GetNextTrack(m.focusedModel); nextTrack != "" {
 			log.Printf("Playing next track from search: %s", nextTrack)
 			return m, m.spotifyState.PlayTrack(m.ctx, nextTrack)
 		}
 	}
-	log.Println("No next track found in current view, not playing anything")
-	//TODO: Do we need to add a fallback here? // Could play from recent tracks or try to come up with a recommendation
-	return m, m.spotifyState.FetchPlaybackState(m.ctx)
+	return m.handleAutoplayFallback()
+}
+
+// handleAutoplayFallback continues playback once the current view has run out of tracks
+func (m applicationModel) handleAutoplayFallback() (applicationModel, tea.Cmd) {
+	fallback := m.spotifyState.GetAutoplayFallback()
+	log.Printf("No next track found in current view, using autoplay fallback: %s", fallback)
+
+	switch fallback {
+	case state.FallbackStop:
+		return m, m.spotifyState.FetchPlaybackState(m.ctx)
+
+	case state.FallbackLoopContext:
+		if firstTrack := m.getFirstTrackInView(); firstTrack != "" {
+			log.Printf("Looping current view from first track: %s", firstTrack)
+			return m, m.spotifyState.PlayTrack(m.ctx, firstTrack)
+		}
+		return m, m.spotifyState.FetchPlaybackState(m.ctx)
+	}
+
+	// The remaining fallbacks fill the queue, which is then drained by handleAutoplay
+	return m, m.spotifyState.LoadAutoplayFallback(m.ctx)
+}
+
+// getFirstTrackInView returns the first track of whatever list autoplay is currently following
+func (m applicationModel) getFirstTrackInView() spotify.ID {
+	switch {
+	case m.focusedModel == FocusHistoryView:
+		if items := m.spotifyState.GetRecentlyPlayed(); len(items) > 0 {
+			return items[0].Track.ID
+		}
+	case m.isTopViewFocus():
+		if tracks := m.spotifyState.GetTopTracks(m.topView.timeRange); len(tracks) > 0 {
+			return tracks[0].ID
+		}
+	case m.focusedModel == FocusSearchTracksView:
+		if tracks := m.spotifyState.GetSearchResultTracks(); len(tracks) > 0 {
+			return tracks[0].ID
+		}
+	default:
+		if tracks := m.playlistView.displayedTracks(); len(tracks) > 0 {
+			return tracks[0].ID
+		}
+	}
+	return ""
 }
@@ -0,0 +1,303 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
+)
+
+type copyFormat int
+
+const (
+	copyURL copyFormat = iota
+	copyURI
+	copyText
+	copyMarkdown
+)
+
+// copyFormats lists the formats in the order the copy dialog offers them
+var copyFormats = []struct {
+	format copyFormat
+	label  string
+	key    string
+}{
+	{copyURL, "URL", "1"},
+	{copyURI, "URI", "2"},
+	{copyText, "Text", "3"},
+	{copyMarkdown, "Markdown", "4"},
+}
+
+// copyItem is a track, album, artist or playlist that can be copied
+type copyItem struct {
+	link    state.SpotifyLink
+	name    string
+	artists []string // Empty for artists and playlists
+}
+
+func trackCopyItem(track spotify.SimpleTrack) copyItem {
+	return copyItem{
+		link:    state.SpotifyLink{Kind: "track", ID: track.ID},
+		name:    track.Name,
+		artists: artistNames(track.Artists),
+	}
+}
+
+func albumCopyItem(album spotify.SimpleAlbum) copyItem {
+	return copyItem{
+		link:    state.SpotifyLink{Kind: "album", ID: album.ID},
+		name:    album.Name,
+		artists: artistNames(album.Artists),
+	}
+}
+
+func artistCopyItem(id spotify.ID, name string) copyItem {
+	return copyItem{link: state.SpotifyLink{Kind: "artist", ID: id}, name: name}
+}
+
+func playlistCopyItem(id spotify.ID, name string) copyItem {
+	return copyItem{link: state.SpotifyLink{Kind: "playlist", ID: id}, name: name}
+}
+
+func artistNames(artists []spotify.SimpleArtist) []string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return names
+}
+
+// text renders the item as "Artist – Title", or just its name when it has no artists
+func (i copyItem) text() string {
+	if len(i.artists) == 0 {
+		return i.name
+	}
+	return strings.Join(i.artists, ", ") + " – " + i.name
+}
+
+func (i copyItem) format(format copyFormat) string {
+	switch format {
+	case copyURI:
+		return string(i.link.URI())
+	case copyText:
+		return i.text()
+	case copyMarkdown:
+		// Brackets in the title would end the link text early
+		text := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(i.text())
+		return fmt.Sprintf("[%s](%s)", text, i.link.URL())
+	}
+	return i.link.URL()
+}
+
+// formatCopyItems renders every item in the format, one per line
+func formatCopyItems(items []copyItem, format copyFormat) string {
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = item.format(format)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// copyRequestMsg asks the application to put text on the clipboard
+type copyRequestMsg struct {
+	Text    string
+	Summary string // Shown once the text is copied
+}
+
+// copiedMsg reports that text reached the clipboard
+type copiedMsg struct {
+	Summary string
+}
+
+type clearCopiedMsg struct{}
+
+// showCopyDialog asks which format to copy the items in
+func showCopyDialog(items ...copyItem) tea.Cmd {
+	if len(items) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return ShowDialogWithContentMsg{Content: NewCopyDialog(items)}
+	}
+}
+
+// writeClipboard puts text on the clipboard. Over SSH the system clipboard is the remote machine's,
+// so the auto method asks the local terminal to set it with an OSC 52 escape sequence instead.
+// It also falls back to OSC 52 when no system clipboard is available.
+func writeClipboard(method, text string) error {
+	if method == "osc52" || (method == "auto" && isRemoteSession()) {
+		return writeOSC52(text)
+	}
+
+	err := clipboard.WriteAll(text)
+	if err != nil && method == "auto" {
+		log.Printf("Clipboard: System clipboard unavailable, falling back to OSC 52: %v", err)
+		return writeOSC52(text)
+	}
+	return err
+}
+
+func isRemoteSession() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_CLIENT") != ""
+}
+
+// terminal is the program's output. The renderer and OSC 52 sequences write through it, so a
+// sequence never lands in the middle of a frame.
+var terminal = &terminalOutput{File: os.Stdout}
+
+// terminalOutput serializes writes to the terminal. It stays a file so the program still
+// finds the terminal's size.
+type terminalOutput struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func (t *terminalOutput) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.File.Write(p)
+}
+
+func (t *terminalOutput) WriteString(s string) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.File.WriteString(s)
+}
+
+// writeOSC52 sends the escape sequence to the terminal, wrapped so tmux and screen pass it on
+func writeOSC52(text string) error {
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+	if _, err := seq.WriteTo(terminal); err != nil {
+		return fmt.Errorf("failed to write OSC 52 sequence: %w", err)
+	}
+	return nil
+}
+
+// copyToClipboard writes the text in the background and reports the result
+func copyToClipboard(method string, msg copyRequestMsg) tea.Cmd {
+	return func() tea.Msg {
+		if err := writeClipboard(method, msg.Text); err != nil {
+			log.Printf("Clipboard: Failed to copy: %v", err)
+			return ShowToastMsg{Title: "Copy Failed", Message: err.Error()}
+		}
+		return copiedMsg{Summary: msg.Summary}
+	}
+}
+
+func clearCopiedAfterDelay() tea.Cmd {
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return clearCopiedMsg{}
+	})
+}
+
+// copyDialogContent previews the items in every format, the chosen action copies them
+type copyDialogContent struct {
+	width  int
+	height int
+	items  []copyItem
+}
+
+func NewCopyDialog(items []copyItem) DialogContent {
+	return &copyDialogContent{items: items}
+}
+
+func (m *copyDialogContent) Init() tea.Cmd {
+	return nil
+}
+
+func (m *copyDialogContent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return m, nil
+}
+
+func (m *copyDialogContent) View() string {
+	const width = 60
+	labelStyle := lipgloss.NewStyle().Foreground(PrimaryColor).Bold(true).Width(12)
+	valueStyle := lipgloss.NewStyle().Foreground(TextColor).Width(width - 12).MaxWidth(width - 12).MaxHeight(1)
+
+	// The first item shows what each format looks like
+	rows := make([]string, 0, len(copyFormats)+1)
+	for _, f := range copyFormats {
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle.Render(f.key+" "+f.label),
+			valueStyle.Render(m.items[0].format(f.format)),
+		))
+	}
+	if len(m.items) > 1 {
+		rows = append(rows, lipgloss.NewStyle().
+			Foreground(TextColor).
+			Italic(true).
+			MarginTop(1).
+			Render(fmt.Sprintf("and %d more, one per line", len(m.items)-1)))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m *copyDialogContent) GetTitle() string {
+	if len(m.items) == 1 {
+		return "Copy " + m.items[0].name
+	}
+	return fmt.Sprintf("Copy %d Items", len(m.items))
+}
+
+func (m *copyDialogContent) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// HandleDialogKey copies right away when a format's number is pressed
+func (m *copyDialogContent) HandleDialogKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	for _, f := range copyFormats {
+		if key.Matches(msg, key.NewBinding(key.WithKeys(f.key))) {
+			return true, tea.Sequence(
+				func() tea.Msg { return HideDialogMsg{} },
+				m.copyCmd(f.format, f.label),
+			)
+		}
+	}
+	return false, nil
+}
+
+func (m *copyDialogContent) copyCmd(format copyFormat, label string) tea.Cmd {
+	summary := fmt.Sprintf("Copied %s as %s", m.items[0].name, label)
+	if len(m.items) > 1 {
+		summary = fmt.Sprintf("Copied %d items as %s", len(m.items), label)
+	}
+	text := formatCopyItems(m.items, format)
+	return func() tea.Msg {
+		return copyRequestMsg{Text: text, Summary: summary}
+	}
+}
+
+func (m *copyDialogContent) GetActions() []DialogAction {
+	actions := make([]DialogAction, 0, len(copyFormats)+1)
+	for _, f := range copyFormats {
+		actions = append(actions, DialogAction{
+			Label: f.label,
+			Key:   key.NewBinding(key.WithKeys(f.key)),
+			Cmd:   m.copyCmd(f.format, f.label),
+		})
+	}
+	return append(actions, DialogAction{
+		Label: "Cancel",
+		Key:   key.NewBinding(key.WithKeys("esc")),
+		Cmd: func() tea.Msg {
+			return DialogMsg{Accepted: false}
+		},
+	})
+}
@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/evertras/bubble-table/table"
+	"github.com/zmb3/spotify/v2"
+)
+
+// trackColumnInput is what a column needs to render or compare one track
+type trackColumnInput struct {
+	track   spotify.SimpleTrack
+	details state.TrackDetails
+	index   int // Position in the loaded tracks
+}
+
+// trackColumn describes one column of a track table
+type trackColumn struct {
+	key      string // Row data key
+	title    string
+	width    int // Fixed width, 0 for a flex column
+	centered bool
+	value    func(in trackColumnInput) string
+	compare  func(a, b trackColumnInput) int
+}
+
+// defaultTrackColumns is the layout of track tables without a configured layout
+var defaultTrackColumns = []string{"number", "title", "artist", "album", "duration"}
+
+// trackColumns holds every configurable column by its config name
+var trackColumns = map[string]trackColumn{
+	"number": {
+		key: "#", title: "#", width: 4, centered: true,
+		value: func(in trackColumnInput) string { return fmt.Sprintf("%d", in.index+1) },
+		compare: func(a, b trackColumnInput) int {
+			return cmp.Compare(a.index, b.index)
+		},
+	},
+	"title": {
+		key: "title", title: "Title",
+		value: func(in trackColumnInput) string { return in.track.Name },
+		compare: func(a, b trackColumnInput) int {
+			return compareFold(a.track.Name, b.track.Name)
+		},
+	},
+	"artist": {
+		key: "artist", title: "Artist",
+		value: func(in trackColumnInput) string { return firstArtistName(in.track) },
+		compare: func(a, b trackColumnInput) int {
+			return compareFold(firstArtistName(a.track), firstArtistName(b.track))
+		},
+	},
+	"artists": {
+		key: "artists", title: "Artists",
+		value: func(in trackColumnInput) string { return allArtistNames(in.track) },
+		compare: func(a, b trackColumnInput) int {
+			return compareFold(allArtistNames(a.track), allArtistNames(b.track))
+		},
+	},
+	"album": {
+		key: "album", title: "Album",
+		value: func(in trackColumnInput) string {
+			if in.track.Album.Name == "" {
+				return "Unknown Album"
+			}
+			return in.track.Album.Name
+		},
+		compare: func(a, b trackColumnInput) int {
+			return compareFold(a.track.Album.Name, b.track.Album.Name)
+		},
+	},
+	"duration": {
+		key: "duration", title: "Duration", width: 8, centered: true,
+		value: func(in trackColumnInput) string { return formatTrackDuration(int(in.track.Duration)) },
+		compare: func(a, b trackColumnInput) int {
+			return cmp.Compare(a.track.Duration, b.track.Duration)
+		},
+	},
+	"added": {
+		key: "added", title: "Added", width: 12, centered: true,
+		value: func(in trackColumnInput) string {
+			if in.details.AddedAt.IsZero() {
+				return "--"
+			}
+			return in.details.AddedAt.Local().Format("2006-01-02")
+		},
+		compare: func(a, b trackColumnInput) int {
+			return a.details.AddedAt.Compare(b.details.AddedAt)
+		},
+	},
+	"popularity": {
+		key: "popularity", title: "Pop", width: 6, centered: true,
+		value: func(in trackColumnInput) string {
+			if in.details.Popularity < 0 {
+				return "--"
+			}
+			return fmt.Sprintf("%d", in.details.Popularity)
+		},
+		compare: func(a, b trackColumnInput) int {
+			return cmp.Compare(a.details.Popularity, b.details.Popularity)
+		},
+	},
+	"year": {
+		key: "year", title: "Year", width: 6, centered: true,
+		value: func(in trackColumnInput) string {
+			if year := releaseYear(in.track); year != "" {
+				return year
+			}
+			return "--"
+		},
+		compare: func(a, b trackColumnInput) int {
+			return cmp.Compare(a.track.Album.ReleaseDate, b.track.Album.ReleaseDate)
+		},
+	},
+	"explicit": {
+		key: "explicit", title: "E", width: 3, centered: true,
+		value: func(in trackColumnInput) string {
+			if in.track.Explicit {
+				return "E"
+			}
+			return ""
+		},
+		compare: func(a, b trackColumnInput) int {
+			return cmp.Compare(boolRank(a.track.Explicit), boolRank(b.track.Explicit))
+		},
+	},
+	"track": {
+		key: "track", title: "Track", width: 7, centered: true,
+		value: func(in trackColumnInput) string { return fmt.Sprintf("%d", in.track.TrackNumber) },
+		// Tracks are numbered per disc, so the disc decides first
+		compare: func(a, b trackColumnInput) int {
+			return cmp.Or(
+				cmp.Compare(a.track.DiscNumber, b.track.DiscNumber),
+				cmp.Compare(a.track.TrackNumber, b.track.TrackNumber),
+			)
+		},
+	},
+	"disc": {
+		key: "disc", title: "Disc", width: 6, centered: true,
+		value: func(in trackColumnInput) string { return fmt.Sprintf("%d", in.track.DiscNumber) },
+		compare: func(a, b trackColumnInput) int {
+			return cmp.Compare(a.track.DiscNumber, b.track.DiscNumber)
+		},
+	},
+}
+
+// tableColumns builds the table columns of a layout, marking the sorted column in its header
+func tableColumns(names []string, sortColumn string, sortDesc bool) []table.Column {
+	columns := make([]table.Column, 0, len(names))
+	for _, name := range names {
+		column, ok := trackColumns[name]
+		if !ok {
+			continue
+		}
+
+		title := column.title
+		if name == sortColumn {
+			if sortDesc {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+
+		var c table.Column
+		if column.width > 0 {
+			// Leave room for the sort marker in narrow columns
+			c = table.NewColumn(column.key, title, max(column.width, lipgloss.Width(title)+2))
+		} else {
+			c = table.NewFlexColumn(column.key, title, 1)
+		}
+		if column.centered {
+			c = c.WithStyle(lipgloss.NewStyle().Align(lipgloss.Center))
+		}
+		columns = append(columns, c)
+	}
+	return columns
+}
+
+func firstArtistName(track spotify.SimpleTrack) string {
+	if len(track.Artists) == 0 {
+		return "Unknown Artist"
+	}
+	return track.Artists[0].Name
+}
+
+func allArtistNames(track spotify.SimpleTrack) string {
+	if len(track.Artists) == 0 {
+		return "Unknown Artist"
+	}
+	names := make([]string, len(track.Artists))
+	for i, artist := range track.Artists {
+		names[i] = artist.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// releaseYear returns the year of the track's album release, which can be given as a year, month or day
+func releaseYear(track spotify.SimpleTrack) string {
+	if len(track.Album.ReleaseDate) < 4 {
+		return ""
+	}
+	return track.Album.ReleaseDate[:4]
+}
+
+func compareFold(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dietzy1/termify/internal/control"
+)
+
+// handleControl runs a command received on the control socket or the HTTP API
+func (m *applicationModel) handleControl(msg control.CommandMsg) tea.Cmd {
+	if msg.Request.Command == control.CommandSubscribe {
+		m.publishEvent(control.EventPlayer)
+		m.publishEvent(control.EventQueue)
+		msg.Reply(control.Response{OK: true})
+		return nil
+	}
+
+	return control.Execute(m.ctx, m.spotifyState, msg, m.audioPlayer.progress*1000, func() tea.Cmd {
+		model, cmd := m.handleAutoplay()
+		*m = model
+		return cmd
+	})
+}
+
+// publishEvent tells API clients about a change in playback or the queue
+func (m applicationModel) publishEvent(eventType string) {
+	if m.events == nil {
+		return
+	}
+	m.events.Publish(control.NewEvent(m.spotifyState, eventType, m.audioPlayer.progress*1000))
+}
@@ -119,6 +119,14 @@ func (m dialogModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Let the content follow state updates while it is shown
+	if m.content != nil {
+		if updatedContent, cmd := m.content.Update(msg); updatedContent != nil {
+			m.content = updatedContent.(DialogContent)
+			return m, cmd
+		}
+	}
+
 	return m, nil
 }
 
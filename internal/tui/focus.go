@@ -4,6 +4,8 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/control"
+	"github.com/dietzy1/termify/internal/state"
 )
 
 type FocusedModel int
@@ -17,6 +19,9 @@ const (
 	FocusSearchArtistsView
 	FocusSearchAlbumsView
 	FocusQueue
+	FocusHistoryView
+	FocusTopTracksView
+	FocusTopArtistsView
 )
 
 func (m applicationModel) isSearchViewFocus() bool {
@@ -26,6 +31,11 @@ func (m applicationModel) isSearchViewFocus() bool {
 		m.focusedModel == FocusSearchAlbumsView
 }
 
+func (m applicationModel) isTopViewFocus() bool {
+	return m.focusedModel == FocusTopTracksView ||
+		m.focusedModel == FocusTopArtistsView
+}
+
 func (m *applicationModel) getDefaultSearchView() FocusedModel {
 	return FocusSearchTracksView
 }
@@ -81,14 +91,23 @@ func (m *applicationModel) cycleFocus() tea.Cmd {
 		m.cycleSearchViews()
 	case FocusSearchBar:
 		// From SearchBar, go to the appropriate content view
-		if m.searchBar.searching {
-			m.focusedModel = m.getDefaultSearchView()
-		} else {
+		if !m.searchBar.searching {
 			m.focusedModel = FocusPlaylistView
+			return nil
 		}
+		// Moving on to the results remembers the query
+		m.focusedModel = m.getDefaultSearchView()
+		return m.searchBar.commitQuery()
 	case FocusQueue:
 		m.focusedModel = FocusLibrary
 		return tea.WindowSize()
+	case FocusHistoryView:
+		m.focusedModel = FocusLibrary
+		return m.spotifyState.SelectPlaylist(string(m.library.list.SelectedItem().(playlist).uri))
+	case FocusTopTracksView:
+		m.focusedModel = FocusTopArtistsView
+	case FocusTopArtistsView:
+		m.focusedModel = FocusTopTracksView
 	}
 
 	return nil
@@ -113,6 +132,13 @@ func (m *applicationModel) cycleFocusBackward() tea.Cmd {
 	case FocusSearchBar:
 		// From SearchBar, go to Library (since it's at the top)
 		m.focusedModel = FocusLibrary
+	case FocusHistoryView:
+		m.focusedModel = FocusLibrary
+		return m.spotifyState.SelectPlaylist(string(m.library.list.SelectedItem().(playlist).uri))
+	case FocusTopTracksView:
+		m.focusedModel = FocusTopArtistsView
+	case FocusTopArtistsView:
+		m.focusedModel = FocusTopTracksView
 	}
 	return nil
 }
@@ -130,6 +156,19 @@ func (m applicationModel) handleGlobalKeys(msg tea.KeyMsg) (applicationModel, te
 			tea.WindowSize(),
 		), true
 
+	case key.Matches(msg, DefaultKeyMap.Return) && m.focusedModel == FocusPlaylistView && m.playlistView.hasSelection():
+		// Leave visual mode and drop the marks before leaving the view
+		m.playlistView.clearSelection()
+		return m, nil, true
+
+	case key.Matches(msg, DefaultKeyMap.Return) && m.focusedModel == FocusPlaylistView && m.playlistView.filter.active():
+		m.playlistView.clearFilter()
+		return m, nil, true
+
+	case key.Matches(msg, DefaultKeyMap.Return) && m.isSearchViewFocus() && m.searchView.expanded:
+		m.searchView.setExpanded(false)
+		return m, nil, true
+
 	case key.Matches(msg, DefaultKeyMap.Return) && m.activeViewport == HelpView:
 		m.activeViewport = MainView
 		return m, nil, false
@@ -144,6 +183,15 @@ func (m applicationModel) handleGlobalKeys(msg tea.KeyMsg) (applicationModel, te
 		), true
 	}
 
+	// Enter opens pasted links, or moves on to the results of the query and remembers it
+	if key.Matches(msg, DefaultKeyMap.Select) && m.searchBar.searching && m.focusedModel == FocusSearchBar {
+		if links, ok := state.ParseSpotifyLinks(m.searchBar.textInput.Value()); ok {
+			return m, m.openLinks(links), true
+		}
+		m.focusedModel = m.getDefaultSearchView()
+		return m, m.searchBar.commitQuery(), true
+	}
+
 	if m.searchBar.searching && m.focusedModel == FocusSearchBar {
 		//Redirect all remaining keys while searching
 		return m, cmd, false
@@ -166,6 +214,50 @@ func (m applicationModel) handleGlobalKeys(msg tea.KeyMsg) (applicationModel, te
 		m.focusedModel = FocusQueue
 		return m, tea.WindowSize(), true
 
+	case key.Matches(msg, DefaultKeyMap.ViewHistory):
+		m.searchBar.ExitSearchMode()
+		m.activeViewport = MainView
+		m.focusedModel = FocusHistoryView
+		return m, tea.Batch(
+			m.spotifyState.FetchRecentlyPlayed(m.ctx),
+			tea.WindowSize(),
+		), true
+
+	case key.Matches(msg, DefaultKeyMap.ViewTop):
+		m.searchBar.ExitSearchMode()
+		m.activeViewport = MainView
+		m.focusedModel = FocusTopTracksView
+		return m, tea.Batch(
+			m.spotifyState.FetchTopItems(m.ctx, m.topView.timeRange),
+			tea.WindowSize(),
+		), true
+
+	case key.Matches(msg, DefaultKeyMap.SavePlaylist):
+		savePlaylistDialog := NewSavePlaylistDialog(m.ctx, m.spotifyState, m.playlistSources())
+		return m, func() tea.Msg {
+			return ShowDialogWithContentMsg{
+				Content: savePlaylistDialog,
+			}
+		}, true
+
+	case key.Matches(msg, DefaultKeyMap.LibrarySearch):
+		librarySearchDialog := NewLibrarySearchDialog(m.ctx, m.spotifyState)
+		return m, tea.Batch(
+			m.startLibraryIndex(0),
+			func() tea.Msg {
+				return ShowDialogWithContentMsg{
+					Content: librarySearchDialog,
+				}
+			},
+		), true
+
+	case key.Matches(msg, DefaultKeyMap.OpenLinks):
+		return m, func() tea.Msg {
+			return ShowDialogWithContentMsg{
+				Content: NewOpenLinksDialog(),
+			}
+		}, true
+
 	case key.Matches(msg, DefaultKeyMap.DeviceDialog):
 		deviceDialog := NewDeviceDialog(m.ctx, m.spotifyState)
 		return m, func() tea.Msg {
@@ -184,6 +276,9 @@ func (m applicationModel) handleGlobalKeys(msg tea.KeyMsg) (applicationModel, te
 		}
 		return m, m.spotifyState.StartPlayback(m.ctx), true
 	case key.Matches(msg, DefaultKeyMap.Next):
+		if m.daemon != nil {
+			return m, m.sendToDaemon(control.Request{Command: control.CommandNext}), true
+		}
 		model, cmd := m.handleAutoplay()
 		return model, cmd, true
 	case key.Matches(msg, DefaultKeyMap.Previous):
@@ -198,6 +293,13 @@ func (m applicationModel) handleGlobalKeys(msg tea.KeyMsg) (applicationModel, te
 		return m, m.spotifyState.DecreaseVolume(m.ctx), true
 	case key.Matches(msg, DefaultKeyMap.VolumeMute):
 		return m, m.spotifyState.Mute(m.ctx), true
+	case key.Matches(msg, DefaultKeyMap.AutoplayFallback):
+		fallback := m.spotifyState.CycleAutoplayFallback()
+		// The daemon autoplays for attached TUIs
+		if m.daemon != nil {
+			return m, m.sendToDaemon(control.Request{Command: control.CommandSetFallback, Value: fallback.String()}), true
+		}
+		return m, nil, true
 	}
 
 	return m, cmd, false
@@ -232,6 +334,17 @@ func (m applicationModel) updateFocusedModel(msg tea.Msg) (tea.Model, tea.Cmd) {
 		queue, cmd := m.queueView.Update(msg)
 		m.queueView = queue.(queueModel)
 		cmds = append(cmds, cmd)
+
+	case FocusHistoryView:
+		historyView, cmd := m.historyView.Update(msg)
+		m.historyView = historyView.(historyViewModel)
+		cmds = append(cmds, cmd)
+
+	case FocusTopTracksView, FocusTopArtistsView:
+		m.topView.SetActivePane(m.focusedModel)
+		topView, cmd := m.topView.Update(msg)
+		m.topView = topView.(topViewModel)
+		cmds = append(cmds, cmd)
 	}
 
 	return m, tea.Batch(cmds...)
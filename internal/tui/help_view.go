@@ -42,17 +42,33 @@ func (m applicationModel) renderHelp() string {
 	}
 
 	actionBindings := []key.Binding{
-		DefaultKeyMap.Select, DefaultKeyMap.Copy, DefaultKeyMap.Return, DefaultKeyMap.AddToQueue,
+		DefaultKeyMap.Select, DefaultKeyMap.Copy, DefaultKeyMap.Return, DefaultKeyMap.AddToQueue, DefaultKeyMap.QueueAll, DefaultKeyMap.PlayNext, DefaultKeyMap.OpenContext, DefaultKeyMap.TimeRange, DefaultKeyMap.ExpandSearch, DefaultKeyMap.Follow,
 	}
 
 	systemBindings := []key.Binding{
-		DefaultKeyMap.Help, DefaultKeyMap.Quit, DefaultKeyMap.Search, DefaultKeyMap.ViewQueue, DefaultKeyMap.DeviceDialog,
+		DefaultKeyMap.Help, DefaultKeyMap.Quit, DefaultKeyMap.Search, DefaultKeyMap.ViewQueue, DefaultKeyMap.ViewHistory, DefaultKeyMap.ViewTop, DefaultKeyMap.SavePlaylist, DefaultKeyMap.DeviceDialog, DefaultKeyMap.LibrarySearch, DefaultKeyMap.OpenLinks,
+	}
+
+	selectionBindings := []key.Binding{
+		DefaultKeyMap.VisualMode, DefaultKeyMap.ToggleSelect, DefaultKeyMap.AddToPlaylist,
+		DefaultKeyMap.RemoveFromPlaylist, DefaultKeyMap.ToggleLike, DefaultKeyMap.Filter, DefaultKeyMap.FilterLoadAll,
+		DefaultKeyMap.SortNext, DefaultKeyMap.SortPrevious, DefaultKeyMap.SortReverse,
+	}
+
+	searchBarBindings := []key.Binding{
+		DefaultKeyMap.SearchHistoryOlder, DefaultKeyMap.SearchHistoryNewer, DefaultKeyMap.AcceptSuggestion,
+		DefaultKeyMap.RemoveSearchHistory, DefaultKeyMap.ClearSearchHistory,
+	}
+
+	queueBindings := []key.Binding{
+		DefaultKeyMap.QueueMoveUp, DefaultKeyMap.QueueMoveDown, DefaultKeyMap.QueueRemove, DefaultKeyMap.QueueClear,
+		DefaultKeyMap.QueueShuffle, DefaultKeyMap.QueueDedupe, DefaultKeyMap.QueueRemoveArtist,
 	}
 
 	mediaBindings := []key.Binding{
 		DefaultKeyMap.VolumeUp, DefaultKeyMap.VolumeDown, DefaultKeyMap.VolumeMute,
 		DefaultKeyMap.Previous, DefaultKeyMap.PlayPause, DefaultKeyMap.Next,
-		DefaultKeyMap.Shuffle, DefaultKeyMap.Repeat,
+		DefaultKeyMap.Shuffle, DefaultKeyMap.Repeat, DefaultKeyMap.AutoplayFallback,
 	}
 
 	// Function to render a section of key bindings
@@ -80,9 +96,22 @@ func (m applicationModel) renderHelp() string {
 	}
 
 	// Build the help content with sections
-	navSection := renderSection("Navigation", navigationBindings)
-	actionSection := renderSection("Actions", actionBindings)
-	systemSection := renderSection("System", systemBindings)
+	// The queue section is stacked under navigation to keep the four column layout
+	navSection := lipgloss.JoinVertical(lipgloss.Left,
+		renderSection("Navigation", navigationBindings),
+		"",
+		renderSection("Queue", queueBindings),
+	)
+	actionSection := lipgloss.JoinVertical(lipgloss.Left,
+		renderSection("Actions", actionBindings),
+		"",
+		renderSection("Selection", selectionBindings),
+	)
+	systemSection := lipgloss.JoinVertical(lipgloss.Left,
+		renderSection("System", systemBindings),
+		"",
+		renderSection("Search Bar", searchBarBindings),
+	)
 	mediaSection := renderSection("Media Controls", mediaBindings)
 
 	// Determine layout based on available width
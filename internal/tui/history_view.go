@@ -0,0 +1,279 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/evertras/bubble-table/table"
+	"github.com/zmb3/spotify/v2"
+)
+
+var _ tea.Model = (*historyViewModel)(nil)
+
+type historyViewModel struct {
+	ctx           context.Context
+	width, height int
+	table         table.Model
+	isFocused     bool
+	spotifyState  *state.SpotifyState
+}
+
+func newHistoryView(ctx context.Context, spotifyState *state.SpotifyState) historyViewModel {
+	return historyViewModel{
+		ctx:          ctx,
+		table:        createHistoryTable(),
+		spotifyState: spotifyState,
+	}
+}
+
+func (m historyViewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m historyViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		const minTableHeight = 7
+		// This check is a panic safeguard
+		if m.height-minTableHeight < 0 {
+			m.table = m.table.WithTargetWidth(m.width).WithMinimumHeight(m.height).WithPageSize(1)
+			return m, nil
+		}
+
+		m.table = m.table.WithTargetWidth(m.width).WithMinimumHeight(m.height).WithPageSize(m.height - headerFooterHeight)
+		return m, nil
+
+	case state.RecentlyPlayedUpdatedMsg:
+		m.updateTableWithHistory()
+		return m, m.checkAndFetchMoreIfNeeded()
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Select):
+			if item := m.getSelectedItem(); item != nil {
+				log.Printf("HistoryView: Playing track: %s", item.Track.ID)
+				return m, m.spotifyState.PlayTrack(m.ctx, item.Track.ID)
+			}
+			return m, nil
+
+		case key.Matches(msg, DefaultKeyMap.AddToQueue):
+			if item := m.getSelectedItem(); item != nil {
+				log.Printf("HistoryView: Adding track to queue: %s", item.Track.ID)
+				m.spotifyState.Queue.Enqueue(item.Track)
+				return m, state.UpdateQueue()
+			}
+			return m, nil
+
+		case key.Matches(msg, DefaultKeyMap.QueueAll):
+			for _, item := range m.spotifyState.GetRecentlyPlayed() {
+				m.spotifyState.Queue.Enqueue(item.Track)
+			}
+			return m, state.UpdateQueue()
+
+		case key.Matches(msg, DefaultKeyMap.PlayNext):
+			if item := m.getSelectedItem(); item != nil {
+				log.Printf("HistoryView: Playing track next: %s", item.Track.ID)
+				m.spotifyState.Queue.EnqueueNext(item.Track)
+				return m, state.UpdateQueue()
+			}
+			return m, nil
+
+		case key.Matches(msg, DefaultKeyMap.Copy):
+			if item := m.getSelectedItem(); item != nil && item.Track.ID != "" {
+				return m, showCopyDialog(trackCopyItem(item.Track))
+			}
+			return m, nil
+
+		case key.Matches(msg, DefaultKeyMap.OpenContext):
+			if item := m.getSelectedItem(); item != nil {
+				return m, m.openContext(item.PlaybackContext)
+			}
+			return m, nil
+		}
+	}
+
+	m.table, cmd = m.table.Update(msg)
+	if _, ok := msg.(tea.KeyMsg); ok {
+		return m, tea.Batch(cmd, m.checkAndFetchMoreIfNeeded())
+	}
+	return m, cmd
+}
+
+func (m historyViewModel) View() string {
+	m.table = m.table.Border(RoundedTableBorders).
+		HeaderStyle(
+			lipgloss.NewStyle().
+				BorderForeground(getBorderStyle(m.isFocused)))
+	m.table = m.table.WithBaseStyle(
+		lipgloss.NewStyle().BorderForeground(getBorderStyle(m.isFocused)),
+	)
+	m.table = m.table.Focused(m.isFocused)
+
+	styledName := lipgloss.NewStyle().
+		Foreground(TextColor).
+		Padding(0, 1).
+		Render("Recently Played")
+
+	styledPage := lipgloss.NewStyle().
+		Foreground(WhiteTextColor).
+		Padding(0, 1).
+		Render(fmt.Sprintf("| Page %d/%d", m.table.CurrentPage(), m.table.MaxPages()))
+
+	m.table = m.table.WithStaticFooter(
+		styledName + styledPage,
+	)
+
+	return m.table.View()
+}
+
+func (m *historyViewModel) updateTableWithHistory() {
+	items := m.spotifyState.GetRecentlyPlayed()
+	playlists := m.spotifyState.GetPlaylists()
+
+	rows := make([]table.Row, 0, len(items))
+	for i, item := range items {
+		artistName := "Unknown Artist"
+		if len(item.Track.Artists) > 0 {
+			artistName = item.Track.Artists[0].Name
+		}
+
+		rows = append(rows, table.NewRow(table.RowData{
+			"#":       fmt.Sprintf("%d", i+1),
+			"title":   item.Track.Name,
+			"artist":  artistName,
+			"played":  formatTimeAgo(item.PlayedAt),
+			"context": describePlaybackContext(item.PlaybackContext, playlists),
+			"index":   i,
+		}))
+	}
+
+	m.table = m.table.WithRows(rows)
+}
+
+func (m *historyViewModel) getSelectedItem() *spotify.RecentlyPlayedItem {
+	selected := m.table.HighlightedRow()
+	if selected.Data == nil {
+		return nil
+	}
+
+	idx, ok := selected.Data["index"].(int)
+	if !ok {
+		return nil
+	}
+
+	items := m.spotifyState.GetRecentlyPlayed()
+	if idx < 0 || idx >= len(items) {
+		return nil
+	}
+	return &items[idx]
+}
+
+// openContext navigates to the playlist, album or artist a track was played from
+func (m *historyViewModel) openContext(playbackContext spotify.PlaybackContext) tea.Cmd {
+	id, vp, ok := parsePlaybackContext(playbackContext)
+	if !ok {
+		return showErrorToast("No Playback Context", "This track was not played from a playlist, album or artist")
+	}
+
+	log.Printf("HistoryView: Opening context %s", playbackContext.URI)
+	m.spotifyState.SetSelectedID(id)
+	return navigateToPlaylistView(id, vp)
+}
+
+// checkAndFetchMoreIfNeeded loads older history once the cursor reaches the last loaded page
+func (m *historyViewModel) checkAndFetchMoreIfNeeded() tea.Cmd {
+	if !m.spotifyState.HasMoreRecentlyPlayed() {
+		return nil
+	}
+
+	if m.table.CurrentPage() < m.table.MaxPages() {
+		return nil
+	}
+
+	return m.spotifyState.FetchMoreRecentlyPlayed(m.ctx)
+}
+
+// getNextTrack returns the track that was played before the currently playing one
+func (m *historyViewModel) getNextTrack() spotify.ID {
+	items := m.spotifyState.GetRecentlyPlayed()
+	if len(items) == 0 {
+		log.Println("No tracks in history to autoplay")
+		return ""
+	}
+
+	playerState := m.spotifyState.GetPlayerState()
+	if playerState.Item == nil {
+		return items[0].Track.ID
+	}
+
+	for i, item := range items {
+		if item.Track.ID == playerState.Item.ID {
+			if i >= len(items)-1 {
+				log.Println("Current track is the last one in history")
+				return ""
+			}
+			return items[i+1].Track.ID
+		}
+	}
+
+	return items[0].Track.ID
+}
+
+func createHistoryTable() table.Model {
+	return table.New([]table.Column{
+		table.NewColumn("#", "#", 4).WithStyle(lipgloss.NewStyle().Align(lipgloss.Center)),
+		table.NewFlexColumn("title", "Title", 1),
+		table.NewFlexColumn("artist", "Artist", 1),
+		table.NewColumn("played", "Played", 12).WithStyle(lipgloss.NewStyle().Align(lipgloss.Center)),
+		table.NewFlexColumn("context", "Context", 1),
+	}).WithRows([]table.Row{}).HeaderStyle(
+		lipgloss.NewStyle().
+			Bold(true).
+			BorderForeground(BorderColor).
+			Underline(true),
+	).WithBaseStyle(
+		lipgloss.NewStyle().
+			Align(lipgloss.Left).
+			BorderForeground(BorderColor),
+	).Focused(true).HighlightStyle(
+		lipgloss.NewStyle().
+			Foreground(PrimaryColor).
+			Padding(0, 0, 0, 1).Bold(true),
+	).Border(
+		RoundedTableBorders,
+	)
+}
+
+// parsePlaybackContext resolves a playback context into the ID and table view used to open it
+func parsePlaybackContext(playbackContext spotify.PlaybackContext) (spotify.ID, tableView, bool) {
+	return parseTableViewURI(string(playbackContext.URI))
+}
+
+// describePlaybackContext renders a short label for where a track was played from
+func describePlaybackContext(playbackContext spotify.PlaybackContext, playlists []spotify.SimplePlaylist) string {
+	if playbackContext.URI == "" {
+		return "-"
+	}
+
+	if playbackContext.Type == "playlist" {
+		for _, p := range playlists {
+			if p.URI == playbackContext.URI {
+				return "Playlist • " + p.Name
+			}
+		}
+	}
+
+	if playbackContext.Type == "" {
+		return "-"
+	}
+	return strings.ToUpper(playbackContext.Type[:1]) + playbackContext.Type[1:]
+}
@@ -14,17 +14,51 @@ type KeyMap struct {
 	CycleFocusBackward key.Binding
 
 	//Actions
-	Select     key.Binding
-	Copy       key.Binding
-	Return     key.Binding
-	AddToQueue key.Binding
+	Select       key.Binding
+	Copy         key.Binding
+	Return       key.Binding
+	AddToQueue   key.Binding
+	PlayNext     key.Binding
+	QueueAll     key.Binding
+	OpenContext  key.Binding
+	TimeRange    key.Binding
+	ExpandSearch key.Binding
+	Follow       key.Binding
+
+	// Multi-select in track tables
+	VisualMode         key.Binding
+	ToggleSelect       key.Binding
+	AddToPlaylist      key.Binding
+	RemoveFromPlaylist key.Binding
+	ToggleLike         key.Binding
+
+	// Filtering track tables
+	Filter        key.Binding
+	FilterLoadAll key.Binding
+
+	// Sorting track tables
+	SortNext     key.Binding
+	SortPrevious key.Binding
+	SortReverse  key.Binding
+
+	// Search bar
+	SearchHistoryOlder  key.Binding
+	SearchHistoryNewer  key.Binding
+	AcceptSuggestion    key.Binding
+	RemoveSearchHistory key.Binding
+	ClearSearchHistory  key.Binding
 
 	// System
-	Quit         key.Binding
-	Help         key.Binding
-	Search       key.Binding
-	ViewQueue    key.Binding
-	DeviceDialog key.Binding
+	Quit          key.Binding
+	Help          key.Binding
+	Search        key.Binding
+	ViewQueue     key.Binding
+	ViewHistory   key.Binding
+	ViewTop       key.Binding
+	SavePlaylist  key.Binding
+	DeviceDialog  key.Binding
+	LibrarySearch key.Binding
+	OpenLinks     key.Binding
 
 	// Media controls
 	Shuffle    key.Binding
@@ -35,6 +69,17 @@ type KeyMap struct {
 	VolumeMute key.Binding
 	VolumeUp   key.Binding
 	VolumeDown key.Binding
+
+	AutoplayFallback key.Binding
+
+	// Queue editing
+	QueueRemove       key.Binding
+	QueueClear        key.Binding
+	QueueMoveUp       key.Binding
+	QueueMoveDown     key.Binding
+	QueueShuffle      key.Binding
+	QueueDedupe       key.Binding
+	QueueRemoveArtist key.Binding
 }
 
 var DefaultKeyMap = KeyMap{
@@ -66,10 +111,114 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("a"),
 		key.WithHelp("a", "Add to queue"),
 	),
+	QueueAll: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "Add all to queue"),
+	),
+	PlayNext: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "Play next"),
+	),
+	QueueRemove: key.NewBinding(
+		key.WithKeys("x", "delete"),
+		key.WithHelp("x", "remove from queue"),
+	),
+	QueueClear: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "clear queue"),
+	),
+	QueueMoveUp: key.NewBinding(
+		key.WithKeys("K", "ctrl+up"),
+		key.WithHelp("K", "move track up"),
+	),
+	QueueMoveDown: key.NewBinding(
+		key.WithKeys("J", "ctrl+down"),
+		key.WithHelp("J", "move track down"),
+	),
+	QueueShuffle: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "shuffle queue"),
+	),
+	QueueDedupe: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "remove duplicates"),
+	),
+	QueueRemoveArtist: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "remove all by artist"),
+	),
 	ViewQueue: key.NewBinding(
 		key.WithKeys("q"),
 		key.WithHelp("q", "View queue"),
 	),
+	ViewHistory: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "View recently played"),
+	),
+	ViewTop: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "View top artists and tracks"),
+	),
+	SavePlaylist: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "Save tracks as playlist"),
+	),
+	TimeRange: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "cycle top items time range"),
+	),
+	ExpandSearch: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "expand search results"),
+	),
+	Follow: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "follow / unfollow"),
+	),
+	VisualMode: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "visual select mode"),
+	),
+	ToggleSelect: key.NewBinding(
+		key.WithKeys("V"),
+		key.WithHelp("V", "mark / unmark track"),
+	),
+	AddToPlaylist: key.NewBinding(
+		key.WithKeys("+"),
+		key.WithHelp("+", "add to playlist"),
+	),
+	RemoveFromPlaylist: key.NewBinding(
+		key.WithKeys("-"),
+		key.WithHelp("-", "remove from playlist"),
+	),
+	ToggleLike: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "like / unlike"),
+	),
+	Filter: key.NewBinding(
+		key.WithKeys("ctrl+f"),
+		key.WithHelp("ctrl+f", "filter tracks"),
+	),
+	FilterLoadAll: key.NewBinding(
+		key.WithKeys("ctrl+l"),
+		key.WithHelp("ctrl+l", "load all tracks to filter"),
+	),
+	SortNext: key.NewBinding(
+		key.WithKeys(">"),
+		key.WithHelp(">", "sort by next column"),
+	),
+	SortPrevious: key.NewBinding(
+		key.WithKeys("<"),
+		key.WithHelp("<", "sort by previous column"),
+	),
+	SortReverse: key.NewBinding(
+		key.WithKeys("~"),
+		key.WithHelp("~", "reverse sort order"),
+	),
+	OpenContext: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open playback context"),
+	),
 	CycleFocusForward: key.NewBinding(
 		key.WithKeys("tab"),
 		key.WithHelp("tab", "cycle focus forward"),
@@ -80,7 +229,7 @@ var DefaultKeyMap = KeyMap{
 	),
 	Copy: key.NewBinding(
 		key.WithKeys("c"),
-		key.WithHelp("c", "copy URL to clipboard"),
+		key.WithHelp("c", "copy link, URI or text"),
 	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
@@ -98,6 +247,34 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("/"),
 		key.WithHelp("/", "search"),
 	),
+	SearchHistoryOlder: key.NewBinding(
+		key.WithKeys("up"),
+		key.WithHelp("↑", "older search (search bar)"),
+	),
+	SearchHistoryNewer: key.NewBinding(
+		key.WithKeys("down"),
+		key.WithHelp("↓", "newer search (search bar)"),
+	),
+	AcceptSuggestion: key.NewBinding(
+		key.WithKeys("right"),
+		key.WithHelp("→", "accept suggestion, ctrl+n/p to cycle"),
+	),
+	RemoveSearchHistory: key.NewBinding(
+		key.WithKeys("ctrl+x"),
+		key.WithHelp("ctrl+x", "forget shown past search"),
+	),
+	ClearSearchHistory: key.NewBinding(
+		key.WithKeys("alt+x"),
+		key.WithHelp("alt+x", "clear search history"),
+	),
+	LibrarySearch: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "find a track in your playlists"),
+	),
+	OpenLinks: key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "open a Spotify link or URI"),
+	),
 	DeviceDialog: key.NewBinding(
 		key.WithKeys("d"),
 		key.WithHelp("d", "open device dialog"),
@@ -130,4 +307,8 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("m"),
 		key.WithHelp("m", "mute/unmute volume"),
 	),
+	AutoplayFallback: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "cycle autoplay fallback"),
+	),
 }
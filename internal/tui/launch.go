@@ -0,0 +1,184 @@
+package tui
+
+import (
+	"log"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dietzy1/termify/internal/config"
+	"github.com/dietzy1/termify/internal/control"
+	"github.com/dietzy1/termify/internal/state"
+)
+
+// launchRequest is what the command line asked to open when Termify starts
+type launchRequest struct {
+	links  []state.SpotifyLink
+	play   bool
+	device string
+
+	// Library selection to keep once the playlists load, which would otherwise select the first entry
+	selectURI string
+	// Start playing once the linked tracks are queued, from where they start in the queue
+	playQueued bool
+	queueStart int
+}
+
+// launchMsg is sent once the launch device is ready
+type launchMsg struct{}
+
+// newLaunchRequest reads the launch arguments, returning nil when there are none
+func newLaunchRequest(c *config.Config) *launchRequest {
+	request := &launchRequest{
+		play:   c.ShouldPlayOnLaunch(),
+		device: c.GetLaunchDevice(),
+	}
+	for _, item := range c.GetLaunchItems() {
+		link, ok := state.ParseSpotifyLink(item)
+		if !ok {
+			log.Printf("Application: Ignoring launch argument %q, not a Spotify link or URI", item)
+			continue
+		}
+		request.links = append(request.links, link)
+	}
+
+	if len(request.links) == 0 && !request.play && request.device == "" {
+		return nil
+	}
+	if len(request.links) == 1 {
+		if _, _, ok := parseTableViewURI(string(request.links[0].URI())); ok {
+			request.selectURI = string(request.links[0].URI())
+		}
+	}
+	return request
+}
+
+// launchCmd switches to the launch device first so playback starts there. An attached TUI
+// leaves that to the daemon.
+func (m applicationModel) launchCmd() tea.Cmd {
+	if m.launch == nil {
+		return nil
+	}
+	ready := func() tea.Msg {
+		return launchMsg{}
+	}
+	if m.launch.device == "" || m.daemon != nil {
+		return ready
+	}
+	return tea.Sequence(m.spotifyState.SelectDeviceByName(m.ctx, m.launch.device), ready)
+}
+
+// handleLaunch opens or plays the launch items
+func (m *applicationModel) handleLaunch() tea.Cmd {
+	request := m.launch
+	log.Printf("Application: Launching with %d items, play %t", len(request.links), request.play)
+	if m.daemon != nil {
+		return m.launchOnDaemon()
+	}
+
+	switch {
+	case len(request.links) == 0:
+		if request.play {
+			return m.spotifyState.StartPlayback(m.ctx)
+		}
+		return nil
+
+	case len(request.links) > 1:
+		// The linked tracks go after the queue restored from the last session
+		request.playQueued = request.play
+		request.queueStart = m.spotifyState.Queue.Size()
+		return m.openLinks(request.links)
+
+	case request.selectURI != "":
+		m.focusedModel = FocusPlaylistView
+		cmd := m.openLinks(request.links)
+		if request.play {
+			cmd = tea.Batch(cmd, m.spotifyState.PlayLink(m.ctx, request.links[0]))
+		}
+		return cmd
+	}
+
+	// Tracks, episodes and shows have no view of their own and are always played
+	return m.openLinks(request.links)
+}
+
+// launchOnDaemon sends the launch items to the daemon, which owns playback and the queue.
+// Views are still opened here.
+func (m *applicationModel) launchOnDaemon() tea.Cmd {
+	request := m.launch
+	var requests []control.Request
+	if request.device != "" {
+		requests = append(requests, control.Request{Command: control.CommandDevice, Value: request.device})
+	}
+
+	var open tea.Cmd
+	links := request.links
+	switch {
+	case len(links) == 0:
+		if request.play {
+			requests = append(requests, control.Request{Command: control.CommandPlay})
+		}
+
+	case len(links) > 1:
+		// Play the first link and queue the rest behind the daemon's queue
+		if request.play {
+			requests = append(requests, control.Request{Command: control.CommandPlay, Value: string(links[0].URI())})
+			links = links[1:]
+		}
+		uris := make([]string, len(links))
+		for i, link := range links {
+			uris[i] = string(link.URI())
+		}
+		requests = append(requests, control.Request{Command: control.CommandEnqueue, Value: strings.Join(uris, " ")})
+
+	case request.selectURI != "":
+		m.focusedModel = FocusPlaylistView
+		open = m.openLinks(links)
+		if request.play {
+			requests = append(requests, control.Request{Command: control.CommandPlay, Value: request.selectURI})
+		}
+
+	default:
+		// Tracks, episodes and shows have no view of their own and are always played
+		requests = append(requests, control.Request{Command: control.CommandPlay, Value: string(links[0].URI())})
+	}
+
+	sends := make([]tea.Cmd, len(requests))
+	for i, request := range requests {
+		sends[i] = m.sendToDaemon(request)
+	}
+	return tea.Batch(open, tea.Sequence(sends...))
+}
+
+// playLaunchQueue plays the first of the queued launch tracks when the launch asked to play them
+func (m *applicationModel) playLaunchQueue(msg state.LinksQueuedMsg) tea.Cmd {
+	if m.launch == nil || !m.launch.playQueued || msg.Queued == 0 {
+		return nil
+	}
+	m.launch.playQueued = false
+
+	track, err := m.spotifyState.Queue.PopAt(m.launch.queueStart)
+	if err != nil {
+		log.Printf("Application: Failed to take the first launch track from the queue: %v", err)
+		return nil
+	}
+	return tea.Batch(state.UpdateQueue(), m.spotifyState.PlayTrack(m.ctx, track.ID))
+}
+
+// pendingSelection returns the library entry to select once the playlists load: the launch item,
+// or else the source open in the last session
+func (m *applicationModel) pendingSelection() string {
+	if m.launch != nil && m.launch.selectURI != "" {
+		uri := m.launch.selectURI
+		m.launch.selectURI = ""
+		return uri
+	}
+	if m.launch != nil && len(m.launch.links) > 0 {
+		return ""
+	}
+	if m.restoredSession != nil && m.restoredSession.SelectedID != "" {
+		uri := "spotify:" + m.restoredSession.SelectedKind + ":" + string(m.restoredSession.SelectedID)
+		m.restoredSession.SelectedID = ""
+		return uri
+	}
+	return ""
+}
@@ -1,11 +1,14 @@
 package tui
 
 import (
+	"context"
+
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
 )
 
 var _ tea.Model = (*libraryModel)(nil)
@@ -22,13 +25,14 @@ func (p playlist) Description() string { return p.desc }
 func (p playlist) FilterValue() string { return p.title }
 
 type libraryModel struct {
+	ctx          context.Context
 	height       int
 	list         list.Model
 	spotifyState *state.SpotifyState
 	isFocused    bool
 }
 
-func newLibrary(spotifyState *state.SpotifyState) libraryModel {
+func newLibrary(ctx context.Context, spotifyState *state.SpotifyState) libraryModel {
 	delegate := list.NewDefaultDelegate()
 
 	const itemWidth = 28
@@ -61,6 +65,7 @@ func newLibrary(spotifyState *state.SpotifyState) libraryModel {
 	l.DisableQuitKeybindings()
 
 	return libraryModel{
+		ctx:          ctx,
 		list:         l,
 		spotifyState: spotifyState,
 	}
@@ -83,11 +88,61 @@ func (m libraryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.list.SetItems(m.convertPlaylistsToItems())
 		return m, m.spotifyState.SelectPlaylist(string(m.list.SelectedItem().(playlist).uri))
 
+	case state.LibraryUpdatedMsg:
+		// Patch the items in place and keep the cursor on the same entry
+		var selectedURI string
+		if selected, ok := m.list.SelectedItem().(playlist); ok {
+			selectedURI = selected.uri
+		}
+
+		items := m.convertPlaylistsToItems()
+		m.list.SetItems(items)
+		for i, it := range items {
+			if it.(playlist).uri == selectedURI {
+				m.list.Select(i)
+				return m, nil
+			}
+		}
+		if m.list.Index() >= len(items) && len(items) > 0 {
+			m.list.Select(len(items) - 1)
+		}
+		return m, nil
+
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, DefaultKeyMap.Up, DefaultKeyMap.Down, DefaultKeyMap.Left, DefaultKeyMap.Right):
 			m.list, cmd = m.list.Update(msg)
 			return m, tea.Batch(cmd, m.spotifyState.SelectPlaylist(string(m.list.SelectedItem().(playlist).uri)))
+
+		case key.Matches(msg, DefaultKeyMap.AddToQueue):
+			selected, ok := m.list.SelectedItem().(playlist)
+			if !ok {
+				return m, nil
+			}
+			if id, view, ok := parseTableViewURI(selected.uri); ok {
+				return m, queueSource(m.ctx, m.spotifyState, id, view)
+			}
+			return m, nil
+
+		case key.Matches(msg, DefaultKeyMap.Copy):
+			selected, ok := m.list.SelectedItem().(playlist)
+			if !ok {
+				return m, nil
+			}
+			if link, ok := state.ParseSpotifyLink(selected.uri); ok {
+				return m, showCopyDialog(copyItem{link: link, name: selected.title})
+			}
+			return m, nil
+
+		case key.Matches(msg, DefaultKeyMap.Follow):
+			selected, ok := m.list.SelectedItem().(playlist)
+			if !ok {
+				return m, nil
+			}
+			if id, view, ok := parseTableViewURI(selected.uri); ok {
+				return m, toggleFollow(m.ctx, m.spotifyState, id, view)
+			}
+			return m, nil
 		}
 	}
 
@@ -95,6 +150,16 @@ func (m libraryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// selectURI moves the cursor to the item with the given URI, if it is in the library
+func (m *libraryModel) selectURI(uri string) {
+	for i, it := range m.list.Items() {
+		if it.(playlist).uri == uri {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
 func (m libraryModel) View() string {
 	// Update delegate styles based on focus
 	delegate := list.NewDefaultDelegate()
@@ -167,5 +232,57 @@ func (m libraryModel) convertPlaylistsToItems() []list.Item {
 			uri:   string(p.URI),
 		})
 	}
+
+	for _, album := range m.spotifyState.GetSavedAlbums() {
+		desc := "Album"
+		if len(album.Artists) > 0 {
+			desc = "Album • " + album.Artists[0].Name
+		}
+		items = append(items, playlist{
+			title: album.Name,
+			desc:  desc,
+			uri:   string(album.URI),
+		})
+	}
+
+	for _, artist := range m.spotifyState.GetFollowedArtists() {
+		items = append(items, playlist{
+			title: artist.Name,
+			desc:  "Artist",
+			uri:   string(artist.URI),
+		})
+	}
 	return items
 }
+
+// libraryMarker prefixes items that are already followed or saved
+const libraryMarker = "✓ "
+
+// isInLibrary reports whether the playlist, artist or album shown by a table view is followed or saved
+func isInLibrary(spotifyState *state.SpotifyState, id spotify.ID, view tableView) bool {
+	switch view {
+	case artistTopTracksView:
+		return spotifyState.IsFollowingArtist(id)
+	case albumTracksView:
+		return spotifyState.IsAlbumSaved(id)
+	default:
+		return spotifyState.IsFollowingPlaylist(id)
+	}
+}
+
+// queueSource adds every track of the playlist, artist or album shown by a table view to the queue
+func queueSource(ctx context.Context, spotifyState *state.SpotifyState, id spotify.ID, view tableView) tea.Cmd {
+	return spotifyState.QueueSource(ctx, id, tableViewKind(view))
+}
+
+// toggleFollow follows or unfollows the playlist, artist or album shown by a table view
+func toggleFollow(ctx context.Context, spotifyState *state.SpotifyState, id spotify.ID, view tableView) tea.Cmd {
+	switch view {
+	case artistTopTracksView:
+		return spotifyState.ToggleFollowArtist(ctx, id)
+	case albumTracksView:
+		return spotifyState.ToggleSaveAlbum(ctx, id)
+	default:
+		return spotifyState.ToggleFollowPlaylist(ctx, id)
+	}
+}
@@ -0,0 +1,276 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
+)
+
+const (
+	// Give the startup requests a head start before warming the index
+	libraryIndexStartDelay = 5 * time.Second
+	// One playlist page is loaded per step to stay well below the API rate limit
+	libraryIndexInterval = 400 * time.Millisecond
+	libraryIndexBackoff  = 15 * time.Second
+
+	librarySearchDialogWidth = 70
+	librarySearchVisibleRows = 14
+	librarySearchMaxMatches  = 100
+)
+
+type libraryIndexTickMsg struct{}
+
+func scheduleLibraryIndex(delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return libraryIndexTickMsg{}
+	})
+}
+
+// libraryJumpMsg opens a library playlist with the cursor on one of its rows
+type libraryJumpMsg struct {
+	PlaylistID spotify.ID
+	Index      int
+}
+
+// trackJump is a row to highlight once the source's tracks arrive
+type trackJump struct {
+	sourceID spotify.ID
+	index    int
+}
+
+// startLibraryIndex starts warming the index unless it is already running
+func (m *applicationModel) startLibraryIndex(delay time.Duration) tea.Cmd {
+	if m.libraryIndexing {
+		return nil
+	}
+	m.libraryIndexing = true
+	return scheduleLibraryIndex(delay)
+}
+
+// handleLibraryIndexProgress schedules the next warming step, backing off after an error
+func (m applicationModel) handleLibraryIndexProgress(msg state.LibraryIndexProgressMsg) (applicationModel, tea.Cmd) {
+	switch {
+	case msg.Done, m.ctx.Err() != nil:
+		m.libraryIndexing = false
+		return m, nil
+	case msg.Err != nil:
+		return m, scheduleLibraryIndex(libraryIndexBackoff)
+	}
+	return m, scheduleLibraryIndex(libraryIndexInterval)
+}
+
+// librarySearchLine is one line of the result list, either a track or one of the rows it appears on
+type librarySearchLine struct {
+	match    int
+	location *state.LibraryLocation
+}
+
+type librarySearchDialogContent struct {
+	ctx          context.Context
+	width        int
+	height       int
+	spotifyState *state.SpotifyState
+	input        textinput.Model
+
+	matches []state.LibraryMatch
+	lines   []librarySearchLine
+	cursor  int // Index into lines, always on a location line when there are results
+}
+
+func NewLibrarySearchDialog(ctx context.Context, spotifyState *state.SpotifyState) DialogContent {
+	ti := textinput.New()
+	ti.Placeholder = "Title, artist or album"
+	ti.CharLimit = 100
+	ti.Prompt = "› "
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(PrimaryColor)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(WhiteTextColor)
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(TextColor)
+	ti.Cursor.SetMode(cursor.CursorStatic)
+	ti.Focus()
+
+	return &librarySearchDialogContent{
+		ctx:          ctx,
+		spotifyState: spotifyState,
+		input:        ti,
+	}
+}
+
+func (m *librarySearchDialogContent) Init() tea.Cmd {
+	return nil
+}
+
+func (m *librarySearchDialogContent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg.(type) {
+	// Pages warmed in the background can add matches
+	case state.LibraryIndexProgressMsg, state.PlaylistTracksChangedMsg:
+		m.search()
+	}
+	return m, nil
+}
+
+// search runs the query against the library index, keeping the cursor where it was if possible
+func (m *librarySearchDialogContent) search() {
+	m.matches = m.spotifyState.SearchLibrary(m.input.Value())
+	if len(m.matches) > librarySearchMaxMatches {
+		m.matches = m.matches[:librarySearchMaxMatches]
+	}
+
+	m.lines = m.lines[:0]
+	for i := range m.matches {
+		m.lines = append(m.lines, librarySearchLine{match: i})
+		for j := range m.matches[i].Locations {
+			m.lines = append(m.lines, librarySearchLine{match: i, location: &m.matches[i].Locations[j]})
+		}
+	}
+	if m.cursor >= len(m.lines) || m.lines[m.cursor].location == nil {
+		m.cursor = 0
+		m.moveCursor(1)
+	}
+}
+
+// moveCursor steps to the next location line in the given direction, skipping track lines
+func (m *librarySearchDialogContent) moveCursor(step int) {
+	for i := m.cursor + step; i >= 0 && i < len(m.lines); i += step {
+		if m.lines[i].location != nil {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+func (m *librarySearchDialogContent) View() string {
+	width := librarySearchDialogWidth
+	if m.width > 0 {
+		width = min(width, m.width-10)
+	}
+
+	indexed, failed, total := m.spotifyState.GetLibraryIndexProgress()
+	progress := fmt.Sprintf("%d/%d playlists indexed", indexed, total)
+	if failed > 0 {
+		progress += fmt.Sprintf(", %d failed to load", failed)
+	}
+	if indexed+failed < total {
+		progress += ", warming in the background"
+	}
+
+	info := lipgloss.NewStyle().
+		Foreground(TextColor).
+		Italic(true).
+		Width(width).
+		Render(progress)
+
+	input := lipgloss.NewStyle().
+		Width(width).
+		MarginBottom(1).
+		Render(m.input.View())
+
+	return lipgloss.JoinVertical(lipgloss.Left, input, m.renderResults(width), info)
+}
+
+func (m *librarySearchDialogContent) renderResults(width int) string {
+	emptyStyle := lipgloss.NewStyle().
+		Foreground(TextColor).
+		Width(width).
+		Height(librarySearchVisibleRows).
+		Align(lipgloss.Center)
+
+	if strings.TrimSpace(m.input.Value()) == "" {
+		return emptyStyle.Render("Find a track in your playlists")
+	}
+	if len(m.lines) == 0 {
+		return emptyStyle.Render("No tracks found")
+	}
+
+	trackStyle := lipgloss.NewStyle().Foreground(WhiteTextColor).Bold(true).Width(width).MaxWidth(width)
+	locationStyle := lipgloss.NewStyle().Foreground(TextColor).Width(width).MaxWidth(width).PaddingLeft(2)
+	selectedStyle := locationStyle.Foreground(PrimaryColor).Bold(true)
+
+	start := max(0, min(m.cursor-librarySearchVisibleRows/2, len(m.lines)-librarySearchVisibleRows))
+	end := min(start+librarySearchVisibleRows, len(m.lines))
+
+	rows := make([]string, 0, librarySearchVisibleRows)
+	for i := start; i < end; i++ {
+		line := m.lines[i]
+		if line.location == nil {
+			track := m.matches[line.match].Track
+			rows = append(rows, trackStyle.Render(fmt.Sprintf("%s — %s", track.Name, allArtistNames(track))))
+			continue
+		}
+
+		text := fmt.Sprintf("%s  #%d", line.location.PlaylistName, line.location.Index+1)
+		if i == m.cursor {
+			rows = append(rows, selectedStyle.Render("→ "+text))
+		} else {
+			rows = append(rows, locationStyle.Render("  "+text))
+		}
+	}
+
+	return lipgloss.NewStyle().Height(librarySearchVisibleRows).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+func (m *librarySearchDialogContent) GetTitle() string {
+	return fmt.Sprintf("Search Library (%d tracks)", len(m.matches))
+}
+
+func (m *librarySearchDialogContent) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m *librarySearchDialogContent) HandleDialogKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch {
+	// Tab switches the dialog actions, enter opens and esc closes
+	case key.Matches(msg, key.NewBinding(key.WithKeys("tab", "shift+tab", "enter", "esc"))):
+		return false, nil
+	case key.Matches(msg, key.NewBinding(key.WithKeys("up", "ctrl+p"))):
+		m.moveCursor(-1)
+		return true, nil
+	case key.Matches(msg, key.NewBinding(key.WithKeys("down", "ctrl+n"))):
+		m.moveCursor(1)
+		return true, nil
+	}
+
+	// Everything else edits the query, including left and right to move within it
+	previous := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.cursor = 0
+		m.search()
+	}
+	return true, cmd
+}
+
+func (m *librarySearchDialogContent) GetActions() []DialogAction {
+	openCmd := func() tea.Msg {
+		if m.cursor >= len(m.lines) || m.lines[m.cursor].location == nil {
+			return DialogMsg{Accepted: false}
+		}
+		location := m.lines[m.cursor].location
+		return libraryJumpMsg{PlaylistID: location.PlaylistID, Index: location.Index}
+	}
+
+	return []DialogAction{
+		{
+			Label: "Open",
+			Key:   key.NewBinding(key.WithKeys("enter")),
+			Cmd:   openCmd,
+		},
+		{
+			Label: "Close",
+			Key:   key.NewBinding(key.WithKeys("esc")),
+			Cmd: func() tea.Msg {
+				return DialogMsg{Accepted: false}
+			},
+		},
+	}
+}
@@ -22,6 +22,8 @@ type navbarModel struct {
 	width, height int
 	deviceCount   int
 	queueCount    int
+	queueProgress string // Shown while a bulk enqueue is still loading pages
+	status        string // Short feedback such as what was copied
 }
 
 func newNavbar() navbarModel {
@@ -68,6 +70,26 @@ func (m navbarModel) View() string {
 			MarginLeft(1).
 			Render(fmt.Sprintf("Queue (%d)", m.queueCount)),
 	)
+	if m.queueProgress != "" {
+		queueText = lipgloss.JoinHorizontal(lipgloss.Left,
+			queueText,
+			lipgloss.NewStyle().
+				Foreground(PrimaryColor).
+				MarginLeft(1).
+				Render(m.queueProgress),
+		)
+	}
+
+	if m.status != "" {
+		queueText = lipgloss.JoinHorizontal(lipgloss.Left,
+			lipgloss.NewStyle().
+				Foreground(PrimaryColor).
+				MarginRight(2).
+				MaxWidth(40).
+				Render(m.status),
+			queueText,
+		)
+	}
 
 	var paddingTop = 0
 	if m.height > 1 {
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/zmb3/spotify/v2"
@@ -37,6 +39,25 @@ func navigateToPlaylistView(selectedID spotify.ID, vp tableView) tea.Cmd {
 	return NavigateCmd(FocusPlaylistView, true, selectedID, vp)
 }
 
+// parseTableViewURI resolves a playlist, artist or album URI into the ID and table view used to open it
+func parseTableViewURI(uri string) (spotify.ID, tableView, bool) {
+	parts := strings.Split(uri, ":")
+	if len(parts) < 3 || parts[len(parts)-1] == "" {
+		return "", playlistView, false
+	}
+	id := spotify.ID(parts[len(parts)-1])
+
+	switch parts[len(parts)-2] {
+	case "playlist":
+		return id, playlistView, true
+	case "album":
+		return id, albumTracksView, true
+	case "artist":
+		return id, artistTopTracksView, true
+	}
+	return "", playlistView, false
+}
+
 func navigateToLibrary() tea.Cmd {
 	return NavigateCmd(FocusLibrary, false, "", playlistView)
 }
@@ -60,6 +81,7 @@ func (m applicationModel) handleNavigationMsg(msg NavigationMsg) (applicationMod
 	}
 
 	if msg.selectedID != "" {
+		m.playlistView.setViewType(msg.viewport)
 		switch msg.viewport {
 		case playlistView:
 			cmds = append(cmds, m.spotifyState.FetchPlaylistTracks(m.ctx, msg.selectedID))
@@ -80,28 +102,42 @@ func (m applicationModel) renderNavigationHelp() string {
 	switch m.focusedModel {
 	case FocusLibrary:
 		focusName = "Library"
-		helpText = "Tab: Switch to content view | /: Search"
+		helpText = "Tab: Switch to content view | a: Queue | f: Unfollow | c: Copy | g: Find in playlists | /: Search"
 	case FocusPlaylistView:
 		focusName = "Playlist"
-		helpText = "Tab: Switch to library | /: Search"
+		helpText = "Tab: Switch to library | A: Queue all | f: Follow | c: Copy | v/V: Select | ctrl+f: Filter | </>: Sort | /: Search"
+		if m.playlistView.filter.typing {
+			helpText = "Enter: Apply filter | ctrl+l: Load all tracks | Esc: Clear filter"
+		} else if m.playlistView.hasSelection() {
+			helpText = "Enter: Play selection | a: Queue | N: Play next | +/-: Add/remove | L: Like | c: Copy | Esc: Clear"
+		}
 	case FocusSearchTracksView:
 		focusName = "Search Tracks"
-		helpText = "Tab: Cycle search views | /: Search"
+		helpText = "Tab: Cycle search views | c: Copy | e: Expand | /: Search"
 	case FocusSearchPlaylistsView:
 		focusName = "Search Playlists"
-		helpText = "Tab: Cycle search views | /: Search"
+		helpText = "Tab: Cycle search views | a: Queue | f: Follow | c: Copy | e: Expand | /: Search"
 	case FocusSearchArtistsView:
 		focusName = "Search Artists"
-		helpText = "Tab: Cycle search views | /: Search"
+		helpText = "Tab: Cycle search views | a: Queue | f: Follow | c: Copy | e: Expand | /: Search"
 	case FocusSearchAlbumsView:
 		focusName = "Search Albums"
-		helpText = "Tab: Cycle search views | /: Search"
+		helpText = "Tab: Cycle search views | a: Queue | f: Follow | c: Copy | e: Expand | /: Search"
 	case FocusSearchBar:
 		focusName = "Search"
 		helpText = "Esc: Exit search | tab: Navigate to content"
 	case FocusQueue:
 		focusName = "View queue"
-		helpText = "Tab: Switch to library | Enter: Play selected track | C: Clear item | Esc: Back"
+		helpText = "Enter: Play | x: Remove | K/J: Move | N: Play next | X: Clear | S: Shuffle | D: Dedupe | R: Remove artist | c: Copy"
+	case FocusHistoryView:
+		focusName = "Recently played"
+		helpText = "Tab: Switch to library | Enter: Play | a: Queue | o: Open context | c: Copy | Esc: Back"
+	case FocusTopTracksView:
+		focusName = "Top tracks"
+		helpText = "Tab: Top artists | Enter: Play | a: Queue | c: Copy | t: Time range | Esc: Back"
+	case FocusTopArtistsView:
+		focusName = "Top artists"
+		helpText = "Tab: Top tracks | Enter: Open artist | f: Follow | c: Copy | t: Time range | Esc: Back"
 	}
 
 	switch m.activeViewport {
@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/state"
+)
+
+// Pasted links can be several URLs long
+const linksCharLimit = 4000
+
+const openLinksDialogWidth = 60
+
+// openLinksMsg asks the application to open or queue pasted Spotify links
+type openLinksMsg struct {
+	Links []state.SpotifyLink
+}
+
+// openLinks opens a single playlist, album or artist in the table view and plays a single track,
+// episode or show. Several links are added to the queue in the order they were pasted.
+func (m *applicationModel) openLinks(links []state.SpotifyLink) tea.Cmd {
+	if len(links) == 0 {
+		return nil
+	}
+	m.searchBar.ExitSearchMode()
+	m.activeViewport = MainView
+
+	if len(links) > 1 {
+		log.Printf("Application: Queueing %d pasted links", len(links))
+		m.navbar.queueProgress = fmt.Sprintf("• adding %d links", len(links))
+		return m.spotifyState.QueueLinks(m.ctx, links)
+	}
+
+	link := links[0]
+	log.Printf("Application: Opening %s", link.URI())
+	if id, view, ok := parseTableViewURI(string(link.URI())); ok {
+		m.library.selectURI(string(link.URI()))
+		m.spotifyState.SetSelectedID(id)
+		return tea.Batch(navigateToPlaylistView(id, view), tea.WindowSize())
+	}
+	return tea.Batch(
+		m.spotifyState.PlayLink(m.ctx, link),
+		navigateToLibrary(),
+		tea.WindowSize(),
+	)
+}
+
+// handleLinksQueued reports links that couldn't be queued and refreshes the queue
+func (m *applicationModel) handleLinksQueued(msg state.LinksQueuedMsg) tea.Cmd {
+	m.navbar.queueProgress = ""
+	if len(msg.Skipped) == 0 {
+		return state.UpdateQueue()
+	}
+
+	skipped := make([]string, len(msg.Skipped))
+	for i, link := range msg.Skipped {
+		skipped[i] = string(link.URI())
+	}
+	return tea.Batch(
+		state.UpdateQueue(),
+		showErrorToast(
+			fmt.Sprintf("Queued %d Tracks, Skipped %d Links", msg.Queued, len(msg.Skipped)),
+			"Podcasts can't be queued and some links failed to load: "+strings.Join(skipped, ", "),
+		),
+	)
+}
+
+// describeLinks tells what pressing enter does with the links
+func describeLinks(links []state.SpotifyLink) string {
+	if len(links) > 1 {
+		return fmt.Sprintf("enter: queue %d links", len(links))
+	}
+	switch links[0].Kind {
+	case "playlist", "album", "artist":
+		return "enter: open " + links[0].Kind
+	}
+	return "enter: play " + links[0].Kind
+}
+
+// openLinksDialogContent takes pasted open.spotify.com URLs and spotify: URIs
+type openLinksDialogContent struct {
+	width  int
+	height int
+	input  textinput.Model
+}
+
+func NewOpenLinksDialog() DialogContent {
+	ti := textinput.New()
+	ti.Placeholder = "open.spotify.com/... or spotify:..."
+	ti.CharLimit = linksCharLimit
+	ti.Prompt = "› "
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(PrimaryColor)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(WhiteTextColor)
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(TextColor)
+	ti.Cursor.SetMode(cursor.CursorStatic)
+	ti.Focus()
+
+	return &openLinksDialogContent{input: ti}
+}
+
+func (m *openLinksDialogContent) Init() tea.Cmd {
+	return nil
+}
+
+func (m *openLinksDialogContent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return m, nil
+}
+
+func (m *openLinksDialogContent) View() string {
+	width := openLinksDialogWidth
+	if m.width > 0 {
+		width = min(width, m.width-10)
+	}
+	m.input.Width = width - 3
+
+	info := "Paste one link to open or play it, or several to queue them all"
+	if links, ok := state.ParseSpotifyLinks(m.input.Value()); ok {
+		info = describeLinks(links)
+	} else if strings.TrimSpace(m.input.Value()) != "" {
+		info = "Not a Spotify link or URI"
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Width(width).MarginBottom(1).Render(m.input.View()),
+		lipgloss.NewStyle().Foreground(TextColor).Italic(true).Width(width).Render(info),
+	)
+}
+
+func (m *openLinksDialogContent) GetTitle() string {
+	return "Open Spotify Link"
+}
+
+func (m *openLinksDialogContent) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m *openLinksDialogContent) HandleDialogKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	// Tab switches the dialog actions, enter opens and esc closes
+	if key.Matches(msg, key.NewBinding(key.WithKeys("tab", "shift+tab", "enter", "esc"))) {
+		return false, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return true, cmd
+}
+
+func (m *openLinksDialogContent) GetActions() []DialogAction {
+	return []DialogAction{
+		{
+			Label: "Open",
+			Key:   key.NewBinding(key.WithKeys("enter")),
+			Cmd: func() tea.Msg {
+				links, ok := state.ParseSpotifyLinks(m.input.Value())
+				if !ok {
+					return ShowToastMsg{
+						Title:   "Invalid Link",
+						Message: "Paste open.spotify.com URLs or spotify: URIs separated by spaces.",
+					}
+				}
+				return openLinksMsg{Links: links}
+			},
+		},
+		{
+			Label: "Close",
+			Key:   key.NewBinding(key.WithKeys("esc")),
+			Cmd: func() tea.Msg {
+				return DialogMsg{Accepted: false}
+			},
+		},
+	}
+}
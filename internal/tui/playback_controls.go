@@ -110,6 +110,27 @@ func (m playbackControlsModel) View() string {
 	return lipgloss.JoinHorizontal(lipgloss.Bottom, renderedButtons...)
 }
 
+// autoplayFallbackView shows what autoplay does once the current view runs out of tracks
+func (m playbackControlsModel) autoplayFallbackView() string {
+	keyStyle := lipgloss.NewStyle().
+		Foreground(PrimaryColor).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(TextColor).
+		MarginLeft(1)
+
+	return lipgloss.NewStyle().
+		Width(28).
+		Align(lipgloss.Right).
+		PaddingRight(1).
+		MaxHeight(1).
+		Render(lipgloss.JoinHorizontal(lipgloss.Left,
+			keyStyle.Render(DefaultKeyMap.AutoplayFallback.Help().Key),
+			labelStyle.Render("→ "+m.spotifyState.GetAutoplayFallback().Label()),
+		))
+}
+
 func (m applicationModel) renderPlaybackSection() string {
 	// Get the song info and volume control views
 	songInfoView := m.audioPlayer.songInfoView()
@@ -146,6 +167,7 @@ func (m applicationModel) renderPlaybackSection() string {
 
 	rightSection := lipgloss.JoinVertical(
 		lipgloss.Right,
+		m.playbackControl.autoplayFallbackView(),
 		device,
 		volumeControlView,
 	)
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strconv"
+	"slices"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -20,6 +20,8 @@ type clearQueuedHighlightMsg struct {
 	TrackID spotify.ID
 }
 
+type clearStatusMsg struct{}
+
 type TrackRowType int
 
 const (
@@ -35,17 +37,30 @@ type TrackRow struct {
 }
 
 type playlistViewModel struct {
-	ctx            context.Context
-	width, height  int
-	table          table.Model
-	isFocused      bool
-	spotifyState   *state.SpotifyState
-	queuedTracks   map[spotify.ID]bool // Track which songs have been queued recently
-	highlightTimer *time.Timer         // Timer to clear the highlight
-	spinner        spinner.Model
+	ctx             context.Context
+	width, height   int
+	table           table.Model
+	isFocused       bool
+	spotifyState    *state.SpotifyState
+	queuedTracks    map[spotify.ID]bool // Track which songs have been queued recently
+	highlightTimer  *time.Timer         // Timer to clear the highlight
+	spinner         spinner.Model
+	viewType        tableView      // Whether the selected ID is a playlist, artist or album
+	restorePosition *tablePosition // Saved page and cursor applied once the source's tracks arrive
+	jumpTo          *trackJump     // Track to highlight once the source's tracks arrive
+	selection       trackSelection // Rows marked for bulk actions
+	filter          trackFilter
+	rowTracks       []int  // Index into the loaded tracks for every table row, -1 for loading rows
+	status          string // Short feedback after a bulk action, cleared by clearStatusMsg
+	// Source whose sorted or filtered rows are queued once every page is loaded
+	queueAllSource spotify.ID
+
+	columns    map[tableView][]string // Configured column layout per view type
+	sortColumn string                 // Column the rows are sorted by, empty for source order
+	sortDesc   bool
 }
 
-func newPlaylistView(ctx context.Context, spotifyState *state.SpotifyState) playlistViewModel {
+func newPlaylistView(ctx context.Context, spotifyState *state.SpotifyState, columns map[tableView][]string) playlistViewModel {
 
 	s := spinner.New()
 	s.Spinner = spinner.Dot
@@ -54,11 +69,86 @@ func newPlaylistView(ctx context.Context, spotifyState *state.SpotifyState) play
 
 	return playlistViewModel{
 		ctx:          ctx,
-		table:        createPlaylistTable(),
+		table:        createTrackTable(columns[playlistView]),
 		spotifyState: spotifyState,
 		queuedTracks: make(map[spotify.ID]bool),
 		spinner:      s,
+		selection:    newTrackSelection(),
+		filter:       newTrackFilter(),
+		columns:      columns,
+	}
+}
+
+// setViewType switches the table to the column layout of another view type
+func (m *playlistViewModel) setViewType(view tableView) {
+	m.viewType = view
+	if !slices.Contains(m.currentColumns(), m.sortColumn) {
+		m.sortColumn, m.sortDesc = "", false
+	}
+	m.table = m.table.WithColumns(tableColumns(m.currentColumns(), m.sortColumn, m.sortDesc))
+}
+
+// currentColumns returns the column layout of the current view type
+func (m playlistViewModel) currentColumns() []string {
+	if columns := m.columns[m.viewType]; len(columns) > 0 {
+		return columns
 	}
+	return defaultTrackColumns
+}
+
+// cycleSort moves the sort to the next or previous column, passing through source order
+func (m *playlistViewModel) cycleSort(step int) {
+	// Index 0 stands for source order, the columns follow
+	options := append([]string{""}, m.currentColumns()...)
+	current := max(slices.Index(options, m.sortColumn), 0)
+	m.sortColumn = options[(current+step+len(options))%len(options)]
+	m.sortDesc = false
+	m.applySort()
+}
+
+// reverseSort flips the sort direction, sorting by the first column if the rows are unsorted
+func (m *playlistViewModel) reverseSort() {
+	if m.sortColumn == "" {
+		m.sortColumn = m.currentColumns()[0]
+	} else {
+		m.sortDesc = !m.sortDesc
+	}
+	m.applySort()
+}
+
+// applySort rebuilds the rows in the new order and keeps the cursor on the same track
+func (m *playlistViewModel) applySort() {
+	highlighted := -1
+	if cursor := m.table.GetHighlightedRowIndex(); cursor >= 0 && cursor < len(m.rowTracks) {
+		highlighted = m.rowTracks[cursor]
+	}
+	// The visual range is kept in rows, which are about to move
+	if m.selection.visual {
+		m.selection.toggleVisual(m.table.GetHighlightedRowIndex(), m.rowTracks)
+	}
+
+	m.table = m.table.WithColumns(tableColumns(m.currentColumns(), m.sortColumn, m.sortDesc))
+	m.updateTableWithTracksAndLoading()
+
+	row := 0
+	if highlighted >= 0 {
+		row = max(slices.Index(m.rowTracks, highlighted), 0)
+	}
+	m.table = m.table.WithHighlightedRow(row)
+	m.updateTableWithTracksAndLoading()
+}
+
+// sortDescription describes the current sort for the footer
+func (m playlistViewModel) sortDescription() string {
+	column, ok := trackColumns[m.sortColumn]
+	if !ok {
+		return ""
+	}
+	direction := "ascending"
+	if m.sortDesc {
+		direction = "descending"
+	}
+	return fmt.Sprintf("| Sorted by %s, %s", column.title, direction)
 }
 
 func (m playlistViewModel) Init() tea.Cmd {
@@ -92,6 +182,36 @@ func (m playlistViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case state.TracksUpdatedMsg:
 		m.updateTableWithTracksAndLoading()
 
+		var queueCmd tea.Cmd
+		if m.queueAllSource != "" && m.queueAllSource == msg.SourceID {
+			queueCmd = m.queueDisplayed()
+		}
+
+		if m.filter.loadingAll && msg.SourceID == m.spotifyState.GetSelectedID() {
+			if cmd := m.fetchRemainingPage(); cmd != nil {
+				return m, tea.Batch(queueCmd, cmd)
+			}
+			m.filter.loadingAll = false
+		}
+
+		if m.restorePosition != nil && m.restorePosition.sourceID == msg.SourceID {
+			// The cursor decides the page if the terminal size changed since the session was saved
+			m.table = m.table.WithCurrentPage(m.restorePosition.page).WithHighlightedRow(m.restorePosition.cursor)
+			m.restorePosition = nil
+		}
+
+		if m.jumpTo != nil && m.jumpTo.sourceID == msg.SourceID {
+			if row := slices.Index(m.rowTracks, m.jumpTo.index); row >= 0 {
+				m.table = m.table.WithHighlightedRow(row)
+				m.jumpTo = nil
+			} else if cmd := m.fetchRemainingPage(); cmd != nil {
+				// The track is on a page that isn't loaded yet
+				return m, tea.Batch(queueCmd, cmd)
+			} else {
+				m.jumpTo = nil
+			}
+		}
+
 		if msg.NextPage != nil {
 			currentPage := m.table.CurrentPage()
 			pageSize := m.height - headerFooterHeight
@@ -106,20 +226,50 @@ func (m playlistViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if currentPage >= maxPageWithCurrentData-1 {
 				log.Printf("PlaylistView: Prefetching next page (current: %d, max loaded: %d)",
 					currentPage, maxPageWithCurrentData)
-				return m, m.spotifyState.FetchNextTracksPage(m.ctx, msg.SourceID, msg.NextPage)
+				return m, tea.Batch(queueCmd, m.spotifyState.FetchNextTracksPage(m.ctx, msg.SourceID, msg.NextPage))
 			}
 		}
 
 		log.Printf("PlaylistView: No prefetch needed (current page: %d, total tracks: %d)",
 			m.table.CurrentPage(), len(m.spotifyState.GetTracks()))
-		return m, nil
+		return m, queueCmd
 
 	// Handle keyboard events for table navigation
 	case tea.KeyMsg:
+		if m.filter.typing {
+			return m.updateFilterInput(msg)
+		}
+
 		switch {
+		case key.Matches(msg, DefaultKeyMap.Filter):
+			// The visual range is kept in rows, which are about to change
+			if m.selection.visual {
+				m.selection.toggleVisual(m.table.GetHighlightedRowIndex(), m.rowTracks)
+			}
+			m.filter.typing = true
+			return m, m.filter.input.Focus()
+
+		case key.Matches(msg, DefaultKeyMap.FilterLoadAll) && m.filter.active():
+			return m, m.loadAllForFilter()
+
+		case key.Matches(msg, DefaultKeyMap.SortNext):
+			m.cycleSort(1)
+			return m, nil
+
+		case key.Matches(msg, DefaultKeyMap.SortPrevious):
+			m.cycleSort(-1)
+			return m, nil
+
+		case key.Matches(msg, DefaultKeyMap.SortReverse):
+			m.reverseSort()
+			return m, nil
+
 		case key.Matches(msg, DefaultKeyMap.Left, DefaultKeyMap.Right):
 			var tableCmd tea.Cmd
 			m.table, tableCmd = m.table.Update(msg)
+			if m.selection.visual {
+				m.updateTableWithTracksAndLoading()
+			}
 			prefetchCmd := m.checkAndPrefetchIfNeeded()
 			if prefetchCmd != nil {
 				return m, tea.Batch(tableCmd, prefetchCmd)
@@ -129,6 +279,9 @@ func (m playlistViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, DefaultKeyMap.Up, DefaultKeyMap.Down):
 			var tableCmd tea.Cmd
 			m.table, tableCmd = m.table.Update(msg)
+			if m.selection.visual {
+				m.updateTableWithTracksAndLoading()
+			}
 
 			prefetchCmd := m.checkAndPrefetchIfNeeded()
 			if prefetchCmd != nil {
@@ -136,6 +289,27 @@ func (m playlistViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, tableCmd
 
+		case key.Matches(msg, DefaultKeyMap.VisualMode):
+			m.selection.toggleVisual(m.table.GetHighlightedRowIndex(), m.rowTracks)
+			m.updateTableWithTracksAndLoading()
+			return m, nil
+
+		case key.Matches(msg, DefaultKeyMap.ToggleSelect):
+			cursor := m.table.GetHighlightedRowIndex()
+			if cursor >= 0 && cursor < len(m.rowTracks) && m.rowTracks[cursor] >= 0 {
+				m.selection.toggle(m.rowTracks[cursor])
+				// Move on like marking in a file manager so consecutive rows can be marked quickly
+				m.table = m.table.WithHighlightedRow(cursor + 1)
+				m.updateTableWithTracksAndLoading()
+			}
+			return m, m.checkAndPrefetchIfNeeded()
+
+		case key.Matches(msg, DefaultKeyMap.Select) && m.selection.active():
+			tracks := m.selectedTracks()
+			m.clearSelection()
+			log.Printf("PlaylistView: Playing %d selected tracks", len(tracks))
+			return m, m.spotifyState.PlayTracks(m.ctx, trackIDs(tracks))
+
 		case key.Matches(msg, DefaultKeyMap.Select):
 			if track := m.getSelectedTrack(); track != nil {
 				log.Printf("PlaylistView: Selected track: %s", track.ID)
@@ -143,6 +317,56 @@ func (m playlistViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case key.Matches(msg, DefaultKeyMap.AddToPlaylist):
+			ids := trackIDs(m.selectedTracks())
+			if len(ids) == 0 {
+				return m, nil
+			}
+			dialog := NewAddToPlaylistDialog(m.ctx, m.spotifyState, ids)
+			return m, func() tea.Msg {
+				return ShowDialogWithContentMsg{Content: dialog}
+			}
+
+		case key.Matches(msg, DefaultKeyMap.RemoveFromPlaylist):
+			selectedID := m.spotifyState.GetSelectedID()
+			ids := trackIDs(m.selectedTracks())
+			if m.viewType != playlistView || selectedID == "" || len(ids) == 0 {
+				return m, nil
+			}
+			// Row indices shift once the tracks are gone
+			m.clearSelection()
+			return m, m.spotifyState.RemoveTracksFromPlaylist(m.ctx, selectedID, ids)
+
+		case key.Matches(msg, DefaultKeyMap.ToggleLike):
+			ids := trackIDs(m.selectedTracks())
+			if len(ids) == 0 {
+				return m, nil
+			}
+			return m, m.spotifyState.ToggleSavedTracks(m.ctx, ids)
+
+		case key.Matches(msg, DefaultKeyMap.Copy):
+			var items []copyItem
+			for _, track := range m.selectedTracks() {
+				// Local files have no link to share
+				if track.ID != "" {
+					items = append(items, trackCopyItem(track))
+				}
+			}
+			return m, showCopyDialog(items...)
+
+		case key.Matches(msg, DefaultKeyMap.Follow):
+			selectedID := m.spotifyState.GetSelectedID()
+			if selectedID == "" {
+				return m, nil
+			}
+			return m, toggleFollow(m.ctx, m.spotifyState, selectedID, m.viewType)
+
+		case key.Matches(msg, DefaultKeyMap.AddToQueue) && m.selection.active():
+			tracks := m.selectedTracks()
+			m.clearSelection()
+			m.spotifyState.Queue.EnqueueAll(tracks)
+			return m, tea.Batch(state.UpdateQueue(), m.setStatus(fmt.Sprintf("Added %d tracks to queue", len(tracks))))
+
 		case key.Matches(msg, DefaultKeyMap.AddToQueue):
 			if track := m.getSelectedTrack(); track != nil {
 				log.Printf("PlaylistView: Adding track to queue: %s", track.ID)
@@ -158,11 +382,64 @@ func (m playlistViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				)
 			}
 			return m, nil
+
+		case key.Matches(msg, DefaultKeyMap.QueueAll):
+			selectedID := m.spotifyState.GetSelectedID()
+			if selectedID == "" {
+				return m, nil
+			}
+			// Sorted or filtered rows are queued as shown
+			if m.sortColumn != "" || m.filter.active() {
+				m.queueAllSource = selectedID
+				return m, m.queueDisplayed()
+			}
+			return m, queueSource(m.ctx, m.spotifyState, selectedID, m.viewType)
+
+		case key.Matches(msg, DefaultKeyMap.PlayNext) && m.selection.active():
+			tracks := m.selectedTracks()
+			m.clearSelection()
+			// Insert back to front so the selection plays in table order
+			for i := len(tracks) - 1; i >= 0; i-- {
+				m.spotifyState.Queue.EnqueueNext(tracks[i])
+			}
+			return m, tea.Batch(state.UpdateQueue(), m.setStatus(fmt.Sprintf("Playing %d tracks next", len(tracks))))
+
+		case key.Matches(msg, DefaultKeyMap.PlayNext):
+			if track := m.getSelectedTrack(); track != nil {
+				log.Printf("PlaylistView: Playing track next: %s", track.ID)
+
+				m.queuedTracks[track.ID] = true
+				m.updateTableWithTracksAndLoading()
+				m.spotifyState.Queue.EnqueueNext(*track)
+				return m, tea.Batch(
+					state.UpdateQueue(),
+					tea.Tick(2*time.Second, func(_ time.Time) tea.Msg {
+						return clearQueuedHighlightMsg{TrackID: track.ID}
+					}),
+				)
+			}
+			return m, nil
 		}
 	case clearQueuedHighlightMsg:
 		delete(m.queuedTracks, msg.TrackID)
 		m.updateTableWithTracksAndLoading()
 		return m, nil
+
+	case clearStatusMsg:
+		m.status = ""
+		return m, nil
+
+	case state.SavedTracksUpdatedMsg:
+		if msg.Saved {
+			return m, m.setStatus(fmt.Sprintf("Saved %d tracks to Liked Songs", msg.Count))
+		}
+		return m, m.setStatus(fmt.Sprintf("Removed %d tracks from Liked Songs", msg.Count))
+
+	case state.PlaylistTracksChangedMsg:
+		if msg.Removed > 0 {
+			return m, m.setStatus(fmt.Sprintf("Removed %d tracks", msg.Removed))
+		}
+		return m, m.setStatus(fmt.Sprintf("Added %d tracks to playlist", msg.Added))
 	}
 
 	// Forward all other messages to the table, but check for page changes
@@ -227,6 +504,32 @@ func (m playlistViewModel) View() string {
 				break
 			}
 		}
+
+		for _, timeRange := range state.TopTimeRanges {
+			for _, artist := range m.spotifyState.GetTopArtists(timeRange) {
+				if artist.ID == spotify.ID(selectedId) {
+					name = artist.Name
+					break
+				}
+			}
+		}
+
+		for _, artist := range m.spotifyState.GetFollowedArtists() {
+			if artist.ID == spotify.ID(selectedId) {
+				name = artist.Name
+				break
+			}
+		}
+		for _, album := range m.spotifyState.GetSavedAlbums() {
+			if album.ID == spotify.ID(selectedId) {
+				name = album.Name
+				break
+			}
+		}
+	}
+
+	if isInLibrary(m.spotifyState, selectedId, m.viewType) {
+		name = libraryMarker + name
 	}
 
 	styledName := lipgloss.NewStyle().
@@ -239,9 +542,34 @@ func (m playlistViewModel) View() string {
 		Padding(0, 1).
 		Render(fmt.Sprintf("| Page %d/%d", currentPage, maxPage))
 
-	m.table = m.table.WithStaticFooter(
-		styledName + styledPage,
-	)
+	footer := styledName + styledPage
+	if m.filter.active() {
+		footer = m.renderFilterFooter() + styledPage
+	}
+	if sort := m.sortDescription(); sort != "" {
+		footer += lipgloss.NewStyle().
+			Foreground(TextColor).
+			Padding(0, 1).
+			Render(sort)
+	}
+	if m.selection.active() {
+		selected := fmt.Sprintf("| %d selected", len(m.selection.indices(m.table.GetHighlightedRowIndex(), m.rowTracks)))
+		if m.selection.visual {
+			selected = "| VISUAL " + selected[2:]
+		}
+		footer += lipgloss.NewStyle().
+			Foreground(PrimaryColor).
+			Padding(0, 1).
+			Render(selected)
+	}
+	if m.status != "" {
+		footer += lipgloss.NewStyle().
+			Foreground(PrimaryColor).
+			Padding(0, 1).
+			Render("| " + m.status)
+	}
+
+	m.table = m.table.WithStaticFooter(footer)
 
 	return m.table.View()
 }
@@ -257,43 +585,89 @@ func (m *playlistViewModel) updateTableWithTracksAndLoading() {
 		return
 	}
 
+	if m.selection.sourceID != selectedID {
+		m.selection.reset(selectedID)
+		m.filter = newTrackFilter()
+	}
+
 	loadedTracks := m.spotifyState.GetTracks()
 	totalTracks := m.spotifyState.GetTotalTracks(selectedID)
 	if totalTracks == 0 {
+		m.rowTracks = nil
 		m.table = m.table.WithRows([]table.Row{})
 		return
 	}
 
-	rows := make([]table.Row, totalTracks)
 	playerState := m.spotifyState.GetPlayerState()
+	cursor := m.table.GetHighlightedRowIndex()
 
-	// Add loaded tracks
+	inputs := make([]trackColumnInput, len(loadedTracks))
 	for i, track := range loadedTracks {
-		rows[i] = m.createTrackRow(track, i, &playerState)
+		inputs[i] = trackColumnInput{
+			track:   track,
+			details: m.spotifyState.GetTrackDetails(selectedID, track.ID),
+			index:   i,
+		}
 	}
 
-	// Add loading placeholders for remaining tracks
-	for i := len(loadedTracks); i < totalTracks; i++ {
-		rows[i] = m.createLoadingRow(i)
+	// Only loaded tracks can match, the footer offers to load the rest
+	query := m.filter.query()
+	var matches []trackMatch
+	if query != "" {
+		matches = matchTracks(query, loadedTracks)
+	} else {
+		matches = make([]trackMatch, len(loadedTracks))
+		for i := range loadedTracks {
+			matches[i] = trackMatch{index: i}
+		}
 	}
 
-	m.table = m.table.WithRows(rows)
-}
+	// A stable sort keeps the match ranking or source order between equal values
+	if column, ok := trackColumns[m.sortColumn]; ok {
+		slices.SortStableFunc(matches, func(a, b trackMatch) int {
+			if m.sortDesc {
+				return column.compare(inputs[b.index], inputs[a.index])
+			}
+			return column.compare(inputs[a.index], inputs[b.index])
+		})
+	}
 
-func (m *playlistViewModel) createTrackRow(track spotify.SimpleTrack, index int, playerState *spotify.PlayerState) table.Row {
-	artistName := "Unknown Artist"
-	if len(track.Artists) > 0 {
-		artistName = track.Artists[0].Name
+	rowCount := len(matches)
+	if query == "" {
+		rowCount = totalTracks
+	}
+	rows := make([]table.Row, rowCount)
+	m.rowTracks = make([]int, rowCount)
+
+	// Add loaded tracks in display order
+	for row, match := range matches {
+		var offsets *trackMatch
+		if query != "" {
+			offsets = &match
+		}
+		m.rowTracks[row] = match.index
+		rows[row] = m.createTrackRow(inputs[match.index], &playerState,
+			m.selection.contains(match.index, row, cursor), offsets)
 	}
 
-	albumName := "Unknown Album"
-	if track.Album.Name != "" {
-		albumName = track.Album.Name
+	// Add loading placeholders for remaining tracks, they are sorted in once loaded
+	for i := len(matches); i < rowCount; i++ {
+		m.rowTracks[i] = -1
+		rows[i] = m.createLoadingRow(i)
 	}
 
-	duration := formatTrackDuration(int(track.Duration))
+	m.table = m.table.WithRows(rows)
+}
 
+func (m *playlistViewModel) createTrackRow(in trackColumnInput, playerState *spotify.PlayerState, selected bool, match *trackMatch) table.Row {
+	track := in.track
 	title := track.Name
+	if match != nil {
+		title = highlightMatches(track.Name, match.title)
+	}
+	if selected {
+		title = lipgloss.NewStyle().Foreground(PrimaryColor).Render("● ") + title
+	}
 	if m.queuedTracks[track.ID] {
 		queuedStyle := lipgloss.NewStyle().
 			Foreground(PrimaryColor).
@@ -301,19 +675,37 @@ func (m *playlistViewModel) createTrackRow(track spotify.SimpleTrack, index int,
 		title = title + " " + queuedStyle.Render("(Added to queue)")
 	}
 
-	var indexDisplay string
+	data := table.RowData{}
+	for _, name := range m.currentColumns() {
+		if column, ok := trackColumns[name]; ok {
+			data[column.key] = column.value(in)
+		}
+	}
+	data["title"] = title
+
 	if playerState != nil && playerState.Item != nil && string(track.ID) == string(playerState.Item.ID) {
-		indexDisplay = m.spinner.View()
-	} else {
-		indexDisplay = fmt.Sprintf("%d", index+1)
+		data["#"] = m.spinner.View()
 	}
 
+	if match != nil {
+		if len(track.Artists) > 0 {
+			data["artist"] = highlightMatches(track.Artists[0].Name, match.artist)
+		}
+		if track.Album.Name != "" {
+			data["album"] = highlightMatches(track.Album.Name, match.album)
+		}
+	}
+	return table.NewRow(data)
+}
+
+// newTrackRow builds a row for the default track table layout
+func newTrackRow(track spotify.SimpleTrack, indexDisplay, title string) table.Row {
 	return table.NewRow(table.RowData{
 		"#":        indexDisplay,
 		"title":    title,
-		"artist":   artistName,
-		"album":    albumName,
-		"duration": duration,
+		"artist":   firstArtistName(track),
+		"album":    trackColumns["album"].value(trackColumnInput{track: track}),
+		"duration": formatTrackDuration(int(track.Duration)),
 	})
 }
 
@@ -322,23 +714,28 @@ func (m *playlistViewModel) createLoadingRow(index int) table.Row {
 		Foreground(TextColor).
 		Italic(true)
 
-	return table.NewRow(table.RowData{
-		"#":        fmt.Sprintf("%d", index+1),
-		"title":    loadingStyle.Render("Loading"),
-		"artist":   "--:--",
-		"album":    "--:--",
-		"duration": "--:--",
-	})
+	data := table.RowData{}
+	for _, name := range m.currentColumns() {
+		if column, ok := trackColumns[name]; ok {
+			data[column.key] = "--:--"
+		}
+	}
+	data["#"] = fmt.Sprintf("%d", index+1)
+	data["title"] = loadingStyle.Render("Loading")
+	return table.NewRow(data)
+}
+
+// createTrackTable creates a track table with the given column layout
+func createTrackTable(columns []string) table.Model {
+	if len(columns) == 0 {
+		columns = defaultTrackColumns
+	}
+	return newStyledTable(tableColumns(columns, "", false))
 }
 
-func createPlaylistTable() table.Model {
-	return table.New([]table.Column{
-		table.NewColumn("#", "#", 4).WithStyle(lipgloss.NewStyle().Align(lipgloss.Center)),
-		table.NewFlexColumn("title", "Title", 1),   // Flex column with weight 3 4
-		table.NewFlexColumn("artist", "Artist", 1), // Flex column with weight 2 4
-		table.NewFlexColumn("album", "Album", 1),   // Flex column with weight 2 3
-		table.NewColumn("duration", "Duration", 8).WithStyle(lipgloss.NewStyle().Align(lipgloss.Center)),
-	}).WithRows([]table.Row{}).HeaderStyle(
+// newStyledTable creates an empty table with the look shared by every table view
+func newStyledTable(columns []table.Column) table.Model {
+	return table.New(columns).WithRows([]table.Row{}).HeaderStyle(
 		lipgloss.NewStyle().
 			Bold(true).
 			BorderForeground(BorderColor).
@@ -356,9 +753,10 @@ func createPlaylistTable() table.Model {
 	)
 }
 
-// GetNextTrack returns the ID of the next track to play when autoplay is triggered
+// GetNextTrack returns the ID of the next track to play when autoplay is triggered.
+// It follows the rows as displayed, so sorting and filtering decide the play order.
 func (m *playlistViewModel) getNextTrack() spotify.ID {
-	tracks := m.spotifyState.GetTracks()
+	tracks := m.displayedTracks()
 	if len(tracks) == 0 {
 		log.Println("No tracks in playlist to autoplay")
 		return ""
@@ -384,7 +782,7 @@ func (m *playlistViewModel) getNextTrack() spotify.ID {
 				return ""
 			}
 			// Return the next track
-			log.Printf("Found current track at index %d, returning next track", i)
+			log.Printf("Found current track at row %d, returning next track", i)
 			return spotify.ID(tracks[i+1].ID)
 		}
 	}
@@ -394,25 +792,176 @@ func (m *playlistViewModel) getNextTrack() spotify.ID {
 	return spotify.ID(tracks[0].ID)
 }
 
-func (m *playlistViewModel) getSelectedTrack() *spotify.SimpleTrack {
-	selected := m.table.HighlightedRow()
-	if selected.Data == nil {
-		return nil
+// displayedTracks returns the loaded tracks in the order the table shows them
+func (m *playlistViewModel) displayedTracks() []spotify.SimpleTrack {
+	tracks := m.spotifyState.GetTracks()
+	displayed := make([]spotify.SimpleTrack, 0, len(m.rowTracks))
+	for _, i := range m.rowTracks {
+		if i >= 0 && i < len(tracks) {
+			displayed = append(displayed, tracks[i])
+		}
 	}
+	return displayed
+}
 
-	numStr, ok := selected.Data["#"].(string)
-	if !ok {
+// selectedTracks returns the marked tracks in table order, or the highlighted track when nothing is marked
+func (m *playlistViewModel) selectedTracks() []spotify.SimpleTrack {
+	if !m.selection.active() {
+		if track := m.getSelectedTrack(); track != nil {
+			return []spotify.SimpleTrack{*track}
+		}
 		return nil
 	}
 
 	tracks := m.spotifyState.GetTracks()
-	idx, err := strconv.Atoi(numStr)
-	if err != nil || idx <= 0 || idx > len(tracks) {
+	selected := make([]spotify.SimpleTrack, 0, len(m.selection.marked))
+	for _, i := range m.selection.indices(m.table.GetHighlightedRowIndex(), m.rowTracks) {
+		// Loading rows can be inside a visual range but have no track yet
+		if i < len(tracks) {
+			selected = append(selected, tracks[i])
+		}
+	}
+	return selected
+}
+
+// updateFilterInput sends a key to the filter prompt and refilters the table
+func (m playlistViewModel) updateFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Return):
+		m.clearFilter()
+		return m, nil
+
+	case key.Matches(msg, DefaultKeyMap.Select):
+		// Keep the filter applied and hand the keys back to the table
+		m.filter.typing = false
+		m.filter.input.Blur()
+		return m, nil
+
+	case key.Matches(msg, DefaultKeyMap.FilterLoadAll):
+		return m, m.loadAllForFilter()
+	}
+
+	var cmd tea.Cmd
+	previous := m.filter.query()
+	m.filter.input, cmd = m.filter.input.Update(msg)
+	if m.filter.query() != previous {
+		m.table = m.table.WithHighlightedRow(0)
+		m.updateTableWithTracksAndLoading()
+	}
+	return m, cmd
+}
+
+// clearFilter closes the filter prompt and shows every track again
+func (m *playlistViewModel) clearFilter() {
+	// Marks are kept but the visual range would point at the wrong rows
+	if m.selection.visual {
+		m.selection.toggleVisual(m.table.GetHighlightedRowIndex(), m.rowTracks)
+	}
+	loadingAll := m.filter.loadingAll
+	m.filter = newTrackFilter()
+	m.filter.loadingAll = loadingAll
+	m.table = m.table.WithHighlightedRow(0)
+	m.updateTableWithTracksAndLoading()
+}
+
+// loadAllForFilter fetches the remaining pages one after another so the filter searches every track
+func (m *playlistViewModel) loadAllForFilter() tea.Cmd {
+	cmd := m.fetchRemainingPage()
+	m.filter.loadingAll = cmd != nil
+	return cmd
+}
+
+// queueDisplayed adds the rows to the queue in the order the table shows them, loading the remaining
+// pages first so they are sorted and filtered with the rest
+func (m *playlistViewModel) queueDisplayed() tea.Cmd {
+	if m.queueAllSource != m.spotifyState.GetSelectedID() {
+		// Another source was opened while the pages loaded
+		m.queueAllSource = ""
+		return nil
+	}
+	if cmd := m.fetchRemainingPage(); cmd != nil {
+		loaded, total := len(m.spotifyState.GetTracks()), m.spotifyState.GetTotalTracks(m.queueAllSource)
+		return tea.Batch(cmd, m.setStatus(fmt.Sprintf("Loading %d/%d tracks to queue", loaded, total)))
+	}
+	m.queueAllSource = ""
+
+	tracks := m.displayedTracks()
+	m.spotifyState.Queue.EnqueueAll(tracks)
+	return tea.Batch(state.UpdateQueue(), m.setStatus(fmt.Sprintf("Added %d tracks to queue", len(tracks))))
+}
+
+// fetchRemainingPage loads the next unloaded page of the open source, or returns nil when everything is loaded
+func (m *playlistViewModel) fetchRemainingPage() tea.Cmd {
+	selectedID := m.spotifyState.GetSelectedID()
+	if selectedID == "" || !m.spotifyState.HasMoreTracks(selectedID) {
+		return nil
+	}
+	cacheEntry, exists := m.spotifyState.GetCachedTracks(selectedID)
+	if !exists || cacheEntry.NextPage == nil {
+		return nil
+	}
+	return m.spotifyState.FetchNextTracksPage(m.ctx, selectedID, cacheEntry.NextPage)
+}
+
+// renderFilterFooter shows the prompt, the match count and how much of the listing was searched
+func (m playlistViewModel) renderFilterFooter() string {
+	prompt := m.filter.input.View()
+	if !m.filter.typing {
+		prompt = lipgloss.NewStyle().Foreground(PrimaryColor).Render("Filter › ") + m.filter.query()
+	}
+
+	infoStyle := lipgloss.NewStyle().
+		Foreground(TextColor).
+		Padding(0, 1)
+
+	info := fmt.Sprintf("| %d matches", len(m.rowTracks))
+	if m.filter.query() == "" {
+		info = ""
+	}
+
+	selectedID := m.spotifyState.GetSelectedID()
+	loaded, total := len(m.spotifyState.GetTracks()), m.spotifyState.GetTotalTracks(selectedID)
+	switch {
+	case m.filter.loadingAll:
+		info += fmt.Sprintf(" | loading %d/%d", loaded, total)
+	case loaded < total:
+		info += fmt.Sprintf(" | searched %d/%d, %s: load all", loaded, total, DefaultKeyMap.FilterLoadAll.Help().Key)
+	}
+
+	return lipgloss.NewStyle().Padding(0, 1).Render(prompt) + infoStyle.Render(info)
+}
+
+func (m *playlistViewModel) hasSelection() bool {
+	return m.selection.active()
+}
+
+func (m *playlistViewModel) clearSelection() {
+	m.selection.reset(m.selection.sourceID)
+	m.updateTableWithTracksAndLoading()
+}
+
+// setStatus shows a short message in the footer for a few seconds
+func (m *playlistViewModel) setStatus(status string) tea.Cmd {
+	m.status = status
+	return tea.Tick(3*time.Second, func(_ time.Time) tea.Msg {
+		return clearStatusMsg{}
+	})
+}
+
+func (m *playlistViewModel) getSelectedTrack() *spotify.SimpleTrack {
+	cursor := m.table.GetHighlightedRowIndex()
+	if cursor < 0 || cursor >= len(m.rowTracks) || m.rowTracks[cursor] < 0 {
 		// User selected a loading row, return nil
 		return nil
 	}
 
-	return &tracks[idx-1]
+	tracks := m.spotifyState.GetTracks()
+	idx := m.rowTracks[cursor]
+	if idx >= len(tracks) {
+		return nil
+	}
+
+	return &tracks[idx]
 }
 
 // 2 for header, 4 for footer
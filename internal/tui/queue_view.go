@@ -161,23 +161,66 @@ func (m queueModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case state.QueueUpdatedMsg:
-		m.list.SetItems(m.convertQueueToItems())
+		items := m.convertQueueToItems()
+		m.list.SetItems(items)
+		if m.list.Index() >= len(items) && len(items) > 0 {
+			m.list.Select(len(items) - 1)
+		}
 
 	case tea.KeyMsg:
+		index := m.list.Index()
+
 		switch {
-		// Delete key to clear the specific item
-		case key.Matches(msg, DefaultKeyMap.Copy):
-			_, err := m.spotifyState.Queue.PopAt(m.list.Index())
-			if err != nil {
+		case key.Matches(msg, DefaultKeyMap.QueueRemove):
+			if _, err := m.spotifyState.Queue.PopAt(index); err != nil {
 				log.Println("Error popping track from queue:", err)
 			}
+			return m, state.UpdateQueue()
+
+		case key.Matches(msg, DefaultKeyMap.QueueMoveUp):
+			return m, m.moveSelected(index, index-1)
+
+		case key.Matches(msg, DefaultKeyMap.QueueMoveDown):
+			return m, m.moveSelected(index, index+1)
+
+		case key.Matches(msg, DefaultKeyMap.PlayNext):
+			return m, m.moveSelected(index, 0)
+
+		case key.Matches(msg, DefaultKeyMap.Copy):
+			tracks := m.spotifyState.Queue.List()
+			if index < 0 || index >= len(tracks) || tracks[index].ID == "" {
+				return m, nil
+			}
+			return m, showCopyDialog(trackCopyItem(tracks[index]))
 
+		case key.Matches(msg, DefaultKeyMap.QueueClear):
+			m.spotifyState.Queue.Clear()
+			return m, state.UpdateQueue()
+
+		case key.Matches(msg, DefaultKeyMap.QueueShuffle):
+			m.spotifyState.Queue.Shuffle()
+			m.list.Select(0)
+			return m, state.UpdateQueue()
+
+		case key.Matches(msg, DefaultKeyMap.QueueDedupe):
+			removed := m.spotifyState.Queue.RemoveDuplicates()
+			log.Printf("Queue: Removed %d duplicate tracks", removed)
+			return m, state.UpdateQueue()
+
+		case key.Matches(msg, DefaultKeyMap.QueueRemoveArtist):
+			tracks := m.spotifyState.Queue.List()
+			if index < 0 || index >= len(tracks) || len(tracks[index].Artists) == 0 {
+				return m, nil
+			}
+			artist := tracks[index].Artists[0]
+			removed := m.spotifyState.Queue.RemoveByArtist(artist.ID)
+			log.Printf("Queue: Removed %d tracks by %s", removed, artist.Name)
 			return m, state.UpdateQueue()
 
 		// Handle select key to play the selected track
 		case key.Matches(msg, DefaultKeyMap.Select):
 
-			track, err := m.spotifyState.Queue.PopAt(m.list.Index())
+			track, err := m.spotifyState.Queue.PopAt(index)
 			if err != nil {
 				log.Println("Error popping track from queue:", err)
 			}
@@ -236,6 +279,16 @@ func (m queueModel) View() string {
 		Render(m.list.View())
 }
 
+// moveSelected moves a queued track and keeps the cursor on it
+func (m *queueModel) moveSelected(from, to int) tea.Cmd {
+	if err := m.spotifyState.Queue.Move(from, to); err != nil {
+		return nil
+	}
+	m.list.SetItems(m.convertQueueToItems())
+	m.list.Select(to)
+	return state.UpdateQueue()
+}
+
 func (m queueModel) convertQueueToItems() []list.Item {
 	queueItems := m.spotifyState.Queue.List()
 
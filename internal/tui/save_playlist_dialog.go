@@ -0,0 +1,302 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
+)
+
+// playlistSource is a set of tracks that can be saved as a new playlist
+type playlistSource struct {
+	label    string
+	trackIDs []spotify.ID
+}
+
+type savePlaylistField int
+
+const (
+	fieldSource savePlaylistField = iota
+	fieldName
+	fieldDescription
+	fieldPublic
+	savePlaylistFieldCount
+)
+
+const savePlaylistDialogWidth = 50
+
+type savePlaylistDialogContent struct {
+	ctx          context.Context
+	width        int
+	height       int
+	spotifyState *state.SpotifyState
+
+	sources     []playlistSource
+	sourceIndex int
+
+	nameInput        textinput.Model
+	descriptionInput textinput.Model
+	public           bool
+	field            savePlaylistField
+}
+
+func NewSavePlaylistDialog(ctx context.Context, spotifyState *state.SpotifyState, sources []playlistSource) DialogContent {
+	nameInput := newDialogTextInput("Playlist name", 100)
+	nameInput.SetValue("Termify " + time.Now().Format("2006-01-02 15:04"))
+
+	descriptionInput := newDialogTextInput("Description (optional)", 300)
+
+	content := &savePlaylistDialogContent{
+		ctx:              ctx,
+		spotifyState:     spotifyState,
+		sources:          sources,
+		nameInput:        nameInput,
+		descriptionInput: descriptionInput,
+	}
+	// Start on the name since the source is usually already the one the user wants
+	content.setField(fieldName)
+	return content
+}
+
+func newDialogTextInput(placeholder string, charLimit int) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.CharLimit = charLimit
+	ti.Width = savePlaylistDialogWidth - 6
+	ti.Prompt = "› "
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(PrimaryColor)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(WhiteTextColor)
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(TextColor)
+	return ti
+}
+
+func (m *savePlaylistDialogContent) Init() tea.Cmd {
+	return nil
+}
+
+func (m *savePlaylistDialogContent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return m, nil
+}
+
+func (m *savePlaylistDialogContent) View() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(TextColor).
+		Width(savePlaylistDialogWidth)
+
+	focusedLabelStyle := labelStyle.
+		Foreground(PrimaryColor).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(WhiteTextColor).
+		Width(savePlaylistDialogWidth).
+		PaddingLeft(2)
+
+	renderLabel := func(field savePlaylistField, label string) string {
+		if m.field == field {
+			return focusedLabelStyle.Render("→ " + label)
+		}
+		return labelStyle.Render("  " + label)
+	}
+
+	source := "Nothing to save"
+	if len(m.sources) > 0 {
+		source = m.sources[m.sourceIndex].label
+		if len(m.sources) > 1 {
+			source = fmt.Sprintf("‹ %s ›", source)
+		}
+	}
+
+	visibility := "Private"
+	if m.public {
+		visibility = "Public"
+	}
+
+	rows := []string{
+		renderLabel(fieldSource, "Tracks"),
+		valueStyle.Render(source),
+		"",
+		renderLabel(fieldName, "Name"),
+		valueStyle.Render(m.nameInput.View()),
+		"",
+		renderLabel(fieldDescription, "Description"),
+		valueStyle.Render(m.descriptionInput.View()),
+		"",
+		renderLabel(fieldPublic, "Visibility"),
+		valueStyle.Render(visibility),
+	}
+
+	navHint := lipgloss.NewStyle().
+		Foreground(TextColor).
+		Italic(true).
+		Width(savePlaylistDialogWidth).
+		Align(lipgloss.Center).
+		MarginTop(1).
+		Render("↑↓ to move between fields, space to change")
+
+	return lipgloss.JoinVertical(lipgloss.Left, append(rows, navHint)...)
+}
+
+func (m *savePlaylistDialogContent) GetTitle() string {
+	return "Save as Playlist"
+}
+
+func (m *savePlaylistDialogContent) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m *savePlaylistDialogContent) HandleDialogKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch {
+	case key.Matches(msg, key.NewBinding(key.WithKeys("up"))):
+		m.setField((m.field - 1 + savePlaylistFieldCount) % savePlaylistFieldCount)
+		return true, nil
+	case key.Matches(msg, key.NewBinding(key.WithKeys("down"))):
+		m.setField((m.field + 1) % savePlaylistFieldCount)
+		return true, nil
+	}
+
+	var cmd tea.Cmd
+	switch m.field {
+	case fieldSource:
+		if key.Matches(msg, key.NewBinding(key.WithKeys(" "))) && len(m.sources) > 0 {
+			m.sourceIndex = (m.sourceIndex + 1) % len(m.sources)
+			return true, nil
+		}
+	case fieldPublic:
+		if key.Matches(msg, key.NewBinding(key.WithKeys(" "))) {
+			m.public = !m.public
+			return true, nil
+		}
+	case fieldName, fieldDescription:
+		// Leave enter, tab and esc to the dialog so the actions keep working while typing
+		if key.Matches(msg, key.NewBinding(key.WithKeys("enter", "tab", "shift+tab", "esc"))) {
+			return false, nil
+		}
+		if m.field == fieldName {
+			m.nameInput, cmd = m.nameInput.Update(msg)
+		} else {
+			m.descriptionInput, cmd = m.descriptionInput.Update(msg)
+		}
+		return true, cmd
+	}
+	return false, nil
+}
+
+func (m *savePlaylistDialogContent) GetActions() []DialogAction {
+	createCmd := func() tea.Msg {
+		if len(m.sources) == 0 || len(m.sources[m.sourceIndex].trackIDs) == 0 {
+			return state.ErrorMsg{
+				Title:   "Nothing to Save",
+				Message: "There are no tracks to save as a playlist.",
+			}
+		}
+
+		name := strings.TrimSpace(m.nameInput.Value())
+		if name == "" {
+			name = m.nameInput.Placeholder
+		}
+
+		source := m.sources[m.sourceIndex]
+		log.Printf("SavePlaylistDialog: Saving %s as %q", source.label, name)
+		return m.spotifyState.CreatePlaylist(
+			m.ctx,
+			name,
+			strings.TrimSpace(m.descriptionInput.Value()),
+			m.public,
+			source.trackIDs,
+		)()
+	}
+
+	return []DialogAction{
+		{
+			Label: "Create",
+			Key:   key.NewBinding(key.WithKeys("enter")),
+			Cmd:   createCmd,
+		},
+		{
+			Label: "Cancel",
+			Key:   key.NewBinding(key.WithKeys("esc")),
+			Cmd: func() tea.Msg {
+				return DialogMsg{Accepted: false}
+			},
+		},
+	}
+}
+
+func (m *savePlaylistDialogContent) setField(field savePlaylistField) {
+	m.field = field
+	m.nameInput.Blur()
+	m.descriptionInput.Blur()
+
+	switch field {
+	case fieldName:
+		m.nameInput.Focus()
+	case fieldDescription:
+		m.descriptionInput.Focus()
+	}
+}
+
+// playlistSources collects the track sets that can currently be saved as a playlist
+func (m applicationModel) playlistSources() []playlistSource {
+	var sources []playlistSource
+
+	if queued := m.spotifyState.Queue.List(); len(queued) > 0 {
+		sources = append(sources, newPlaylistSource("Queue", queued))
+	}
+
+	var viewLabel string
+	var viewTracks []spotify.SimpleTrack
+	switch {
+	case m.focusedModel == FocusHistoryView:
+		viewLabel = "Recently played"
+		for _, item := range m.spotifyState.GetRecentlyPlayed() {
+			viewTracks = append(viewTracks, item.Track)
+		}
+	case m.isTopViewFocus():
+		viewLabel = "Top tracks • " + timeRangeLabels[m.topView.timeRange]
+		for _, track := range m.spotifyState.GetTopTracks(m.topView.timeRange) {
+			viewTracks = append(viewTracks, track.SimpleTrack)
+		}
+	case m.isSearchViewFocus():
+		viewLabel = "Search results"
+		for _, track := range m.spotifyState.GetSearchResultTracks() {
+			viewTracks = append(viewTracks, track.SimpleTrack)
+		}
+	default:
+		viewLabel = "Current view"
+		viewTracks = m.spotifyState.GetTracks()
+	}
+	if len(viewTracks) > 0 {
+		viewSource := newPlaylistSource(viewLabel, viewTracks)
+		// Prefer the view unless the queue is what the user is looking at
+		if m.focusedModel == FocusQueue {
+			sources = append(sources, viewSource)
+		} else {
+			sources = append([]playlistSource{viewSource}, sources...)
+		}
+	}
+
+	// Marked rows are the most specific choice so they come first
+	if m.focusedModel == FocusPlaylistView && m.playlistView.hasSelection() {
+		sources = append([]playlistSource{newPlaylistSource("Selection", m.playlistView.selectedTracks())}, sources...)
+	}
+
+	return sources
+}
+
+func newPlaylistSource(label string, tracks []spotify.SimpleTrack) playlistSource {
+	ids := trackIDs(tracks)
+	return playlistSource{
+		label:    fmt.Sprintf("%s (%d tracks)", label, len(ids)),
+		trackIDs: ids,
+	}
+}
@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
+)
+
+// clearSearchHistoryMsg is sent when clearing the search history is confirmed
+type clearSearchHistoryMsg struct{}
+
+// loadHistory reads the saved search history, starting empty if it can't be read
+func (m *searchbarModel) loadHistory() {
+	if m.historyPath == "" {
+		return
+	}
+	history, err := state.LoadSearchHistory(m.historyPath)
+	if err != nil {
+		log.Printf("Searchbar: Failed to load search history: %v", err)
+		return
+	}
+	m.history = history
+	log.Printf("Searchbar: Loaded %d past searches", len(history.Queries))
+}
+
+// saveHistory writes a snapshot of the history in the background
+func (m searchbarModel) saveHistory() tea.Cmd {
+	if m.historyPath == "" {
+		return nil
+	}
+	path, history := m.historyPath, m.history
+	return func() tea.Msg {
+		if err := state.SaveSearchHistory(path, history); err != nil {
+			log.Printf("Searchbar: Failed to save search history: %v", err)
+		}
+		return nil
+	}
+}
+
+// commitQuery records the current query in the history once the user moves on to its results
+func (m *searchbarModel) commitQuery() tea.Cmd {
+	query := strings.TrimSpace(m.textInput.Value())
+	if query == "" {
+		return nil
+	}
+	m.history.Add(query)
+	m.recall = -1
+	m.textInput.SetSuggestions(m.suggestions())
+	return m.saveHistory()
+}
+
+// recallHistory steps through the history, older for a positive step and newer for a negative one.
+// Stepping past the newest entry brings back what was typed before. It reports whether the input changed.
+func (m *searchbarModel) recallHistory(step int) bool {
+	next := m.recall + step
+	if next < -1 || next >= len(m.history.Queries) {
+		return false
+	}
+	if m.recall == -1 {
+		m.draft = m.textInput.Value()
+	}
+	m.recall = next
+
+	if m.recall == -1 {
+		m.textInput.SetValue(m.draft)
+	} else {
+		m.textInput.SetValue(m.history.Queries[m.recall])
+	}
+	m.textInput.CursorEnd()
+	return true
+}
+
+// acceptSuggestion completes the input with the highlighted suggestion when the cursor is at the end
+func (m *searchbarModel) acceptSuggestion() bool {
+	value := m.textInput.Value()
+	suggestion := m.textInput.CurrentSuggestion()
+	if m.textInput.Position() < len([]rune(value)) || len(suggestion) <= len(value) {
+		return false
+	}
+	m.textInput.SetValue(suggestion)
+	m.textInput.CursorEnd()
+	m.recall = -1
+	return true
+}
+
+// removeHistoryEntry forgets the recalled query, or the suggested one when it comes from the history
+func (m *searchbarModel) removeHistoryEntry() tea.Cmd {
+	var query string
+	switch {
+	case m.recall >= 0:
+		query = m.history.Queries[m.recall]
+	case m.textInput.Value() != "":
+		query = m.textInput.CurrentSuggestion()
+	}
+	if query == "" || !m.history.Remove(query) {
+		return nil
+	}
+	log.Printf("Searchbar: Removed %q from the search history", query)
+
+	// Show the entry that took its place, or what was typed when it was the oldest
+	if m.recall >= len(m.history.Queries) {
+		m.recall = -1
+		m.textInput.SetValue(m.draft)
+	} else if m.recall >= 0 {
+		m.textInput.SetValue(m.history.Queries[m.recall])
+	}
+	m.textInput.CursorEnd()
+	m.textInput.SetSuggestions(m.suggestions())
+	return m.saveHistory()
+}
+
+// clearHistory forgets every past query
+func (m *searchbarModel) clearHistory() tea.Cmd {
+	m.history.Clear()
+	m.recall = -1
+	m.textInput.SetSuggestions(m.suggestions())
+	log.Println("Searchbar: Cleared the search history")
+	return m.saveHistory()
+}
+
+// suggestions lists the past queries followed by the names of library playlists and cached artists
+func (m searchbarModel) suggestions() []string {
+	seen := make(map[string]bool)
+	var suggestions []string
+	add := func(name string) {
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		suggestions = append(suggestions, name)
+	}
+
+	for _, query := range m.history.Queries {
+		add(query)
+	}
+	for _, playlist := range m.spotifyState.GetPlaylists() {
+		add(playlist.Name)
+	}
+	for _, artist := range m.spotifyState.GetFollowedArtists() {
+		add(artist.Name)
+	}
+	for _, timeRange := range []spotify.Range{spotify.ShortTermRange, spotify.MediumTermRange, spotify.LongTermRange} {
+		for _, artist := range m.spotifyState.GetTopArtists(timeRange) {
+			add(artist.Name)
+		}
+	}
+	return suggestions
+}
+
+// clearSearchHistoryDialogContent asks before forgetting every past search
+type clearSearchHistoryDialogContent struct {
+	width   int
+	height  int
+	entries int
+}
+
+func NewClearSearchHistoryDialog(entries int) DialogContent {
+	return &clearSearchHistoryDialogContent{entries: entries}
+}
+
+func (m *clearSearchHistoryDialogContent) Init() tea.Cmd {
+	return nil
+}
+
+func (m *clearSearchHistoryDialogContent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return m, nil
+}
+
+func (m *clearSearchHistoryDialogContent) View() string {
+	return lipgloss.NewStyle().
+		Foreground(TextColor).
+		Width(40).
+		Align(lipgloss.Center).
+		Render(fmt.Sprintf("Forget %d past searches?", m.entries))
+}
+
+func (m *clearSearchHistoryDialogContent) GetTitle() string {
+	return "Clear Search History"
+}
+
+func (m *clearSearchHistoryDialogContent) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m *clearSearchHistoryDialogContent) HandleDialogKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	return false, nil
+}
+
+func (m *clearSearchHistoryDialogContent) GetActions() []DialogAction {
+	return []DialogAction{
+		{
+			Label: "Clear",
+			Key:   key.NewBinding(key.WithKeys("enter")),
+			Cmd: func() tea.Msg {
+				return clearSearchHistoryMsg{}
+			},
+		},
+		{
+			Label: "Cancel",
+			Key:   key.NewBinding(key.WithKeys("esc")),
+			Cmd: func() tea.Msg {
+				return DialogMsg{Accepted: false}
+			},
+		},
+	}
+}
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/evertras/bubble-table/table"
+)
+
+// searchCategory maps a search list focus to the result category it shows
+func searchCategory(focus FocusedModel) (state.SearchCategory, bool) {
+	switch focus {
+	case FocusSearchTracksView:
+		return state.SearchTracks, true
+	case FocusSearchArtistsView:
+		return state.SearchArtists, true
+	case FocusSearchAlbumsView:
+		return state.SearchAlbums, true
+	case FocusSearchPlaylistsView:
+		return state.SearchPlaylists, true
+	}
+	return state.SearchTracks, false
+}
+
+// searchTableColumns returns the columns of the full-screen table of a search category
+func searchTableColumns(focus FocusedModel) []table.Column {
+	centered := lipgloss.NewStyle().Align(lipgloss.Center)
+	number := table.NewColumn("#", "#", 5).WithStyle(centered)
+
+	switch focus {
+	case FocusSearchArtistsView:
+		return []table.Column{
+			number,
+			table.NewFlexColumn("name", "Name", 2),
+			table.NewFlexColumn("genres", "Genres", 3),
+			table.NewColumn("followers", "Followers", 12).WithStyle(centered),
+			table.NewColumn("popularity", "Popularity", 12).WithStyle(centered),
+		}
+	case FocusSearchAlbumsView:
+		return []table.Column{
+			number,
+			table.NewFlexColumn("name", "Name", 1),
+			table.NewFlexColumn("artist", "Artist", 1),
+			table.NewColumn("year", "Year", 6).WithStyle(centered),
+			table.NewColumn("tracks", "Tracks", 8).WithStyle(centered),
+		}
+	case FocusSearchPlaylistsView:
+		return []table.Column{
+			number,
+			table.NewFlexColumn("name", "Name", 2),
+			table.NewFlexColumn("owner", "Owner", 1),
+			table.NewColumn("tracks", "Tracks", 8).WithStyle(centered),
+		}
+	}
+	return []table.Column{
+		number,
+		table.NewFlexColumn("name", "Title", 1),
+		table.NewFlexColumn("artist", "Artist", 1),
+		table.NewFlexColumn("album", "Album", 1),
+		table.NewColumn("duration", "Duration", 8).WithStyle(centered),
+	}
+}
+
+// updateResultsTable fills the full-screen table with the results of the active category
+func (m *searchViewModel) updateResultsTable() {
+	var rows []table.Row
+	switch m.activeList {
+	case FocusSearchTracksView:
+		for i, track := range m.spotifyState.GetSearchResultTracks() {
+			rows = append(rows, table.NewRow(table.RowData{
+				"#":        fmt.Sprintf("%d", i+1),
+				"name":     track.Name,
+				"artist":   allArtistNames(track.SimpleTrack),
+				"album":    track.Album.Name,
+				"duration": formatTrackDuration(int(track.Duration)),
+			}))
+		}
+	case FocusSearchArtistsView:
+		for i, artist := range m.spotifyState.GetSearchResultArtists() {
+			name := artist.Name
+			if m.spotifyState.IsFollowingArtist(artist.ID) {
+				name = libraryMarker + name
+			}
+			rows = append(rows, table.NewRow(table.RowData{
+				"#":          fmt.Sprintf("%d", i+1),
+				"name":       name,
+				"genres":     strings.Join(artist.Genres, ", "),
+				"followers":  fmt.Sprintf("%d", artist.Followers.Count),
+				"popularity": fmt.Sprintf("%d", artist.Popularity),
+			}))
+		}
+	case FocusSearchAlbumsView:
+		for i, album := range m.spotifyState.GetSearchResultAlbums() {
+			name := album.Name
+			if m.spotifyState.IsAlbumSaved(album.ID) {
+				name = libraryMarker + name
+			}
+			year := "--"
+			if len(album.ReleaseDate) >= 4 {
+				year = album.ReleaseDate[:4]
+			}
+			artist := "Unknown Artist"
+			if len(album.Artists) > 0 {
+				artist = album.Artists[0].Name
+			}
+			rows = append(rows, table.NewRow(table.RowData{
+				"#":      fmt.Sprintf("%d", i+1),
+				"name":   name,
+				"artist": artist,
+				"year":   year,
+				"tracks": fmt.Sprintf("%d", album.TotalTracks),
+			}))
+		}
+	case FocusSearchPlaylistsView:
+		for i, playlist := range m.spotifyState.GetSearchResultPlaylists() {
+			name := playlist.Name
+			if m.spotifyState.IsFollowingPlaylist(playlist.ID) {
+				name = libraryMarker + name
+			}
+			rows = append(rows, table.NewRow(table.RowData{
+				"#":      fmt.Sprintf("%d", i+1),
+				"name":   name,
+				"owner":  playlist.Owner.DisplayName,
+				"tracks": fmt.Sprintf("%d", playlist.Tracks.Total),
+			}))
+		}
+	}
+
+	m.resultsTable = m.resultsTable.WithColumns(searchTableColumns(m.activeList)).WithRows(rows)
+	if l := m.activeListModel(); l != nil {
+		m.resultsTable = m.resultsTable.WithHighlightedRow(l.Index())
+	}
+}
+
+// renderResultsTable renders the full-screen table with how many results are loaded in the footer
+func (m searchViewModel) renderResultsTable() string {
+	t := m.resultsTable.Border(RoundedTableBorders).
+		HeaderStyle(lipgloss.NewStyle().BorderForeground(getBorderStyle(m.isFocused))).
+		WithBaseStyle(lipgloss.NewStyle().BorderForeground(getBorderStyle(m.isFocused))).
+		Focused(m.isFocused)
+
+	category, _ := searchCategory(m.activeList)
+	name := map[state.SearchCategory]string{
+		state.SearchTracks:    "Tracks",
+		state.SearchArtists:   "Artists",
+		state.SearchAlbums:    "Albums",
+		state.SearchPlaylists: "Playlists",
+	}[category]
+
+	footer := fmt.Sprintf("%s | %d of %d loaded", name, t.TotalRows(), m.spotifyState.GetSearchResultTotal(category))
+	if m.spotifyState.HasMoreSearchResults(category) {
+		footer += " | more load at the last row"
+	}
+	footer += fmt.Sprintf(" | %s: back to lists", DefaultKeyMap.ExpandSearch.Help().Key)
+
+	return t.WithStaticFooter(lipgloss.NewStyle().Foreground(TextColor).Padding(0, 1).Render(footer)).View()
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -9,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dietzy1/termify/internal/state"
+	"github.com/evertras/bubble-table/table"
 	"github.com/zmb3/spotify/v2"
 )
 
@@ -37,6 +39,10 @@ type searchViewModel struct {
 
 	// Track which list is currently active
 	activeList FocusedModel
+
+	// Show the active category as a full-screen table instead of the four lists
+	expanded     bool
+	resultsTable table.Model
 }
 
 // NewSearchView creates a new search view
@@ -48,6 +54,7 @@ func newSearchView(ctx context.Context, spotifyState *state.SpotifyState) search
 		playlistList: createEmptyList("Playlists"),
 		albumList:    createEmptyList("Albums"),
 		artistList:   createEmptyList("Artists"),
+		resultsTable: newStyledTable(searchTableColumns(FocusSearchTracksView)),
 	}
 
 	return m
@@ -85,16 +92,30 @@ func (m searchViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.updateListStyles(listWidth)
 
-	case state.SearchResultsUpdatedMsg:
+		m.resultsTable = m.resultsTable.WithTargetWidth(m.width).WithMinimumHeight(m.height).WithPageSize(max(m.height-headerFooterHeight, 1))
+
+	case state.SearchResultsUpdatedMsg, state.LibraryUpdatedMsg:
 		m.UpdateSearchResults()
 
 	case tea.KeyMsg:
-		var cmd tea.Cmd
+		if key.Matches(msg, DefaultKeyMap.ExpandSearch) {
+			m.setExpanded(!m.expanded)
+			return m, nil
+		}
+
+		if key.Matches(msg, DefaultKeyMap.Copy) {
+			return m, showCopyDialog(m.selectedCopyItems()...)
+		}
+
+		// Matched before the list sees the key, "f" also pages lists forward
+		if key.Matches(msg, DefaultKeyMap.Follow) {
+			return m, m.toggleFollowSelected()
+		}
+
 		// Update only the active list based on the current focus
+		cmds = append(cmds, m.navigate(msg))
 		switch m.activeList {
 		case FocusSearchTracksView:
-			m.trackList, cmd = m.trackList.Update(msg)
-			cmds = append(cmds, cmd)
 			// If the user presses enter, play the selected track
 			if key.Matches(msg, DefaultKeyMap.Select) {
 				if len(m.trackList.Items()) > 0 {
@@ -118,10 +139,21 @@ func (m searchViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				}
 			}
+			if key.Matches(msg, DefaultKeyMap.PlayNext) {
+				if len(m.trackList.Items()) > 0 {
+					fullTrack := m.spotifyState.GetSearchResultTracks()[m.trackList.Index()]
+					m.spotifyState.Queue.EnqueueNext(fullTrack.SimpleTrack)
+					return m, state.UpdateQueue()
+				}
+			}
+			if key.Matches(msg, DefaultKeyMap.QueueAll) {
+				for _, track := range m.spotifyState.GetSearchResultTracks() {
+					m.spotifyState.Queue.Enqueue(track.SimpleTrack)
+				}
+				return m, state.UpdateQueue()
+			}
 
 		case FocusSearchPlaylistsView:
-			m.playlistList, cmd = m.playlistList.Update(msg)
-			cmds = append(cmds, cmd)
 			// If they press select then we need to open the table view for the selected playlist
 			if key.Matches(msg, DefaultKeyMap.Select) {
 				if len(m.playlistList.Items()) > 0 {
@@ -136,10 +168,14 @@ func (m searchViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, navigateToPlaylistView(playlistID, playlistView)
 				}
 			}
+			if key.Matches(msg, DefaultKeyMap.AddToQueue) {
+				if len(m.playlistList.Items()) > 0 {
+					playlistID := m.spotifyState.GetSearchResultPlaylists()[m.playlistList.Index()].ID
+					return m, queueSource(m.ctx, m.spotifyState, playlistID, playlistView)
+				}
+			}
 
 		case FocusSearchArtistsView:
-			m.artistList, cmd = m.artistList.Update(msg)
-			cmds = append(cmds, cmd)
 			// If user selects an artist, navigate to view their tracks/albums
 			if key.Matches(msg, DefaultKeyMap.Select) {
 				if len(m.artistList.Items()) > 0 {
@@ -153,10 +189,14 @@ func (m searchViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, navigateToPlaylistView(artistID, artistTopTracksView)
 				}
 			}
+			if key.Matches(msg, DefaultKeyMap.AddToQueue) {
+				if len(m.artistList.Items()) > 0 {
+					artistID := m.spotifyState.GetSearchResultArtists()[m.artistList.Index()].ID
+					return m, queueSource(m.ctx, m.spotifyState, artistID, artistTopTracksView)
+				}
+			}
 
 		case FocusSearchAlbumsView:
-			m.albumList, cmd = m.albumList.Update(msg)
-			cmds = append(cmds, cmd)
 			// If user selects an album, navigate to view its tracks
 			if key.Matches(msg, DefaultKeyMap.Select) {
 				if len(m.albumList.Items()) > 0 {
@@ -170,6 +210,12 @@ func (m searchViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, navigateToPlaylistView(albumID, albumTracksView)
 				}
 			}
+			if key.Matches(msg, DefaultKeyMap.AddToQueue) {
+				if len(m.albumList.Items()) > 0 {
+					albumID := m.spotifyState.GetSearchResultAlbums()[m.albumList.Index()].ID
+					return m, queueSource(m.ctx, m.spotifyState, albumID, albumTracksView)
+				}
+			}
 		}
 
 	}
@@ -177,8 +223,82 @@ func (m searchViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// toggleFollowSelected follows or unfollows the selected playlist or artist, or saves or
+// removes the selected album
+func (m *searchViewModel) toggleFollowSelected() tea.Cmd {
+	switch m.activeList {
+	case FocusSearchPlaylistsView:
+		if len(m.playlistList.Items()) > 0 {
+			playlistID := m.spotifyState.GetSearchResultPlaylists()[m.playlistList.Index()].ID
+			return m.spotifyState.ToggleFollowPlaylist(m.ctx, playlistID)
+		}
+	case FocusSearchArtistsView:
+		if len(m.artistList.Items()) > 0 {
+			artistID := m.spotifyState.GetSearchResultArtists()[m.artistList.Index()].ID
+			return m.spotifyState.ToggleFollowArtist(m.ctx, artistID)
+		}
+	case FocusSearchAlbumsView:
+		if len(m.albumList.Items()) > 0 {
+			albumID := m.spotifyState.GetSearchResultAlbums()[m.albumList.Index()].ID
+			return m.spotifyState.ToggleSaveAlbum(m.ctx, albumID)
+		}
+	}
+	return nil
+}
+
+// navigate moves the cursor of the active list, or of the table when expanded, and loads
+// the next page of results once the cursor reaches the last one
+func (m *searchViewModel) navigate(msg tea.KeyMsg) tea.Cmd {
+	l := m.activeListModel()
+	if l == nil {
+		return nil
+	}
+
+	var cmd tea.Cmd
+	if m.expanded {
+		m.resultsTable, cmd = m.resultsTable.Update(msg)
+		// The actions read the list cursor, keep it on the highlighted row
+		l.Select(m.resultsTable.GetHighlightedRowIndex())
+	} else {
+		*l, cmd = l.Update(msg)
+	}
+
+	category, _ := searchCategory(m.activeList)
+	if items := len(l.Items()); items > 0 && l.Index() >= items-1 && m.spotifyState.HasMoreSearchResults(category) {
+		return tea.Batch(cmd, m.spotifyState.SearchMore(m.ctx, category))
+	}
+	return cmd
+}
+
+// activeListModel returns the list of the focused category
+func (m *searchViewModel) activeListModel() *list.Model {
+	switch m.activeList {
+	case FocusSearchTracksView:
+		return &m.trackList
+	case FocusSearchPlaylistsView:
+		return &m.playlistList
+	case FocusSearchArtistsView:
+		return &m.artistList
+	case FocusSearchAlbumsView:
+		return &m.albumList
+	}
+	return nil
+}
+
+// setExpanded switches between the four lists and the full-screen table of the active category
+func (m *searchViewModel) setExpanded(expanded bool) {
+	m.expanded = expanded
+	if expanded {
+		m.updateResultsTable()
+	}
+}
+
 // View renders the search view
 func (m searchViewModel) View() string {
+	if m.expanded {
+		return m.renderResultsTable()
+	}
+
 	// Base style for all lists
 	baseStyle := lipgloss.NewStyle().
 		BorderStyle(lipgloss.NormalBorder()).
@@ -262,6 +382,9 @@ func (m *searchViewModel) UpdateSearchResults() {
 		if playlist.Owner.DisplayName != "" {
 			ownerName = playlist.Owner.DisplayName
 		}
+		if m.spotifyState.IsFollowingPlaylist(playlist.ID) {
+			ownerName = libraryMarker + ownerName
+		}
 
 		playlistItems = append(playlistItems, item{
 			title: playlist.Name,
@@ -276,6 +399,9 @@ func (m *searchViewModel) UpdateSearchResults() {
 		if len(album.Artists) > 0 {
 			artistName = album.Artists[0].Name
 		}
+		if m.spotifyState.IsAlbumSaved(album.ID) {
+			artistName = libraryMarker + artistName
+		}
 		albumItems = append(albumItems, item{
 			title: album.Name,
 			desc:  artistName,
@@ -291,6 +417,9 @@ func (m *searchViewModel) UpdateSearchResults() {
 		if len(artist.Genres) > 0 {
 			desc = artist.Genres[0]
 		}
+		if m.spotifyState.IsFollowingArtist(artist.ID) {
+			desc = libraryMarker + desc
+		}
 		artistItems = append(artistItems, item{
 			title: artist.Name,
 			desc:  desc,
@@ -301,6 +430,24 @@ func (m *searchViewModel) UpdateSearchResults() {
 	m.playlistList.SetItems(playlistItems)
 	m.albumList.SetItems(albumItems)
 	m.artistList.SetItems(artistItems)
+
+	m.trackList.Title = m.listTitle("Tracks", state.SearchTracks, len(trackItems))
+	m.playlistList.Title = m.listTitle("Playlists", state.SearchPlaylists, len(playlistItems))
+	m.albumList.Title = m.listTitle("Albums", state.SearchAlbums, len(albumItems))
+	m.artistList.Title = m.listTitle("Artists", state.SearchArtists, len(artistItems))
+
+	if m.expanded {
+		m.updateResultsTable()
+	}
+}
+
+// listTitle shows how many of the results are loaded next to the category name
+func (m *searchViewModel) listTitle(name string, category state.SearchCategory, loaded int) string {
+	total := m.spotifyState.GetSearchResultTotal(category)
+	if total == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s (%d/%d)", name, loaded, total)
 }
 
 func createEmptyList(title string) list.Model {
@@ -320,79 +467,47 @@ func createEmptyList(title string) list.Model {
 }
 
 func (m *searchViewModel) updateListStyles(itemWidth int) {
-	delegate := list.NewDefaultDelegate()
+	// Update the delegates for all lists
+	m.trackList.SetDelegate(newSearchListDelegate(itemWidth, m.activeList == FocusSearchTracksView))
+	m.playlistList.SetDelegate(newSearchListDelegate(itemWidth, m.activeList == FocusSearchPlaylistsView))
+	m.albumList.SetDelegate(newSearchListDelegate(itemWidth, m.activeList == FocusSearchAlbumsView))
+	m.artistList.SetDelegate(newSearchListDelegate(itemWidth, m.activeList == FocusSearchArtistsView))
+}
 
-	delegate.Styles.NormalTitle = lipgloss.NewStyle().
-		Foreground(WhiteTextColor).
-		Padding(0, 0, 0, 2).
+// newSearchListDelegate creates the list delegate used by the search lists, highlighting the selection when focused
+func newSearchListDelegate(itemWidth int, isFocused bool) list.DefaultDelegate {
+	selectedColor := TextColor
+	if isFocused {
+		selectedColor = PrimaryColor
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(selectedColor).
+		Foreground(selectedColor).
+		Padding(0, 0, 0, 1).
+		Bold(true).
 		Width(itemWidth - 2).
 		MaxWidth(itemWidth - 2)
 
-	delegate.Styles.NormalDesc = delegate.Styles.NormalTitle.
-		Foreground(TextColor).
+	delegate.Styles.SelectedDesc = lipgloss.NewStyle().
+		Foreground(selectedColor).
 		Padding(0, 0, 0, 2).
 		Width(itemWidth - 2).
 		MaxWidth(itemWidth - 2)
 
-	// Create a function to get the appropriate selected style based on focus
-	getSelectedStyle := func(isFocused bool) lipgloss.Style {
-		selectedColor := TextColor
-		if isFocused {
-			selectedColor = PrimaryColor
-		}
-
-		return lipgloss.NewStyle().
-			Border(lipgloss.NormalBorder(), false, false, false, true).
-			BorderForeground(selectedColor).
-			Foreground(selectedColor).
-			Padding(0, 0, 0, 1).
-			Bold(true).
-			Width(itemWidth - 2).
-			MaxWidth(itemWidth - 2)
-	}
-
-	// Create a function to get the appropriate selected description style based on focus
-	getSelectedDescStyle := func(isFocused bool) lipgloss.Style {
-		selectedColor := TextColor
-		if isFocused {
-			selectedColor = PrimaryColor
-		}
-
-		return lipgloss.NewStyle().
-			Foreground(selectedColor).
-			Padding(0, 0, 0, 2).
-			Width(itemWidth - 2).
-			MaxWidth(itemWidth - 2)
-	}
-
-	// Create delegates for each list with appropriate focus state
-	trackDelegate := list.NewDefaultDelegate()
-	trackDelegate.Styles.SelectedTitle = getSelectedStyle(m.activeList == FocusSearchTracksView)
-	trackDelegate.Styles.SelectedDesc = getSelectedDescStyle(m.activeList == FocusSearchTracksView)
-
-	playlistDelegate := list.NewDefaultDelegate()
-	playlistDelegate.Styles.SelectedTitle = getSelectedStyle(m.activeList == FocusSearchPlaylistsView)
-	playlistDelegate.Styles.SelectedDesc = getSelectedDescStyle(m.activeList == FocusSearchPlaylistsView)
-
-	artistDelegate := list.NewDefaultDelegate()
-	artistDelegate.Styles.SelectedTitle = getSelectedStyle(m.activeList == FocusSearchArtistsView)
-	artistDelegate.Styles.SelectedDesc = getSelectedDescStyle(m.activeList == FocusSearchArtistsView)
-
-	albumDelegate := list.NewDefaultDelegate()
-	albumDelegate.Styles.SelectedTitle = getSelectedStyle(m.activeList == FocusSearchAlbumsView)
-	albumDelegate.Styles.SelectedDesc = getSelectedDescStyle(m.activeList == FocusSearchAlbumsView)
-
-	// Update the delegates for all lists
-	m.trackList.SetDelegate(trackDelegate)
-	m.playlistList.SetDelegate(playlistDelegate)
-	m.albumList.SetDelegate(albumDelegate)
-	m.artistList.SetDelegate(artistDelegate)
+	return delegate
 }
 
 // SetActiveList sets which list is currently active based on the focused model
 func (m *searchViewModel) SetActiveList(focusedModel FocusedModel) {
+	changed := m.activeList != focusedModel
 	m.activeList = focusedModel
 	m.updateListStyles(m.width / 2)
+	if changed && m.expanded {
+		m.updateResultsTable()
+	}
 }
 
 // GetNextTrack returns the ID of the next track to play in the active search view
@@ -492,3 +607,30 @@ func (m *searchViewModel) getNextTrackFromList(tracks []spotify.FullTrack) spoti
 	log.Println("Current track not found in search results, returning first track")
 	return tracks[0].ID
 }
+
+// selectedCopyItems returns the result under the cursor of the active list
+func (m searchViewModel) selectedCopyItems() []copyItem {
+	switch m.activeList {
+	case FocusSearchTracksView:
+		if tracks := m.spotifyState.GetSearchResultTracks(); m.trackList.Index() < len(tracks) {
+			track := tracks[m.trackList.Index()]
+			track.SimpleTrack.Album = track.Album
+			return []copyItem{trackCopyItem(track.SimpleTrack)}
+		}
+	case FocusSearchArtistsView:
+		if artists := m.spotifyState.GetSearchResultArtists(); m.artistList.Index() < len(artists) {
+			artist := artists[m.artistList.Index()]
+			return []copyItem{artistCopyItem(artist.ID, artist.Name)}
+		}
+	case FocusSearchAlbumsView:
+		if albums := m.spotifyState.GetSearchResultAlbums(); m.albumList.Index() < len(albums) {
+			return []copyItem{albumCopyItem(albums[m.albumList.Index()])}
+		}
+	case FocusSearchPlaylistsView:
+		if playlists := m.spotifyState.GetSearchResultPlaylists(); m.playlistList.Index() < len(playlists) {
+			playlist := playlists[m.playlistList.Index()]
+			return []copyItem{playlistCopyItem(playlist.ID, playlist.Name)}
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -15,6 +16,20 @@ var _ tea.Model = (*searchbarModel)(nil)
 
 const debounceTime = time.Millisecond * 300
 
+// searchFilters are the field filters understood by the Spotify search, with a usage example
+var searchFilters = []struct {
+	field   string
+	example string
+}{
+	{"artist:", "artist:radiohead"},
+	{"album:", `album:"ok computer"`},
+	{"track:", "track:creep"},
+	{"year:", "year:1997 or year:1990-1999"},
+	{"genre:", "genre:jazz"},
+	{"tag:new", "tag:new finds albums from the past two weeks"},
+	{"tag:hipster", "tag:hipster finds the least popular albums"},
+}
+
 type debouncedSearch struct {
 	searchTerm string
 }
@@ -27,15 +42,29 @@ type searchbarModel struct {
 	searching bool
 	isFocused bool
 
+	history     state.SearchHistory
+	historyPath string
+	recall      int    // Index of the history entry shown in the input, -1 while typing
+	draft       string // What was typed before browsing the history
+
 	spotifyState *state.SpotifyState
 }
 
-func newSearchbar(ctx context.Context, spotifyState *state.SpotifyState) searchbarModel {
+func newSearchbar(ctx context.Context, spotifyState *state.SpotifyState, historyPath string) searchbarModel {
 	ti := textinput.New()
 	ti.Placeholder = "What do you want to play?"
-	ti.CharLimit = 25
+	// Field filters make queries longer than plain keywords, and pasted links longer still
+	ti.CharLimit = linksCharLimit
 	ti.Width = 30
 
+	// Suggestions are completed inline, tab is taken by focus cycling and up and down by the history
+	ti.ShowSuggestions = true
+	ti.KeyMap.AcceptSuggestion.SetEnabled(false)
+	ti.KeyMap.NextSuggestion = key.NewBinding(key.WithKeys("ctrl+n"))
+	ti.KeyMap.PrevSuggestion = key.NewBinding(key.WithKeys("ctrl+p"))
+	ti.CompletionStyle = lipgloss.NewStyle().
+		Foreground(BorderColor)
+
 	// Apply styles
 	ti.PromptStyle = lipgloss.NewStyle().
 		Foreground(PrimaryColor)
@@ -51,6 +80,8 @@ func newSearchbar(ctx context.Context, spotifyState *state.SpotifyState) searchb
 		textInput:    ti,
 		searching:    false,
 		isFocused:    false,
+		historyPath:  historyPath,
+		recall:       -1,
 		spotifyState: spotifyState,
 	}
 }
@@ -65,6 +96,8 @@ func (m *searchbarModel) EnterSearchMode() {
 		return
 	}
 	m.searching = true
+	m.recall = -1
+	m.textInput.SetSuggestions(m.suggestions())
 	m.textInput.Focus()
 }
 
@@ -88,6 +121,10 @@ func (m searchbarModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.textInput.Width = m.width
 
 	case debouncedSearch:
+		// Pasted links are opened with enter instead of searched for
+		if _, ok := state.ParseSpotifyLinks(msg.searchTerm); ok {
+			return m, nil
+		}
 		if m.textInput.Value() == msg.searchTerm {
 			return m, m.spotifyState.SearchEverything(m.ctx, msg.searchTerm)
 		}
@@ -101,13 +138,36 @@ func (m searchbarModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.textInput.SetValue("")
 				m.textInput.Blur()
 				return m, nil
+			case key.Matches(msg, DefaultKeyMap.SearchHistoryOlder):
+				if m.recallHistory(1) {
+					return m, m.debounceSearch()
+				}
+				return m, nil
+			case key.Matches(msg, DefaultKeyMap.SearchHistoryNewer):
+				if m.recallHistory(-1) {
+					return m, m.debounceSearch()
+				}
+				return m, nil
+			case key.Matches(msg, DefaultKeyMap.AcceptSuggestion) && m.acceptSuggestion():
+				return m, m.debounceSearch()
+			case key.Matches(msg, DefaultKeyMap.RemoveSearchHistory):
+				return m, m.removeHistoryEntry()
+			case key.Matches(msg, DefaultKeyMap.ClearSearchHistory):
+				if len(m.history.Queries) == 0 {
+					return m, nil
+				}
+				return m, func() tea.Msg {
+					return ShowDialogWithContentMsg{Content: NewClearSearchHistoryDialog(len(m.history.Queries))}
+				}
 			default:
+				previous := m.textInput.Value()
 				m.textInput, cmd = m.textInput.Update(msg)
-				return m, tea.Sequence(cmd, tea.Tick(debounceTime, func(_ time.Time) tea.Msg {
-					return debouncedSearch{
-						searchTerm: m.textInput.Value(),
-					}
-				}))
+				if m.textInput.Value() == previous {
+					return m, cmd
+				}
+				// Editing a recalled query makes it a new one
+				m.recall = -1
+				return m, tea.Sequence(cmd, m.debounceSearch())
 			}
 		}
 	}
@@ -116,6 +176,16 @@ func (m searchbarModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// debounceSearch searches for the current query unless it changes within the debounce time
+func (m searchbarModel) debounceSearch() tea.Cmd {
+	searchTerm := m.textInput.Value()
+	return tea.Tick(debounceTime, func(_ time.Time) tea.Msg {
+		return debouncedSearch{
+			searchTerm: searchTerm,
+		}
+	})
+}
+
 func (m searchbarModel) View() string {
 	searchStyle := lipgloss.NewStyle().
 		Padding(0, 1).
@@ -128,5 +198,42 @@ func (m searchbarModel) View() string {
 		searchPrefix = "/ "
 	}
 
-	return searchStyle.Render(searchPrefix + m.textInput.View())
+	hint := ""
+	if m.searching {
+		hint = lipgloss.NewStyle().
+			Foreground(TextColor).
+			Italic(true).
+			Render(m.syntaxHint())
+	}
+
+	// Only show the hint when it leaves room to type
+	const minInputWidth = 20
+	ti := m.textInput
+	if hint != "" && m.width-lipgloss.Width(hint)-8 >= minInputWidth {
+		ti.Width = m.width - lipgloss.Width(hint) - 8
+		return searchStyle.Render(searchPrefix + ti.View() + " " + hint)
+	}
+	return searchStyle.Render(searchPrefix + ti.View())
+}
+
+// syntaxHint explains the filter being typed, or lists the filters when no filter is being typed.
+// Pasted links are described instead.
+func (m searchbarModel) syntaxHint() string {
+	value := m.textInput.Value()
+	if links, ok := state.ParseSpotifyLinks(value); ok {
+		return describeLinks(links)
+	}
+	if word := value[strings.LastIndex(value, " ")+1:]; word != "" {
+		for _, filter := range searchFilters {
+			if strings.HasPrefix(filter.field, word) || strings.HasPrefix(word, filter.field) {
+				return filter.example
+			}
+		}
+	}
+
+	fields := make([]string, len(searchFilters))
+	for i, filter := range searchFilters {
+		fields[i] = filter.field
+	}
+	return "Filters: " + strings.Join(fields, " ")
 }
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"slices"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// trackSelection holds the rows marked in a track table. Marks are tracked by the
+// track's index in the loaded tracks of one source, so table rebuilds, filtering and
+// sorting keep the selection. The visual range is kept in row positions and mapped
+// to tracks through the rows currently shown.
+type trackSelection struct {
+	sourceID spotify.ID
+	marked   map[int]bool
+
+	// In visual mode every row between the anchor and the cursor is selected as well
+	visual bool
+	anchor int
+}
+
+func newTrackSelection() trackSelection {
+	return trackSelection{marked: make(map[int]bool)}
+}
+
+// reset drops the selection and binds it to another source
+func (s *trackSelection) reset(sourceID spotify.ID) {
+	s.sourceID = sourceID
+	s.marked = make(map[int]bool)
+	s.visual = false
+}
+
+func (s *trackSelection) toggle(trackIndex int) {
+	if s.marked[trackIndex] {
+		delete(s.marked, trackIndex)
+		return
+	}
+	s.marked[trackIndex] = true
+}
+
+// toggleVisual starts visual mode at the cursor row, or commits the visual range when already active
+func (s *trackSelection) toggleVisual(cursorRow int, rowTracks []int) {
+	if !s.visual {
+		s.visual = true
+		s.anchor = cursorRow
+		return
+	}
+	for _, trackIndex := range s.visualTracks(cursorRow, rowTracks) {
+		s.marked[trackIndex] = true
+	}
+	s.visual = false
+}
+
+func (s trackSelection) contains(trackIndex, row, cursorRow int) bool {
+	if s.marked[trackIndex] {
+		return true
+	}
+	return s.visual && row >= min(s.anchor, cursorRow) && row <= max(s.anchor, cursorRow)
+}
+
+// active reports whether bulk actions should apply to the selection instead of the highlighted row
+func (s trackSelection) active() bool {
+	return s.visual || len(s.marked) > 0
+}
+
+// indices returns the selected track indices in track order
+func (s trackSelection) indices(cursorRow int, rowTracks []int) []int {
+	indices := make([]int, 0, len(s.marked))
+	for i := range s.marked {
+		indices = append(indices, i)
+	}
+	for _, trackIndex := range s.visualTracks(cursorRow, rowTracks) {
+		if !s.marked[trackIndex] {
+			indices = append(indices, trackIndex)
+		}
+	}
+	slices.Sort(indices)
+	return indices
+}
+
+// visualTracks maps the rows of the visual range to tracks, skipping rows that are still loading
+func (s trackSelection) visualTracks(cursorRow int, rowTracks []int) []int {
+	if !s.visual {
+		return nil
+	}
+	var tracks []int
+	for row := max(min(s.anchor, cursorRow), 0); row <= max(s.anchor, cursorRow) && row < len(rowTracks); row++ {
+		if rowTracks[row] >= 0 {
+			tracks = append(tracks, rowTracks[row])
+		}
+	}
+	return tracks
+}
+
+// trackIDs returns the IDs of the tracks, skipping local files and unavailable tracks
+func trackIDs(tracks []spotify.SimpleTrack) []spotify.ID {
+	ids := make([]spotify.ID, 0, len(tracks))
+	for _, track := range tracks {
+		if track.ID != "" {
+			ids = append(ids, track.ID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/zmb3/spotify/v2"
+)
+
+// How often the session is written to disk while running, so a crash loses little
+const sessionSaveInterval = 30 * time.Second
+
+type sessionSaveTickMsg struct{}
+
+func scheduleSessionSave() tea.Cmd {
+	return tea.Tick(sessionSaveInterval, func(_ time.Time) tea.Msg {
+		return sessionSaveTickMsg{}
+	})
+}
+
+// tablePosition is a saved page and cursor waiting for its tracks to load
+type tablePosition struct {
+	sourceID spotify.ID
+	page     int
+	cursor   int
+}
+
+// Focus names stored in the session, only the views that are always available are restored
+var sessionFocusNames = map[FocusedModel]string{
+	FocusLibrary:      "library",
+	FocusPlaylistView: "playlist",
+}
+
+// tableViewKind returns the source kind string used by the state package for a table view
+func tableViewKind(view tableView) string {
+	switch view {
+	case artistTopTracksView:
+		return "artist"
+	case albumTracksView:
+		return "album"
+	default:
+		return "playlist"
+	}
+}
+
+// snapshotSession captures the queue, the open table view and the current playback
+func (m applicationModel) snapshotSession() state.Session {
+	session := state.Session{
+		Queue:        m.spotifyState.Queue.List(),
+		SelectedID:   m.spotifyState.GetSelectedID(),
+		SelectedKind: tableViewKind(m.playlistView.viewType),
+		Focus:        sessionFocusNames[m.focusedModel],
+		TablePage:    m.playlistView.table.CurrentPage(),
+		TableCursor:  m.playlistView.table.GetHighlightedRowIndex(),
+		SavedAt:      time.Now(),
+	}
+
+	// The daemon saves its own queue, keep the one from running without it
+	if m.daemon != nil {
+		session.Queue = nil
+		if m.restoredSession != nil {
+			session.Queue = m.restoredSession.Queue
+		}
+	}
+
+	playerState := m.spotifyState.GetPlayerState()
+	if playerState.Item != nil {
+		session.ContextURI = playerState.PlaybackContext.URI
+		session.TrackID = playerState.Item.ID
+		session.TrackName = playerState.Item.Name
+		if len(playerState.Item.Artists) > 0 {
+			session.ArtistName = playerState.Item.Artists[0].Name
+		}
+		session.ProgressMs = m.audioPlayer.progress * 1000
+	}
+
+	// Keep the previous track when nothing has played yet so the prompt isn't lost
+	if session.TrackID == "" && m.restoredSession != nil {
+		session.ContextURI = m.restoredSession.ContextURI
+		session.TrackID = m.restoredSession.TrackID
+		session.TrackName = m.restoredSession.TrackName
+		session.ArtistName = m.restoredSession.ArtistName
+		session.ProgressMs = m.restoredSession.ProgressMs
+	}
+
+	return session
+}
+
+// saveSession writes the session synchronously, used when quitting
+func (m applicationModel) saveSession() {
+	if m.sessionPath == "" {
+		return
+	}
+	if err := state.SaveSession(m.sessionPath, m.snapshotSession()); err != nil {
+		log.Printf("Application: Failed to save session: %v", err)
+		return
+	}
+	log.Println("Application: Session saved")
+}
+
+// saveSessionAsync snapshots the session on the update loop and writes it in the background
+func (m applicationModel) saveSessionAsync() tea.Cmd {
+	if m.sessionPath == "" {
+		return nil
+	}
+	path, session := m.sessionPath, m.snapshotSession()
+	return func() tea.Msg {
+		if err := state.SaveSession(path, session); err != nil {
+			log.Printf("Application: Failed to save session: %v", err)
+		}
+		return nil
+	}
+}
+
+// restoreSession puts the saved queue back and prepares the table position to be restored once loaded
+func (m *applicationModel) restoreSession(session *state.Session) {
+	m.restoredSession = session
+	if m.daemon == nil {
+		m.spotifyState.Queue.EnqueueAll(session.Queue)
+	}
+	m.navbar.queueCount = m.spotifyState.Queue.Size()
+
+	for focus, name := range sessionFocusNames {
+		if name == session.Focus {
+			m.focusedModel = focus
+		}
+	}
+
+	if session.SelectedID != "" {
+		m.playlistView.restorePosition = &tablePosition{
+			sourceID: session.SelectedID,
+			page:     session.TablePage,
+			cursor:   session.TableCursor,
+		}
+	}
+	log.Printf("Application: Restored session from %s with %d queued tracks", session.SavedAt.Format(time.RFC3339), len(session.Queue))
+}
+
+// resumeDialogContent asks whether to continue playing where the last session left off
+type resumeDialogContent struct {
+	ctx          context.Context
+	width        int
+	height       int
+	spotifyState *state.SpotifyState
+	session      state.Session
+}
+
+func NewResumeDialog(ctx context.Context, spotifyState *state.SpotifyState, session state.Session) DialogContent {
+	return &resumeDialogContent{
+		ctx:          ctx,
+		spotifyState: spotifyState,
+		session:      session,
+	}
+}
+
+func (m *resumeDialogContent) Init() tea.Cmd {
+	return nil
+}
+
+func (m *resumeDialogContent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return m, nil
+}
+
+func (m *resumeDialogContent) View() string {
+	const width = 40
+
+	trackName := m.session.TrackName
+	if trackName == "" {
+		trackName = string(m.session.TrackID)
+	}
+
+	rows := []string{
+		lipgloss.NewStyle().
+			Foreground(WhiteTextColor).
+			Bold(true).
+			Width(width).
+			Align(lipgloss.Center).
+			Render(trackName),
+	}
+	if m.session.ArtistName != "" {
+		rows = append(rows, lipgloss.NewStyle().
+			Foreground(TextColor).
+			Width(width).
+			Align(lipgloss.Center).
+			Render(m.session.ArtistName))
+	}
+	rows = append(rows, lipgloss.NewStyle().
+		Foreground(TextColor).
+		Italic(true).
+		Width(width).
+		Align(lipgloss.Center).
+		MarginTop(1).
+		Render(fmt.Sprintf("at %s, last played %s", formatDuration(m.session.ProgressMs/1000), m.session.SavedAt.Format("Jan 2 15:04"))))
+
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m *resumeDialogContent) GetTitle() string {
+	return "Resume Playback?"
+}
+
+func (m *resumeDialogContent) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m *resumeDialogContent) HandleDialogKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	return false, nil
+}
+
+func (m *resumeDialogContent) GetActions() []DialogAction {
+	return []DialogAction{
+		{
+			Label: "Resume",
+			Key:   key.NewBinding(key.WithKeys("enter")),
+			Cmd:   m.spotifyState.ResumePlayback(m.ctx, m.session),
+		},
+		{
+			Label: "Not now",
+			Key:   key.NewBinding(key.WithKeys("esc")),
+			Cmd: func() tea.Msg {
+				return DialogMsg{Accepted: false}
+			},
+		},
+	}
+}
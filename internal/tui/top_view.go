@@ -0,0 +1,315 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dietzy1/termify/internal/state"
+	"github.com/evertras/bubble-table/table"
+	"github.com/zmb3/spotify/v2"
+)
+
+var _ tea.Model = (*topViewModel)(nil)
+
+var timeRangeLabels = map[spotify.Range]string{
+	spotify.ShortTermRange:  "Last 4 weeks",
+	spotify.MediumTermRange: "Last 6 months",
+	spotify.LongTermRange:   "All time",
+}
+
+// topViewModel shows the user's top artists next to their top tracks for a time range
+type topViewModel struct {
+	ctx           context.Context
+	width, height int
+	isFocused     bool
+	spotifyState  *state.SpotifyState
+
+	timeRange  spotify.Range
+	activePane FocusedModel
+
+	artistList list.Model
+	trackTable table.Model
+}
+
+func newTopView(ctx context.Context, spotifyState *state.SpotifyState) topViewModel {
+	return topViewModel{
+		ctx:          ctx,
+		spotifyState: spotifyState,
+		timeRange:    spotify.ShortTermRange,
+		activePane:   FocusTopTracksView,
+		artistList:   createEmptyList("Top Artists"),
+		trackTable:   createTrackTable(defaultTrackColumns),
+	}
+}
+
+func (m topViewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m topViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+		listWidth := m.listWidth()
+		m.artistList.SetSize(listWidth, max(m.height-2, 0))
+		m.artistList.Styles.NoItems = lipgloss.NewStyle().Width(listWidth-2).Padding(0, 0, 0, 2)
+		m.artistList.SetDelegate(newSearchListDelegate(listWidth, m.activePane == FocusTopArtistsView))
+
+		tableWidth := m.width - listWidth - 2
+		const minTableHeight = 7
+		// This check is a panic safeguard
+		if m.height-minTableHeight < 0 {
+			m.trackTable = m.trackTable.WithTargetWidth(tableWidth).WithMinimumHeight(m.height).WithPageSize(1)
+			return m, nil
+		}
+		m.trackTable = m.trackTable.WithTargetWidth(tableWidth).WithMinimumHeight(m.height).WithPageSize(m.height - headerFooterHeight)
+		return m, nil
+
+	case state.TopItemsUpdatedMsg:
+		if msg.TimeRange == m.timeRange {
+			m.updateTopItems()
+		}
+		return m, nil
+
+	case state.LibraryUpdatedMsg:
+		m.updateTopItems()
+		return m, nil
+
+	case tea.KeyMsg:
+		if key.Matches(msg, DefaultKeyMap.TimeRange) {
+			m.timeRange = nextTimeRange(m.timeRange)
+			log.Printf("TopView: Switched time range to %s", m.timeRange)
+			m.updateTopItems()
+			return m, m.spotifyState.FetchTopItems(m.ctx, m.timeRange)
+		}
+
+		switch m.activePane {
+		case FocusTopArtistsView:
+			if key.Matches(msg, DefaultKeyMap.Select) {
+				artists := m.spotifyState.GetTopArtists(m.timeRange)
+				index := m.artistList.Index()
+				if index < 0 || index >= len(artists) {
+					return m, nil
+				}
+				artistID := artists[index].ID
+				log.Println("Opening artist view for: ", artistID)
+				m.spotifyState.SetSelectedID(artistID)
+				return m, navigateToPlaylistView(artistID, artistTopTracksView)
+			}
+			if key.Matches(msg, DefaultKeyMap.AddToQueue) {
+				artists := m.spotifyState.GetTopArtists(m.timeRange)
+				index := m.artistList.Index()
+				if index < 0 || index >= len(artists) {
+					return m, nil
+				}
+				return m, queueSource(m.ctx, m.spotifyState, artists[index].ID, artistTopTracksView)
+			}
+			if key.Matches(msg, DefaultKeyMap.Follow) {
+				artists := m.spotifyState.GetTopArtists(m.timeRange)
+				index := m.artistList.Index()
+				if index < 0 || index >= len(artists) {
+					return m, nil
+				}
+				return m, m.spotifyState.ToggleFollowArtist(m.ctx, artists[index].ID)
+			}
+			if key.Matches(msg, DefaultKeyMap.Copy) {
+				artists := m.spotifyState.GetTopArtists(m.timeRange)
+				index := m.artistList.Index()
+				if index < 0 || index >= len(artists) {
+					return m, nil
+				}
+				return m, showCopyDialog(artistCopyItem(artists[index].ID, artists[index].Name))
+			}
+			m.artistList, cmd = m.artistList.Update(msg)
+			return m, cmd
+
+		case FocusTopTracksView:
+			switch {
+			case key.Matches(msg, DefaultKeyMap.Select):
+				if track := m.getSelectedTrack(); track != nil {
+					log.Printf("TopView: Playing track: %s", track.ID)
+					return m, m.spotifyState.PlayTrack(m.ctx, track.ID)
+				}
+				return m, nil
+
+			case key.Matches(msg, DefaultKeyMap.AddToQueue):
+				if track := m.getSelectedTrack(); track != nil {
+					log.Printf("TopView: Adding track to queue: %s", track.ID)
+					simpleTrack := track.SimpleTrack
+					simpleTrack.Album = track.Album
+					m.spotifyState.Queue.Enqueue(simpleTrack)
+					return m, state.UpdateQueue()
+				}
+				return m, nil
+
+			case key.Matches(msg, DefaultKeyMap.QueueAll):
+				for _, track := range m.spotifyState.GetTopTracks(m.timeRange) {
+					simpleTrack := track.SimpleTrack
+					simpleTrack.Album = track.Album
+					m.spotifyState.Queue.Enqueue(simpleTrack)
+				}
+				return m, state.UpdateQueue()
+
+			case key.Matches(msg, DefaultKeyMap.Copy):
+				if track := m.getSelectedTrack(); track != nil {
+					simpleTrack := track.SimpleTrack
+					simpleTrack.Album = track.Album
+					return m, showCopyDialog(trackCopyItem(simpleTrack))
+				}
+				return m, nil
+
+			case key.Matches(msg, DefaultKeyMap.PlayNext):
+				if track := m.getSelectedTrack(); track != nil {
+					log.Printf("TopView: Playing track next: %s", track.ID)
+					simpleTrack := track.SimpleTrack
+					simpleTrack.Album = track.Album
+					m.spotifyState.Queue.EnqueueNext(simpleTrack)
+					return m, state.UpdateQueue()
+				}
+				return m, nil
+			}
+			m.trackTable, cmd = m.trackTable.Update(msg)
+			return m, cmd
+		}
+	}
+
+	return m, nil
+}
+
+func (m topViewModel) View() string {
+	tracksFocused := m.isFocused && m.activePane == FocusTopTracksView
+	artistsFocused := m.isFocused && m.activePane == FocusTopArtistsView
+
+	listStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(getBorderStyle(artistsFocused))
+
+	m.trackTable = m.trackTable.Border(RoundedTableBorders).
+		HeaderStyle(
+			lipgloss.NewStyle().
+				BorderForeground(getBorderStyle(tracksFocused)))
+	m.trackTable = m.trackTable.WithBaseStyle(
+		lipgloss.NewStyle().BorderForeground(getBorderStyle(tracksFocused)),
+	)
+	m.trackTable = m.trackTable.Focused(tracksFocused)
+
+	styledName := lipgloss.NewStyle().
+		Foreground(TextColor).
+		Padding(0, 1).
+		Render("Top Tracks • " + timeRangeLabels[m.timeRange])
+
+	styledPage := lipgloss.NewStyle().
+		Foreground(WhiteTextColor).
+		Padding(0, 1).
+		Render(fmt.Sprintf("| Page %d/%d", m.trackTable.CurrentPage(), m.trackTable.MaxPages()))
+
+	m.trackTable = m.trackTable.WithStaticFooter(styledName + styledPage)
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		listStyle.Render(m.artistList.View()),
+		m.trackTable.View(),
+	)
+}
+
+// SetActivePane switches between the artist list and the track table
+func (m *topViewModel) SetActivePane(focusedModel FocusedModel) {
+	m.activePane = focusedModel
+	m.artistList.SetDelegate(newSearchListDelegate(m.listWidth(), m.activePane == FocusTopArtistsView))
+}
+
+func (m *topViewModel) listWidth() int {
+	return m.width / 3
+}
+
+func (m *topViewModel) updateTopItems() {
+	artists := m.spotifyState.GetTopArtists(m.timeRange)
+	artistItems := make([]list.Item, 0, len(artists))
+	for _, artist := range artists {
+		desc := "Artist"
+		if len(artist.Genres) > 0 {
+			desc = artist.Genres[0]
+		}
+		if m.spotifyState.IsFollowingArtist(artist.ID) {
+			desc = libraryMarker + desc
+		}
+		artistItems = append(artistItems, item{
+			title: artist.Name,
+			desc:  desc,
+		})
+	}
+	m.artistList.SetItems(artistItems)
+	m.artistList.Title = "Top Artists • " + timeRangeLabels[m.timeRange]
+
+	tracks := m.spotifyState.GetTopTracks(m.timeRange)
+	rows := make([]table.Row, 0, len(tracks))
+	for i, track := range tracks {
+		simpleTrack := track.SimpleTrack
+		simpleTrack.Album = track.Album
+		rows = append(rows, newTrackRow(simpleTrack, strconv.Itoa(i+1), track.Name))
+	}
+	m.trackTable = m.trackTable.WithRows(rows)
+}
+
+func (m *topViewModel) getSelectedTrack() *spotify.FullTrack {
+	selected := m.trackTable.HighlightedRow()
+	if selected.Data == nil {
+		return nil
+	}
+
+	numStr, ok := selected.Data["#"].(string)
+	if !ok {
+		return nil
+	}
+
+	tracks := m.spotifyState.GetTopTracks(m.timeRange)
+	idx, err := strconv.Atoi(numStr)
+	if err != nil || idx <= 0 || idx > len(tracks) {
+		return nil
+	}
+	return &tracks[idx-1]
+}
+
+// getNextTrack returns the top track following the currently playing one
+func (m *topViewModel) getNextTrack() spotify.ID {
+	tracks := m.spotifyState.GetTopTracks(m.timeRange)
+	if len(tracks) == 0 {
+		log.Println("No top tracks to autoplay")
+		return ""
+	}
+
+	playerState := m.spotifyState.GetPlayerState()
+	if playerState.Item == nil {
+		return tracks[0].ID
+	}
+
+	for i, track := range tracks {
+		if track.ID == playerState.Item.ID {
+			if i >= len(tracks)-1 {
+				log.Println("Current track is the last one in top tracks")
+				return ""
+			}
+			return tracks[i+1].ID
+		}
+	}
+
+	return tracks[0].ID
+}
+
+func nextTimeRange(current spotify.Range) spotify.Range {
+	for i, r := range state.TopTimeRanges {
+		if r == current {
+			return state.TopTimeRanges[(i+1)%len(state.TopTimeRanges)]
+		}
+	}
+	return state.TopTimeRanges[0]
+}
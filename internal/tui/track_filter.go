@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+	"github.com/zmb3/spotify/v2"
+)
+
+// trackFilter is the fuzzy filter prompt of a track table
+type trackFilter struct {
+	input  textinput.Model
+	typing bool // The prompt has focus and receives every key
+
+	// Set while every remaining page is fetched so the filter can search the full listing
+	loadingAll bool
+}
+
+func newTrackFilter() trackFilter {
+	ti := textinput.New()
+	ti.Placeholder = "Filter by title, artist or album"
+	ti.CharLimit = 100
+	ti.Prompt = "Filter › "
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(PrimaryColor)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(WhiteTextColor)
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(TextColor)
+	// Blink messages are only routed to the search bar
+	ti.Cursor.SetMode(cursor.CursorStatic)
+	return trackFilter{input: ti}
+}
+
+func (f trackFilter) query() string {
+	return strings.TrimSpace(f.input.Value())
+}
+
+// active reports whether the prompt is shown, either while typing or with a query applied
+func (f trackFilter) active() bool {
+	return f.typing || f.query() != ""
+}
+
+// trackMatch is a track that matched the filter, with the matched character offsets per column
+type trackMatch struct {
+	index  int
+	title  []int
+	artist []int
+	album  []int
+}
+
+// The columns are matched as one string so a query can span them, e.g. "artist song"
+const filterFieldSeparator = " / "
+
+// matchTracks fuzzy matches the query against each track's title, first artist and album, best matches first
+func matchTracks(query string, tracks []spotify.SimpleTrack) []trackMatch {
+	// Spaces separate words in the query but the matcher expects one pattern
+	pattern := strings.ReplaceAll(query, " ", "")
+
+	fields := make([][3]string, len(tracks))
+	data := make([]string, len(tracks))
+	for i, track := range tracks {
+		fields[i] = [3]string{track.Name, "", track.Album.Name}
+		if len(track.Artists) > 0 {
+			fields[i][1] = track.Artists[0].Name
+		}
+		data[i] = strings.Join(fields[i][:], filterFieldSeparator)
+	}
+
+	results := fuzzy.Find(pattern, data)
+	matches := make([]trackMatch, 0, len(results))
+	for _, result := range results {
+		match := trackMatch{index: result.Index}
+		f := fields[result.Index]
+		artistStart := len(f[0]) + len(filterFieldSeparator)
+		albumStart := artistStart + len(f[1]) + len(filterFieldSeparator)
+		for _, offset := range result.MatchedIndexes {
+			switch {
+			case offset < len(f[0]):
+				match.title = append(match.title, offset)
+			case offset >= artistStart && offset < artistStart+len(f[1]):
+				match.artist = append(match.artist, offset-artistStart)
+			case offset >= albumStart:
+				match.album = append(match.album, offset-albumStart)
+			}
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// highlightMatches renders the matched byte offsets of text in the primary color
+func highlightMatches(text string, offsets []int) string {
+	if len(offsets) == 0 {
+		return text
+	}
+
+	matchStyle := lipgloss.NewStyle().Foreground(PrimaryColor).Bold(true).Underline(true)
+
+	var b strings.Builder
+	next := 0
+	for i, r := range text {
+		if next < len(offsets) && offsets[next] == i {
+			b.WriteString(matchStyle.Render(string(r)))
+			next++
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
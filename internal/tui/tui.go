@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dietzy1/termify/internal/authentication"
 	"github.com/dietzy1/termify/internal/config"
+	"github.com/dietzy1/termify/internal/control"
+	"github.com/dietzy1/termify/internal/services"
 	"github.com/zmb3/spotify/v2"
 	"golang.org/x/oauth2"
 )
@@ -35,18 +38,27 @@ const (
 type model struct {
 	width, height int
 	state         tuiState
+	config        *config.Config
 
 	authModel        authModel
 	applicationModel applicationModel
 	tokenStorer      tokenStorer
+
+	// Attached TUIs use the daemon's login, which they don't save
+	attached bool
+	err      error // Why the TUI quit, if it couldn't run
 }
 
 func (m model) Init() tea.Cmd {
 	switch m.state {
 	case authenticating:
+		login := m.authModel.Init()
+		if m.attached {
+			login = daemonLogin(m.authModel.ctx, m.config.GetControlSocketPath())
+		}
 		return tea.Batch(
 			tea.SetWindowTitle("Authenticating"),
-			m.authModel.Init(),
+			login,
 		)
 	case application:
 		return tea.Batch(
@@ -57,16 +69,25 @@ func (m model) Init() tea.Cmd {
 	return nil
 }
 
-func Run(ctx context.Context, c *config.Config, authenticator authenticator, tokenStorer tokenStorer) error {
+// Run runs the TUI. When attached, the TUI is a client of the daemon listening on the control
+// socket, which owns the queue and autoplay.
+func Run(ctx context.Context, c *config.Config, authenticator authenticator, tokenStorer tokenStorer, events *control.Broker, attached bool) error {
+
+	var daemon *daemonLink
+	if attached {
+		daemon = &daemonLink{socketPath: c.GetControlSocketPath()}
+	}
 
 	m := model{
 		state:            authenticating,
+		config:           c,
 		authModel:        newAuthModel(ctx, c, authenticator),
-		applicationModel: newApplication(ctx, nil),
+		applicationModel: newApplication(ctx, c, nil, events, daemon),
 		tokenStorer:      tokenStorer,
+		attached:         attached,
 	}
 
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithOutput(terminal))
 	authenticator.SetTeaProgram(p)
 
 	// Handle context cancellation
@@ -77,11 +98,32 @@ func Run(ctx context.Context, c *config.Config, authenticator authenticator, tok
 		p.Quit()
 	}()
 
-	if _, err := p.Run(); err != nil {
-		return fmt.Errorf("error running program: %w", err)
+	// The daemon serves these for attached TUIs
+	if !attached {
+		serveCtx, stopServing := context.WithCancel(ctx)
+		var serving sync.WaitGroup
+		defer func() {
+			stopServing()
+			serving.Wait()
+		}()
+
+		// Accept commands from `termify ctl` and scripts while the program runs
+		if c.IsControlEnabled() {
+			if server, err := control.Listen(c.GetControlSocketPath(), events); err != nil {
+				log.Printf("Control socket disabled: %v", err)
+			} else {
+				serving.Go(func() { server.Serve(serveCtx, p) })
+			}
+		}
+
+		services.Start(serveCtx, c, events, p, &serving)
 	}
 
-	return nil
+	final, err := p.Run()
+	if err != nil {
+		return fmt.Errorf("error running program: %w", err)
+	}
+	return final.(model).err
 }
 
 func transitionToApplication(m model, spotifyClient *spotify.Client) model {
@@ -89,9 +131,11 @@ func transitionToApplication(m model, spotifyClient *spotify.Client) model {
 		width:            m.width,
 		height:           m.height,
 		state:            application,
+		config:           m.config,
 		authModel:        m.authModel,
-		applicationModel: newApplication(m.applicationModel.ctx, spotifyClient),
+		applicationModel: newApplication(m.applicationModel.ctx, m.config, spotifyClient, m.applicationModel.events, m.applicationModel.daemon),
 		tokenStorer:      m.tokenStorer,
+		attached:         m.attached,
 	}
 }
 
@@ -105,6 +149,20 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		updatedModel := transitionToApplication(m, msg.Client)
 		return updatedModel, updatedModel.Init()
+	case daemonLoginFailedMsg:
+		log.Printf("Parent model (tui.go): The daemon's login is unavailable: %v", msg.err)
+		m.err = fmt.Errorf("can't attach to the daemon: %w. Log in with the URL `termify daemon` printed, or stop the daemon to run Termify on its own", msg.err)
+		return m, tea.Quit
+	case authentication.AuthExpiredMsg:
+		// The login may expire before or after the application started
+		if m.applicationModel.events != nil {
+			m.applicationModel.events.Publish(control.Event{Type: control.EventAuthExpired})
+		}
+	case control.CommandMsg:
+		if m.state != application {
+			msg.Reply(control.Response{Error: "not logged in to Spotify yet"})
+			return m, nil
+		}
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, DefaultKeyMap.Quit):
@@ -140,8 +198,9 @@ func (m model) View() string {
 
 func (m model) handleProgramQuit() (tea.Model, tea.Cmd) {
 	log.Println("Parent model (tui.go): Received quit command. Quitting program.")
+	// An attached TUI only has the daemon's access token, saving it would lose the refresh token
 	oathToken := m.applicationModel.spotifyState.GetOathToken()
-	if oathToken != nil {
+	if oathToken != nil && !m.attached {
 		log.Println("Parent model (tui.go): Saving token before quitting.")
 		if err := m.tokenStorer.SaveToken(oathToken); err != nil {
 			log.Printf("Parent model (tui.go): Failed to save token: %v", err)
@@ -149,6 +208,9 @@ func (m model) handleProgramQuit() (tea.Model, tea.Cmd) {
 			log.Println("Parent model (tui.go): Token saved successfully.")
 		}
 	}
+	if m.state == application {
+		m.applicationModel.saveSession()
+	}
 	// Sidenote we should potentially cancel the context here also so API calls do not hang
 	return m, tea.Quit
 }
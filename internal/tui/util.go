@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"log"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -40,6 +41,22 @@ func formatTrackDuration(ms int) string {
 	return fmt.Sprintf("%d:%02d", minutes, remainingSeconds)
 }
 
+// formatTimeAgo formats a timestamp relative to now, falling back to a date for older entries
+func formatTimeAgo(t time.Time) string {
+	elapsed := time.Since(t)
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm ago", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(elapsed.Hours()))
+	case elapsed < 7*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(elapsed.Hours()/24))
+	}
+	return t.Local().Format("Jan 2")
+}
+
 func safelyRenderError(err error) string {
 	if err == nil {
 		return ""
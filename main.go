@@ -4,16 +4,36 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"runtime"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dietzy1/termify/internal/authentication"
 	"github.com/dietzy1/termify/internal/config"
+	"github.com/dietzy1/termify/internal/control"
+	"github.com/dietzy1/termify/internal/daemon"
+	"github.com/dietzy1/termify/internal/nowplaying"
+	"github.com/dietzy1/termify/internal/state"
 	"github.com/dietzy1/termify/internal/tui"
 )
 
 func main() {
+	// `termify ctl` talks to a running instance and exits
+	if len(os.Args) > 1 && os.Args[1] == "ctl" {
+		os.Exit(control.RunCLI(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	// `termify status` prints the now playing line once and exits
+	if len(os.Args) > 1 && os.Args[1] == "status" {
+		os.Exit(nowplaying.RunCLI(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	// `termify daemon` runs playback headless for TUIs to attach to, the other arguments are flags
+	daemonMode := len(os.Args) > 1 && os.Args[1] == "daemon"
+	if daemonMode {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+
 	// Set up context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -24,6 +44,16 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Check the launch arguments before taking over the terminal
+	for _, item := range config.GetLaunchItems() {
+		if _, ok := state.ParseSpotifyLink(item); !ok {
+			log.Fatalf("Invalid argument %q: expected an open.spotify.com URL or a spotify: URI", item)
+		}
+	}
+	if _, err := nowplaying.ParseTemplate(config.GetNowPlayingFormat()); err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	// Set up logging if enabled
 	if config.IsLoggingEnabled() {
 		logPath := config.GetLogFilePath()
@@ -50,20 +80,28 @@ func main() {
 		log.Fatalf("Failed to create auth service: %v", err)
 	}
 
-	// Setup authentication server
-	authServer := authentication.NewServer(config, authService)
-	if err != nil {
-		log.Fatalf("Failed to create authentication server: %v", err)
+	// A running daemon owns playback and serves the API, so the TUI attaches to it instead
+	attached := !daemonMode && config.IsControlEnabled() && control.DaemonRunning(config.GetControlSocketPath())
+	if attached {
+		log.Println("Attaching to the running daemon")
 	}
 
+	// Player and queue changes pushed to HTTP API clients
+	var events *control.Broker
 	var authServiceErr error
-	go func() {
-		if err := authServer.ListenAndServe(ctx); err != nil && ctx.Err() == nil {
-			log.Printf("Server error: %v", err)
-			authServiceErr = fmt.Errorf("authentication server error: %w", err)
-			cancel()
-		}
-	}()
+	if !attached {
+		events = control.NewBroker()
+
+		// Setup authentication server
+		authServer := authentication.NewServer(config, authService, events)
+		go func() {
+			if err := authServer.ListenAndServe(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Server error: %v", err)
+				authServiceErr = fmt.Errorf("authentication server error: %w", err)
+				cancel()
+			}
+		}()
+	}
 
 	go func() {
 		ticker := time.NewTicker(30 * time.Second)
@@ -82,9 +120,16 @@ func main() {
 		}
 	}()
 
-	// Run TUI - this will block until TUI exits
-	if err := tui.Run(ctx, config, authService, authService); err != nil {
+	if daemonMode {
+		if err := daemon.Run(ctx, config, authService, authService, events); err != nil {
+			fmt.Fprintln(os.Stderr, "termify daemon:", err)
+			os.Exit(1)
+		}
+	} else if err := tui.Run(ctx, config, authService, authService, events, attached); err != nil {
+		// Run TUI - this will block until TUI exits
 		log.Printf("TUI error: %v", err)
+		fmt.Fprintln(os.Stderr, "termify:", err)
+		os.Exit(1)
 	}
 
 	if authServiceErr != nil {